@@ -0,0 +1,43 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+
+	"github.com/spf13/cobra"
+)
+
+// beginCmd represents the begin command
+var beginCmd = &cobra.Command{
+	Use:   "begin",
+	Short: "Start a transaction",
+	Long: `Start a transaction. Until commit or rollback, the changes made by
+mkdir, create, append, mv, cp, rm, trash and ln are visible only in this session.
+cat and ls see the changes, the other commands don't.
+
+Examples:
+begin
+mkdir /app
+append /app/config "key=value"
+commit`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("invalid argument")
+		}
+		req := &fsservice.Request{
+			Request: &fsservice.Request_Begin{
+				Begin: &fsservice.BeginRequest{},
+			},
+		}
+		fsclient.Session.DoRequest(req, fsclient.Session.Begin, noop)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(beginCmd)
+}
@@ -13,29 +13,32 @@ import (
 
 // readCmd represents the read command
 var catCmd = &cobra.Command{
-	Use:   "cat [FILE]",
+	Use:   "cat [FILE]...",
 	Short: "Print files",
 	Long: `Print the contents of a file to the standard output.
-Supports absolute and relative paths.
+Supports absolute and relative paths and wildcards.
 
 Examples:
 cat file1
 cat /dir1/file1
 cat dir1/file1
+cat dir1/*.txt
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) != 1 {
+		if len(args) == 0 {
 			return fmt.Errorf("invalid argument")
 		}
 
-		req := &fsservice.Request{
-			Request: &fsservice.Request_ReadAll{
-				ReadAll: &fsservice.ReadAllRequest{
-					Path: args[0],
+		for _, path := range args {
+			req := &fsservice.Request{
+				Request: &fsservice.Request_ReadAll{
+					ReadAll: &fsservice.ReadAllRequest{
+						Path: path,
+					},
 				},
-			},
+			}
+			fsclient.Session.DoRequest(req, fsclient.Session.ReadAll, printReadAll)
 		}
-		fsclient.Session.DoRequest(req, fsclient.Session.ReadAll, printReadAll)
 		return nil
 	},
 }
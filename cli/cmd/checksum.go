@@ -0,0 +1,72 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+
+	"github.com/spf13/cobra"
+)
+
+var checksumAlgorithms = map[string]fsservice.ChecksumAlgorithm{
+	"sha256": fsservice.ChecksumAlgorithm_SHA256,
+	"sha1":   fsservice.ChecksumAlgorithm_SHA1,
+	"md5":    fsservice.ChecksumAlgorithm_MD5,
+	"crc32c": fsservice.ChecksumAlgorithm_CRC32C,
+}
+
+var checksumAlgorithm *string
+
+// checksumCmd represents the checksum command
+var checksumCmd = &cobra.Command{
+	Use:     "checksum [FILE]...",
+	Aliases: []string{"sha256sum"},
+	Short:   "Compute file checksums",
+	Long: `Print the checksum of every FILE, SHA-256 by default.
+Supported algorithms: sha256, sha1, md5, crc32c.
+Supports absolute and relative paths and wildcards.
+
+Examples:
+checksum file1
+sha256sum file1 /dir1/file2
+checksum -a md5 *.txt`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("invalid argument")
+		}
+
+		algorithm, found := checksumAlgorithms[*checksumAlgorithm]
+		if !found {
+			return fmt.Errorf("invalid algorithm %s", *checksumAlgorithm)
+		}
+
+		for _, path := range args {
+			req := &fsservice.Request{
+				Request: &fsservice.Request_Checksum{
+					Checksum: &fsservice.ChecksumRequest{
+						Path:      path,
+						Algorithm: algorithm,
+					},
+				},
+			}
+			fsclient.Session.DoRequest(req, fsclient.Session.Checksum, func(resp *fsservice.Response) {
+				fmt.Printf("%s  %s\n", resp.GetChecksum().GetDigest(), path)
+			})
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checksumCmd)
+	checksumCmd.PostRun = checksumPostRun
+	checksumPostRun(nil, nil)
+}
+
+func checksumPostRun(cmd *cobra.Command, args []string) {
+	checksumCmd.ResetFlags()
+	checksumAlgorithm = checksumCmd.Flags().StringP("algorithm", "a", "sha256", "checksum algorithm: sha256, sha1, md5 or crc32c")
+}
@@ -0,0 +1,40 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+
+	"github.com/spf13/cobra"
+)
+
+// commitCmd represents the commit command
+var commitCmd = &cobra.Command{
+	Use:   "commit",
+	Short: "Apply the transaction",
+	Long: `Apply every change of the transaction started by begin atomically.
+If a change conflicts with the current file system, nothing is applied.
+The transaction is closed in any case.
+
+Examples:
+commit`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("invalid argument")
+		}
+		req := &fsservice.Request{
+			Request: &fsservice.Request_Commit{
+				Commit: &fsservice.CommitRequest{},
+			},
+		}
+		fsclient.Session.DoRequest(req, fsclient.Session.Commit, noop)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(commitCmd)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+
+	"github.com/spf13/cobra"
+)
+
+var compressions = map[string]fsservice.Compression{
+	"none":  fsservice.Compression_NONE,
+	"flate": fsservice.Compression_FLATE,
+	"gzip":  fsservice.Compression_GZIP,
+}
+
+var compressAlgorithm *string
+
+// compressCmd represents the compress command
+var compressCmd = &cobra.Command{
+	Use:   "compress [PATH]...",
+	Short: "Compress file contents",
+	Long: `Compress the content of every file in PATH, flate by default.
+If PATH is a directory, every file in the directory tree is compressed
+and files created in it afterwards are compressed too.
+Use -c none to disable the compression.
+Supported compressions: flate, gzip, none.
+Supports absolute and relative paths.
+
+Examples:
+compress /logs
+compress -c gzip file1 file2
+compress -c none /logs`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("invalid argument")
+		}
+
+		compression, found := compressions[*compressAlgorithm]
+		if !found {
+			return fmt.Errorf("invalid compression %s", *compressAlgorithm)
+		}
+
+		for _, path := range args {
+			req := &fsservice.Request{
+				Request: &fsservice.Request_SetCompression{
+					SetCompression: &fsservice.SetCompressionRequest{
+						Path:        path,
+						Compression: compression,
+					},
+				},
+			}
+			fsclient.Session.DoRequest(req, fsclient.Session.SetCompression, noop)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(compressCmd)
+	compressCmd.PostRun = compressPostRun
+	compressPostRun(nil, nil)
+}
+
+func compressPostRun(cmd *cobra.Command, args []string) {
+	compressCmd.ResetFlags()
+	compressAlgorithm = compressCmd.Flags().StringP("compression", "c", "flate", "compression: flate, gzip or none")
+}
@@ -11,36 +11,90 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var copyContinueOnError *bool
+var copyPreserveLinks *bool
+
 // cpCmd represents the cp command
 var cpCmd = &cobra.Command{
-	Use:   "cp [SOURCE] [DEST]",
+	Use:   "cp [SOURCE]... [DEST]",
 	Short: "Copy files and directories",
 	Long: `Copy SOURCE to DEST and resolves any name conflict
 by merging directories and renaming files.
 Creates all parent directories of DEST.
-Supports relative and absolute paths. 
+Supports relative and absolute paths and wildcards,
+except in DEST. With more than one SOURCE, DEST must be
+an existing directory.
+With -c, the files that can't be copied are skipped and
+printed with a summary.
+With -p, the files hard linked to each other in SOURCE
+are hard linked to each other in DEST.
 
 Examples:
 cp dir1 dir2
-cp /dir1/file1 dir2/file1`,
+cp -c dir1 dir2
+cp -p dir1 dir2
+cp /dir1/file1 dir2/file1
+cp *.txt dir2`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) != 2 {
+		if len(args) < 2 {
 			return fmt.Errorf("invalid argument")
 		}
 
-		req := &fsservice.Request{
-			Request: &fsservice.Request_Copy{
-				Copy: &fsservice.CopyRequest{
-					SrcPath:  args[0],
-					DestPath: args[1],
+		dest := args[len(args)-1]
+		if len(args) > 2 && !isDirectory(dest) {
+			return fmt.Errorf("target '%s' is not a directory", dest)
+		}
+		for _, src := range args[:len(args)-1] {
+			req := &fsservice.Request{
+				Request: &fsservice.Request_Copy{
+					Copy: &fsservice.CopyRequest{
+						SrcPath:         src,
+						DestPath:        dest,
+						ContinueOnError: copyContinueOnError,
+						PreserveLinks:   copyPreserveLinks,
+					},
 				},
-			},
+			}
+			fsclient.Session.DoReportRequest(req, fsclient.Session.Copy, func(resp *fsservice.Response) {
+				printReport("cp", "copied", resp.GetCopy().GetReport(), *copyContinueOnError)
+			})
 		}
-		fsclient.Session.DoRequest(req, fsclient.Session.Copy, noop)
 		return nil
 	},
 }
 
+// isDirectory returns true if path is an existing directory
+// or a symbolic link to a directory
+func isDirectory(path string) bool {
+	followLinks := true
+	maxDepth := int32(0)
+	fileType := fsservice.FileType_DIRECTORY
+	req := &fsservice.Request{
+		Request: &fsservice.Request_FindV2{
+			FindV2: &fsservice.FindFilesV2Request{
+				Path:        path,
+				Type:        &fileType,
+				MaxDepth:    &maxDepth,
+				FollowLinks: &followLinks,
+			},
+		},
+	}
+
+	found := false
+	fsclient.Session.DoRequest(req, fsclient.Session.FindFilesV2, func(resp *fsservice.Response) {
+		found = len(resp.GetFind().GetPaths()) > 0
+	})
+	return found
+}
+
 func init() {
 	rootCmd.AddCommand(cpCmd)
+	cpCmd.PostRun = cpPostRun
+	cpPostRun(nil, nil)
+}
+
+func cpPostRun(cmd *cobra.Command, args []string) {
+	cpCmd.ResetFlags()
+	copyContinueOnError = cpCmd.Flags().BoolP("continue-on-error", "c", false, "copy every file possible and print the files that failed")
+	copyPreserveLinks = cpCmd.Flags().BoolP("preserve-links", "p", false, "hard link the copies of the files hard linked to each other")
 }
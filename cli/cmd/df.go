@@ -0,0 +1,65 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+
+	"github.com/spf13/cobra"
+)
+
+var dfHumanReadable *bool
+
+// dfCmd represents the df command
+var dfCmd = &cobra.Command{
+	Use:   "df",
+	Short: "Report file system usage",
+	Long: `Report the number of files, the memory used by the file system,
+the number of open files, the deleted files that are still open
+and the savings of the block deduplication.
+
+Examples:
+df
+df -h`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("invalid argument")
+		}
+
+		req := &fsservice.Request{
+			Request: &fsservice.Request_StatFS{
+				StatFS: &fsservice.StatFSRequest{},
+			},
+		}
+		fsclient.Session.DoRequest(req, fsclient.Session.StatFS, printStatFS)
+		return nil
+	},
+}
+
+func printStatFS(resp *fsservice.Response) {
+	stats := resp.GetStatFS()
+	fmt.Printf("Inodes:\t\t\t%d\n", stats.GetInodes())
+	fmt.Printf("Size:\t\t\t%s\n", formatSize(stats.GetApparentSize(), *dfHumanReadable))
+	fmt.Printf("Allocated:\t\t%s\n", formatSize(stats.GetAllocatedBytes(), *dfHumanReadable))
+	fmt.Printf("Open descriptors:\t%d\n", stats.GetOpenDescriptors())
+	fmt.Printf("Orphaned files:\t\t%d\n", stats.GetOrphanedFiles())
+	fmt.Printf("Orphaned bytes:\t\t%s\n", formatSize(stats.GetOrphanedBytes(), *dfHumanReadable))
+	fmt.Printf("Blocks:\t\t\t%d\n", stats.GetBlocks())
+	fmt.Printf("Stored bytes:\t\t%s\n", formatSize(stats.GetStoredBytes(), *dfHumanReadable))
+	fmt.Printf("Referenced bytes:\t%s\n", formatSize(stats.GetReferencedBytes(), *dfHumanReadable))
+	fmt.Printf("Dedup ratio:\t\t%.2f\n", stats.GetDedupRatio())
+}
+
+func init() {
+	rootCmd.AddCommand(dfCmd)
+	dfCmd.PostRun = dfPostRun
+	dfPostRun(nil, nil)
+}
+
+func dfPostRun(cmd *cobra.Command, args []string) {
+	dfCmd.ResetFlags()
+	dfHumanReadable = dfCmd.Flags().BoolP("human-readable", "h", false, "print sizes in human readable format (e.g., 1K 234M 2G)")
+}
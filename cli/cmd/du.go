@@ -0,0 +1,99 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+var duHumanReadable *bool
+var duSummarize *bool
+var duApparentSize *bool
+var duMaxDepth *int
+
+// duCmd represents the du command
+var duCmd = &cobra.Command{
+	Use:   "du [DIRECTORY]",
+	Short: "Estimate file space usage",
+	Long: `Summarize the memory used by DIRECTORY (the current directory by default)
+and by every subdirectory. Files with multiple hard links are counted once.
+Supports absolute and relative paths.
+
+Examples:
+du
+du -h /dir1
+du -s dir1
+du --max-depth 1 /`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 {
+			return fmt.Errorf("invalid argument")
+		}
+
+		path := ""
+		if len(args) == 1 {
+			path = args[0]
+		}
+
+		duReq := &fsservice.DiskUsageRequest{Path: path}
+		if *duSummarize {
+			duReq.MaxDepth = proto.Int32(0)
+		} else if *duMaxDepth >= 0 {
+			duReq.MaxDepth = proto.Int32(int32(*duMaxDepth))
+		}
+
+		req := &fsservice.Request{
+			Request: &fsservice.Request_DiskUsage{
+				DiskUsage: duReq,
+			},
+		}
+		fsclient.Session.DoRequest(req, fsclient.Session.DiskUsage, printDiskUsage)
+		return nil
+	},
+}
+
+func printDiskUsage(resp *fsservice.Response) {
+	for _, usage := range resp.GetDiskUsage().GetUsages() {
+		size := usage.GetAllocatedBytes()
+		if *duApparentSize {
+			size = usage.GetApparentSize()
+		}
+		fmt.Printf("%s\t%s\n", formatSize(size, *duHumanReadable), usage.GetPath())
+	}
+}
+
+// formatSize formats the number of bytes, optionally
+// in human readable format (e.g. 1.5K, 20M)
+func formatSize(size int64, humanReadable bool) string {
+	if !humanReadable || size < 1024 {
+		return fmt.Sprintf("%d", size)
+	}
+
+	value := float64(size)
+	units := []string{"K", "M", "G", "T"}
+	unit := ""
+	for i := 0; i < len(units) && value >= 1024; i++ {
+		value /= 1024
+		unit = units[i]
+	}
+	return fmt.Sprintf("%.1f%s", value, unit)
+}
+
+func init() {
+	rootCmd.AddCommand(duCmd)
+	duCmd.PostRun = duPostRun
+	duPostRun(nil, nil)
+}
+
+func duPostRun(cmd *cobra.Command, args []string) {
+	duCmd.ResetFlags()
+	duHumanReadable = duCmd.Flags().BoolP("human-readable", "h", false, "print sizes in human readable format (e.g., 1K 234M 2G)")
+	duSummarize = duCmd.Flags().BoolP("summarize", "s", false, "display only a total for the directory")
+	duApparentSize = duCmd.Flags().Bool("apparent-size", false, "print apparent sizes rather than allocated memory")
+	duMaxDepth = duCmd.Flags().Int("max-depth", -1, "print the total for a directory only if it is N or fewer levels below the command line argument")
+}
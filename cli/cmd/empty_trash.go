@@ -0,0 +1,65 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+var emptyTrashMaxAge *time.Duration
+var emptyTrashMaxSize *int64
+
+// emptyTrashCmd represents the empty-trash command
+var emptyTrashCmd = &cobra.Command{
+	Use:   "empty-trash",
+	Short: "Purge the trash",
+	Long: `Permanently delete the files in your trash and print them.
+With --max-age, purge only the files trashed more than the given time ago.
+With --max-size, purge the oldest files until the trash is at most the given size in bytes.
+
+Examples:
+empty-trash
+empty-trash --max-age 24h
+empty-trash --max-size 1048576`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("invalid argument")
+		}
+
+		emptyReq := &fsservice.EmptyTrashRequest{}
+		if cmd.Flags().Changed("max-age") {
+			emptyReq.MaxAge = proto.Int64(int64(emptyTrashMaxAge.Seconds()))
+		}
+		if cmd.Flags().Changed("max-size") {
+			emptyReq.MaxSize = proto.Int64(*emptyTrashMaxSize)
+		}
+		req := &fsservice.Request{
+			Request: &fsservice.Request_EmptyTrash{
+				EmptyTrash: emptyReq,
+			},
+		}
+		fsclient.Session.DoRequest(req, fsclient.Session.EmptyTrash, func(resp *fsservice.Response) {
+			printTrashEntries(resp.GetEmptyTrash().GetEntries())
+		})
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(emptyTrashCmd)
+	emptyTrashCmd.PostRun = emptyTrashPostRun
+	emptyTrashPostRun(nil, nil)
+}
+
+func emptyTrashPostRun(cmd *cobra.Command, args []string) {
+	emptyTrashCmd.ResetFlags()
+	emptyTrashMaxAge = emptyTrashCmd.Flags().Duration("max-age", 0, "purge only the files trashed more than this time ago (e.g. 1h30m)")
+	emptyTrashMaxSize = emptyTrashCmd.Flags().Int64("max-size", 0, "purge the oldest files until the trash is at most this size in bytes")
+}
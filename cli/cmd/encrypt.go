@@ -0,0 +1,59 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+
+	"github.com/spf13/cobra"
+)
+
+var encryptDisable *bool
+
+// encryptCmd represents the encrypt command
+var encryptCmd = &cobra.Command{
+	Use:   "encrypt [PATH]...",
+	Short: "Encrypt file contents",
+	Long: `Encrypt the content of every file in PATH with AES-GCM.
+If PATH is a directory, every file in the directory tree is encrypted
+and files created in it afterwards are encrypted too.
+The daemon loads the master keys from the file in FS_DAEMON_KEYS_FILE.
+Supports absolute and relative paths.
+
+Examples:
+encrypt /secrets
+encrypt file1 file2
+encrypt -d /secrets`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("invalid argument")
+		}
+
+		for _, path := range args {
+			req := &fsservice.Request{
+				Request: &fsservice.Request_SetEncryption{
+					SetEncryption: &fsservice.SetEncryptionRequest{
+						Path:    path,
+						Enabled: !*encryptDisable,
+					},
+				},
+			}
+			fsclient.Session.DoRequest(req, fsclient.Session.SetEncryption, noop)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(encryptCmd)
+	encryptCmd.PostRun = encryptPostRun
+	encryptPostRun(nil, nil)
+}
+
+func encryptPostRun(cmd *cobra.Command, args []string) {
+	encryptCmd.ResetFlags()
+	encryptDisable = encryptCmd.Flags().BoolP("decrypt", "d", false, "disable the encryption")
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+	"strings"
+)
+
+// Commands whose arguments are expanded when they contain wildcards
+var expandableCommands = map[string]bool{
+	"rm":        true,
+	"cp":        true,
+	"mv":        true,
+	"cat":       true,
+	"checksum":  true,
+	"sha256sum": true,
+}
+
+// Commands whose last argument is a destination, never expanded
+var destinationCommands = map[string]bool{
+	"cp": true,
+}
+
+type argument struct {
+	value string
+	// true if any part of the argument was quoted
+	quoted bool
+}
+
+// ParseCommandLine splits the command line into arguments and expands
+// any unquoted wildcard using the daemon.
+// Single and double quotes can be used to prevent the expansion or to
+// pass arguments containing spaces.
+// If a pattern doesn't match any file, it's passed to the command as is.
+// The destination of a command like cp is not expanded.
+func ParseCommandLine(line string) []string {
+	args := splitCommandLine(line)
+	result := []string{}
+	for i, arg := range args {
+		isDestination := i > 1 && i == len(args)-1 && destinationCommands[args[0].value]
+		if i == 0 || arg.quoted || isDestination || !expandableCommands[args[0].value] || !hasWildcard(arg.value) {
+			result = append(result, arg.value)
+			continue
+		}
+		result = append(result, expandWildcard(arg.value)...)
+	}
+	return result
+}
+
+// splitCommandLine splits the line on spaces, honouring quotes
+func splitCommandLine(line string) []argument {
+	args := []argument{}
+	var curr strings.Builder
+	var quote rune
+	inArg, quoted := false, false
+
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			curr.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote = r
+			inArg, quoted = true, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, argument{value: curr.String(), quoted: quoted})
+				curr.Reset()
+				inArg, quoted = false, false
+			}
+		default:
+			curr.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if inArg {
+		args = append(args, argument{value: curr.String(), quoted: quoted})
+	}
+	return args
+}
+
+func hasWildcard(arg string) bool {
+	return strings.ContainsAny(arg, "*?[{")
+}
+
+// expandWildcard returns the files matching pattern,
+// or the pattern itself if there is no match.
+func expandWildcard(pattern string) []string {
+	matches := []string{}
+	req := &fsservice.Request{
+		Request: &fsservice.Request_Glob{
+			Glob: &fsservice.GlobRequest{
+				Pattern: pattern,
+			},
+		},
+	}
+	fsclient.Session.DoRequest(req, fsclient.Session.Glob, func(resp *fsservice.Response) {
+		matches = resp.GetGlob().GetPaths()
+	})
+
+	if len(matches) == 0 {
+		return []string{pattern}
+	}
+	return matches
+}
@@ -7,38 +7,432 @@ import (
 	"fmt"
 	"material/filesystem/cli/fsclient"
 	"material/filesystem/pb/proto/fsservice"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
 )
 
+var findFileTypes = map[string]fsservice.FileType{
+	"f": fsservice.FileType_REGULAR_FILE,
+	"d": fsservice.FileType_DIRECTORY,
+	"l": fsservice.FileType_SYMBOLIC_LINK,
+	"p": fsservice.FileType_FIFO,
+}
+
+var sizeUnits = map[byte]int64{
+	'c': 1,
+	'k': 1 << 10,
+	'M': 1 << 20,
+	'G': 1 << 30,
+}
+
+// findOperators combine the tests of the expression
+var findOperators = map[string]bool{
+	"(": true, ")": true, "!": true, "-not": true,
+	"-a": true, "-and": true, "-o": true, "-or": true,
+}
+
 // findCmd represents the find command
 var findCmd = &cobra.Command{
-	Use:   "find [PATH] [NAME]",
+	Use:   "find [PATH] [NAME] [EXPRESSION]",
 	Short: "Search for files in a directory hierarchy",
 	Long: `Find searches the directory tree rooted at the given
-path and prints list of files/directories matching the given name. 
+path and prints list of files/directories matching the given name
+regular expression and the expression.
 Supports absolute and relative paths.
 
+Tests:
+-name GLOB          file name matches the glob pattern
+-regex REGEX        absolute path matches the regular expression
+-type f|d|l|p       file is a regular file, directory, symbolic link or fifo
+-size [+|-]N[ckMG]  file size is more than (+), less than (-) or exactly N
+-mtime [+|-]N       file was modified more than (+), less than (-) or exactly N days ago
+-ctime [+|-]N       file was changed more than (+), less than (-) or exactly N days ago
+-mmin [+|-]N        file was modified more than (+), less than (-) or exactly N minutes ago
+-cmin [+|-]N        file was changed more than (+), less than (-) or exactly N minutes ago
+-links [+|-]N       file has more than (+), less than (-) or exactly N hard links
+-newer FILE         file was modified more recently than FILE
+-empty              file is empty or directory has no children
+
+Operators, from the highest precedence:
+( EXPR )            group
+! EXPR, -not EXPR   true if EXPR is false
+EXPR EXPR, EXPR -a EXPR, EXPR -and EXPR
+                    true if both are true
+EXPR -o EXPR, EXPR -or EXPR
+                    true if at least one is true
+
+Options:
+-mindepth N         ignore files at depth less than N
+-maxdepth N         descend at most N levels below PATH
+-prune GLOB         skip files and directories whose name matches the glob pattern
+-L                  follow symbolic links
+-delete             delete the matching files
+
 Examples:
 find / some_name
-find dir1/dir2 some_name`,
+find dir1/dir2 some_name
+find / -type f -size +1k
+find dir1 -name '*.log' -maxdepth 2 -delete
+find . -newer file1 -prune tmp
+find / ( -name '*.log' -o -name '*.tmp' ) ! -mtime -7
+find / -type f -links +1`,
+	DisableFlagParsing: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) != 2 {
-			return fmt.Errorf("invalid argument")
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+			return cmd.Help()
+		}
+
+		findReq, err := parseFindArgs(args, time.Now())
+		if err != nil {
+			return err
 		}
+
 		req := &fsservice.Request{
-			Request: &fsservice.Request_Find{
-				Find: &fsservice.FindFilesRequest{
-					Path: args[0],
-					Name: args[1],
-				},
+			Request: &fsservice.Request_FindV2{
+				FindV2: findReq,
 			},
 		}
-		fsclient.Session.DoRequest(req, fsclient.Session.FindFiles, printFind)
+		fsclient.Session.DoRequest(req, fsclient.Session.FindFilesV2, printFind)
 		return nil
 	},
 }
 
+// parseFindArgs parses the positional arguments, the options and the expression.
+// The time tests are relative to now.
+func parseFindArgs(args []string, now time.Time) (*fsservice.FindFilesV2Request, error) {
+	findReq := &fsservice.FindFilesV2Request{}
+	positional := []string{}
+	expression := []string{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if findOperators[arg] {
+			expression = append(expression, arg)
+			continue
+		}
+		if !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+			continue
+		}
+
+		// options and tests without value
+		switch arg {
+		case "-L":
+			findReq.FollowLinks = proto.Bool(true)
+			continue
+		case "-delete":
+			findReq.Delete = proto.Bool(true)
+			continue
+		case "-empty":
+			expression = append(expression, arg)
+			continue
+		}
+
+		if i+1 >= len(args) {
+			return nil, fmt.Errorf("missing argument to %s", arg)
+		}
+		i++
+		switch arg {
+		case "-mindepth", "-maxdepth", "-prune":
+			if err := parseFindOption(findReq, arg, args[i]); err != nil {
+				return nil, err
+			}
+		default:
+			expression = append(expression, arg, args[i])
+		}
+	}
+
+	if len(positional) == 0 || len(positional) > 2 {
+		return nil, fmt.Errorf("invalid argument")
+	}
+	findReq.Path = positional[0]
+	if len(positional) == 2 {
+		findReq.NameRegex = proto.String(positional[1])
+	}
+
+	if len(expression) > 0 {
+		parser := &findParser{tokens: expression, now: now}
+		match, err := parser.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if parser.pos < len(parser.tokens) {
+			return nil, fmt.Errorf("unexpected %s", parser.tokens[parser.pos])
+		}
+		findReq.Match = match
+	}
+	return findReq, nil
+}
+
+// parseFindOption parses an option taking a value
+func parseFindOption(findReq *fsservice.FindFilesV2Request, option string, value string) error {
+	switch option {
+	case "-prune":
+		findReq.Prune = append(findReq.Prune, value)
+	case "-mindepth", "-maxdepth":
+		depth, err := strconv.Atoi(value)
+		if err != nil || depth < 0 {
+			return fmt.Errorf("invalid argument %s to %s", value, option)
+		}
+		if option == "-mindepth" {
+			findReq.MinDepth = proto.Int32(int32(depth))
+		} else {
+			findReq.MaxDepth = proto.Int32(int32(depth))
+		}
+	}
+	return nil
+}
+
+// findParser converts the expression tokens into a predicate tree
+type findParser struct {
+	tokens []string
+	pos    int
+	now    time.Time
+}
+
+func (p *findParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// parseOr parses EXPR [-o EXPR]...
+func (p *findParser) parseOr() (*fsservice.FindPredicate, error) {
+	predicates := []*fsservice.FindPredicate{}
+	for {
+		predicate, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, predicate)
+		if token := p.peek(); token != "-o" && token != "-or" {
+			break
+		}
+		p.pos++
+	}
+
+	if len(predicates) == 1 {
+		return predicates[0], nil
+	}
+	return &fsservice.FindPredicate{
+		Predicate: &fsservice.FindPredicate_Or{Or: &fsservice.FindPredicates{Predicates: predicates}},
+	}, nil
+}
+
+// parseAnd parses EXPR [[-a] EXPR]...
+func (p *findParser) parseAnd() (*fsservice.FindPredicate, error) {
+	predicates := []*fsservice.FindPredicate{}
+	for {
+		predicate, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, predicate)
+
+		token := p.peek()
+		if token == "-a" || token == "-and" {
+			p.pos++
+			continue
+		}
+		if token == "" || token == ")" || token == "-o" || token == "-or" {
+			break
+		}
+	}
+
+	if len(predicates) == 1 {
+		return predicates[0], nil
+	}
+	return &fsservice.FindPredicate{
+		Predicate: &fsservice.FindPredicate_And{And: &fsservice.FindPredicates{Predicates: predicates}},
+	}, nil
+}
+
+// parseNot parses [!] EXPR
+func (p *findParser) parseNot() (*fsservice.FindPredicate, error) {
+	if token := p.peek(); token == "!" || token == "-not" {
+		p.pos++
+		predicate, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &fsservice.FindPredicate{
+			Predicate: &fsservice.FindPredicate_Not{Not: predicate},
+		}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses ( EXPR ) or a test
+func (p *findParser) parsePrimary() (*fsservice.FindPredicate, error) {
+	token := p.peek()
+	switch token {
+	case "":
+		return nil, fmt.Errorf("expected expression")
+	case "(":
+		p.pos++
+		predicate, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return predicate, nil
+	case "-empty":
+		p.pos++
+		return findLeaf(&fsservice.FindCriterion{
+			Criterion: &fsservice.FindCriterion_Empty{Empty: true},
+		}), nil
+	}
+
+	if findOperators[token] || p.pos+1 >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %s", token)
+	}
+	value := p.tokens[p.pos+1]
+	p.pos += 2
+
+	criterion, err := parseFindTest(token, value, p.now)
+	if err != nil {
+		return nil, err
+	}
+	return findLeaf(criterion), nil
+}
+
+func findLeaf(criterion *fsservice.FindCriterion) *fsservice.FindPredicate {
+	return &fsservice.FindPredicate{
+		Predicate: &fsservice.FindPredicate_Leaf{Leaf: criterion},
+	}
+}
+
+// parseFindTest parses a test taking a value
+func parseFindTest(test string, value string, now time.Time) (*fsservice.FindCriterion, error) {
+	criterion := &fsservice.FindCriterion{}
+	switch test {
+	case "-name":
+		criterion.Criterion = &fsservice.FindCriterion_Name{Name: value}
+	case "-regex":
+		criterion.Criterion = &fsservice.FindCriterion_PathRegex{PathRegex: value}
+	case "-newer":
+		criterion.Criterion = &fsservice.FindCriterion_Newer{Newer: value}
+	case "-type":
+		fileType, found := findFileTypes[value]
+		if !found {
+			return nil, fmt.Errorf("invalid argument %s to -type", value)
+		}
+		criterion.Criterion = &fsservice.FindCriterion_Type{Type: fileType}
+	case "-size":
+		size, err := parseFindSize(value)
+		if err != nil {
+			return nil, err
+		}
+		criterion.Criterion = &fsservice.FindCriterion_Size{Size: size}
+	case "-links":
+		sign, n, err := parseFindNumber(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %s to -links", value)
+		}
+		criterion.Criterion = &fsservice.FindCriterion_Links{Links: findNumberRange(sign, n)}
+	case "-mtime", "-ctime", "-mmin", "-cmin":
+		timeRange, err := parseFindAge(test, value, now)
+		if err != nil {
+			return nil, err
+		}
+		if test == "-mtime" || test == "-mmin" {
+			criterion.Criterion = &fsservice.FindCriterion_ModTime{ModTime: timeRange}
+		} else {
+			criterion.Criterion = &fsservice.FindCriterion_ChangeTime{ChangeTime: timeRange}
+		}
+	default:
+		return nil, fmt.Errorf("unknown predicate %s", test)
+	}
+	return criterion, nil
+}
+
+// parseFindNumber parses numbers like +1, -2 or 3 and returns the sign and the number
+func parseFindNumber(value string) (byte, int64, error) {
+	sign := byte(0)
+	if strings.HasPrefix(value, "+") || strings.HasPrefix(value, "-") {
+		sign, value = value[0], value[1:]
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 || (sign == '-' && n == 0) {
+		return 0, 0, fmt.Errorf("invalid number %s", value)
+	}
+	return sign, n, nil
+}
+
+// findNumberRange returns the range of the integers more than (+), less than (-) or equal to n
+func findNumberRange(sign byte, n int64) *fsservice.FindRange {
+	switch sign {
+	case '+':
+		return &fsservice.FindRange{Min: proto.Int64(n + 1)}
+	case '-':
+		return &fsservice.FindRange{Min: proto.Int64(0), Max: proto.Int64(n - 1)}
+	default:
+		return &fsservice.FindRange{Min: proto.Int64(n), Max: proto.Int64(n)}
+	}
+}
+
+// parseFindSize parses sizes like +10k, -1M or 100
+func parseFindSize(value string) (*fsservice.FindRange, error) {
+	unit := int64(1)
+	number := value
+	if len(number) > 0 {
+		if multiplier, found := sizeUnits[number[len(number)-1]]; found {
+			unit, number = multiplier, number[:len(number)-1]
+		}
+	}
+
+	sign, n, err := parseFindNumber(number)
+	if err != nil {
+		return nil, fmt.Errorf("invalid argument %s to -size", value)
+	}
+	size := n * unit
+
+	switch sign {
+	case '+':
+		return &fsservice.FindRange{Min: proto.Int64(size + 1)}, nil
+	case '-':
+		return &fsservice.FindRange{Min: proto.Int64(0), Max: proto.Int64(size - 1)}, nil
+	default:
+		return &fsservice.FindRange{Min: proto.Int64(size), Max: proto.Int64(size)}, nil
+	}
+}
+
+// parseFindAge parses ages like +7, -1 or 2 in days or minutes and returns
+// the range of times in seconds since the epoch.
+// The age is rounded down: 2 days matches the files between 2 and 3 days old.
+func parseFindAge(test string, value string, now time.Time) (*fsservice.FindRange, error) {
+	unit := 24 * time.Hour
+	if test == "-mmin" || test == "-cmin" {
+		unit = time.Minute
+	}
+
+	sign, n, err := parseFindNumber(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid argument %s to %s", value, test)
+	}
+
+	// files of age n units were modified in (now - (n+1) units, now - n units]
+	newest := now.Add(-time.Duration(n) * unit).Unix()
+	oldest := now.Add(-time.Duration(n+1) * unit).Unix()
+
+	switch sign {
+	case '+':
+		return &fsservice.FindRange{Max: proto.Int64(oldest)}, nil
+	case '-':
+		return &fsservice.FindRange{Min: proto.Int64(newest + 1)}, nil
+	default:
+		return &fsservice.FindRange{Min: proto.Int64(oldest + 1), Max: proto.Int64(newest)}, nil
+	}
+}
+
 func printFind(resp *fsservice.Response) {
 	for _, path := range resp.GetFind().GetPaths() {
 		fmt.Println(path)
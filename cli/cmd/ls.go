@@ -11,17 +11,24 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var lsClassify *bool
+
+// lsPageSize is the number of files requested at a time
+const lsPageSize = 1000
+
 // lsCmd represents the ls command
 var lsCmd = &cobra.Command{
 	Use:   "ls [DIRECTORY]",
 	Short: "List directory contents",
 	Long: `List the FILEs names (in the current directory by
 default). Supports absolute and relative paths.
+Large directories are listed page by page.
+With -F, append / to directories, @ to symbolic links and | to fifos.
 
 Examples:
 ls
 ls /dir/dir1/dir2
-ls dir1/
+ls -F dir1/
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
@@ -33,24 +40,56 @@ ls dir1/
 			path = args[0]
 		}
 
-		req := &fsservice.Request{
-			Request: &fsservice.Request_List{
-				List: &fsservice.ListFilesRequest{
-					Path: path,
+		cursor := ""
+		for {
+			limit := int32(lsPageSize)
+			req := &fsservice.Request{
+				Request: &fsservice.Request_List{
+					List: &fsservice.ListFilesRequest{
+						Path:   path,
+						Cursor: &cursor,
+						Limit:  &limit,
+					},
 				},
-			},
+			}
+
+			nextCursor := ""
+			fsclient.Session.DoRequest(req, fsclient.Session.ListFiles, func(resp *fsservice.Response) {
+				printFileNames(resp)
+				nextCursor = resp.GetList().GetNextCursor()
+			})
+			if nextCursor == "" {
+				return nil
+			}
+			cursor = nextCursor
 		}
-		fsclient.Session.DoRequest(req, fsclient.Session.ListFiles, printFileNames)
-		return nil
 	},
 }
 
+// fileTypeIndicators are the suffixes appended by ls -F
+var fileTypeIndicators = map[fsservice.FileType]string{
+	fsservice.FileType_DIRECTORY:     "/",
+	fsservice.FileType_SYMBOLIC_LINK: "@",
+	fsservice.FileType_FIFO:          "|",
+}
+
 func printFileNames(resp *fsservice.Response) {
-	for _, name := range resp.GetList().GetNames() {
+	types := resp.GetList().GetTypes()
+	for i, name := range resp.GetList().GetNames() {
+		if *lsClassify && i < len(types) {
+			name += fileTypeIndicators[types[i]]
+		}
 		fmt.Println(name)
 	}
 }
 
 func init() {
 	rootCmd.AddCommand(lsCmd)
+	lsCmd.PostRun = lsPostRun
+	lsPostRun(nil, nil)
+}
+
+func lsPostRun(cmd *cobra.Command, args []string) {
+	lsCmd.ResetFlags()
+	lsClassify = lsCmd.Flags().BoolP("classify", "F", false, "append an indicator of the file type to the names")
 }
@@ -0,0 +1,43 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+
+	"github.com/spf13/cobra"
+)
+
+// mkfifoCmd represents the mkfifo command
+var mkfifoCmd = &cobra.Command{
+	Use:   "mkfifo [PATH]",
+	Short: "Make a named pipe",
+	Long: `Create a named pipe (FIFO) at PATH.
+A session opening the fifo for reading waits for a writer, and the other way around.
+Supports absolute and relative paths.
+
+Examples:
+mkfifo /pipe
+mkfifo dir1/pipe`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("invalid argument")
+		}
+		req := &fsservice.Request{
+			Request: &fsservice.Request_Mkfifo{
+				Mkfifo: &fsservice.MkfifoRequest{
+					Path: args[0],
+				},
+			},
+		}
+		fsclient.Session.DoRequest(req, fsclient.Session.Mkfifo, noop)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mkfifoCmd)
+}
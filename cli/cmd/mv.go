@@ -11,36 +11,55 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var moveContinueOnError *bool
+
 // mvCmd represents the mv command
 var mvCmd = &cobra.Command{
-	Use:   "mv [SOURCE] [DEST]",
+	Use:   "mv [SOURCE]... [DEST]",
 	Short: "Move (rename) files",
 	Long: `Rename SOURCE to DEST, or move SOURCE(s) to DIRECTORY
 and resolves any name conflict by merging directories and renaming files. 
 Creates all parent directories of DEST.
-Supports relative and absolute paths.
+Supports relative and absolute paths and wildcards.
+With -c, the files that can't be moved are left in SOURCE and
+printed with a summary.
 
 Examples:
 mv dir1 dir2
-mv /dir1/file1 dir1/file2`,
+mv -c dir1 dir2
+mv /dir1/file1 dir1/file2
+mv *.txt dir2`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) != 2 {
+		if len(args) < 2 {
 			return fmt.Errorf("invalid argument")
 		}
 
-		req := &fsservice.Request{
-			Request: &fsservice.Request_Move{
-				Move: &fsservice.MoveRequest{
-					SrcPath:  args[0],
-					DestPath: args[1],
+		dest := args[len(args)-1]
+		for _, src := range args[:len(args)-1] {
+			req := &fsservice.Request{
+				Request: &fsservice.Request_Move{
+					Move: &fsservice.MoveRequest{
+						SrcPath:         src,
+						DestPath:        dest,
+						ContinueOnError: moveContinueOnError,
+					},
 				},
-			},
+			}
+			fsclient.Session.DoReportRequest(req, fsclient.Session.Move, func(resp *fsservice.Response) {
+				printReport("mv", "moved", resp.GetMove().GetReport(), *moveContinueOnError)
+			})
 		}
-		fsclient.Session.DoRequest(req, fsclient.Session.Move, noop)
 		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(mvCmd)
+	mvCmd.PostRun = mvPostRun
+	mvPostRun(nil, nil)
+}
+
+func mvPostRun(cmd *cobra.Command, args []string) {
+	mvCmd.ResetFlags()
+	moveContinueOnError = mvCmd.Flags().BoolP("continue-on-error", "c", false, "move every file possible and print the files that failed")
 }
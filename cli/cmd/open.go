@@ -11,17 +11,26 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var openRead *bool
+var openWrite *bool
+var openNonBlocking *bool
+
 // openCmd represents the open command
 var openCmd = &cobra.Command{
 	Use:   "open [PATH]",
 	Short: "Open a file",
 	Long: `The open call opens the file specified by pathname and
 prints the file descriptor to stdout.
+By default the file is open for reading and writing.
+Opening a fifo only for reading or only for writing waits for the other end,
+unless --non-blocking is set.
 Supports absolute and relative paths.
 
 Examples:
 open /file1
-open file1`,
+open file1
+open -r /pipe
+open -w --non-blocking /pipe`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
 			return fmt.Errorf("invalid argument")
@@ -29,7 +38,10 @@ open file1`,
 		req := &fsservice.Request{
 			Request: &fsservice.Request_Open{
 				Open: &fsservice.OpenRequest{
-					Path: args[0],
+					Path:        args[0],
+					Read:        openRead,
+					Write:       openWrite,
+					NonBlocking: openNonBlocking,
 				},
 			},
 		}
@@ -40,6 +52,15 @@ open file1`,
 
 func init() {
 	rootCmd.AddCommand(openCmd)
+	openCmd.PostRun = openPostRun
+	openPostRun(nil, nil)
+}
+
+func openPostRun(cmd *cobra.Command, args []string) {
+	openCmd.ResetFlags()
+	openRead = openCmd.Flags().BoolP("read", "r", false, "open for reading")
+	openWrite = openCmd.Flags().BoolP("write", "w", false, "open for writing")
+	openNonBlocking = openCmd.Flags().BoolP("non-blocking", "n", false, "fail instead of waiting for the other end of a fifo")
 }
 
 func printFd(resp *fsservice.Response) {
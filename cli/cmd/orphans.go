@@ -0,0 +1,85 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var orphansReclaim *bool
+
+// orphansCmd represents the orphans command
+var orphansCmd = &cobra.Command{
+	Use:   "orphans [--reclaim ID...]",
+	Short: "List or reclaim the deleted files that are still open",
+	Long: `List the files that have been deleted while open, oldest first.
+Their content stays allocated until every descriptor is closed.
+For each file, print id, deletion time, allocated bytes, open descriptors, owning sessions and original path.
+With --reclaim, close every descriptor of the given files and release their content.
+
+Examples:
+orphans
+orphans --reclaim 1 2`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !*orphansReclaim {
+			if len(args) > 0 {
+				return fmt.Errorf("invalid argument")
+			}
+			req := &fsservice.Request{
+				Request: &fsservice.Request_ListOrphans{
+					ListOrphans: &fsservice.ListOrphansRequest{},
+				},
+			}
+			fsclient.Session.DoRequest(req, fsclient.Session.ListOrphans, func(resp *fsservice.Response) {
+				printOrphans(resp.GetListOrphans().GetOrphans())
+			})
+			return nil
+		}
+
+		if len(args) == 0 {
+			return fmt.Errorf("missing orphan id")
+		}
+		for _, id := range args {
+			req := &fsservice.Request{
+				Request: &fsservice.Request_ReclaimOrphan{
+					ReclaimOrphan: &fsservice.ReclaimOrphanRequest{Id: id},
+				},
+			}
+			fsclient.Session.DoRequest(req, fsclient.Session.ReclaimOrphan, func(resp *fsservice.Response) {
+				printOrphans([]*fsservice.Orphan{resp.GetReclaimOrphan().GetOrphan()})
+			})
+		}
+		return nil
+	},
+}
+
+// printOrphans prints one orphan per line: id, deletion time, allocated bytes,
+// open descriptors, owning sessions and original path
+func printOrphans(orphans []*fsservice.Orphan) {
+	for _, o := range orphans {
+		owners := strings.Join(o.GetOwners(), ",")
+		if owners == "" {
+			owners = "-"
+		}
+		deletedAt := time.Unix(o.GetDeletedAt(), 0).Format(time.RFC3339)
+		fmt.Printf("%s\t%s\t%d\t%d\t%s\t%s\n", o.GetId(), deletedAt, o.GetAllocatedBytes(), o.GetDescriptors(), owners, o.GetOriginalPath())
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(orphansCmd)
+	orphansCmd.PostRun = orphansPostRun
+	orphansPostRun(nil, nil)
+}
+
+func orphansPostRun(cmd *cobra.Command, args []string) {
+	orphansCmd.ResetFlags()
+	orphansReclaim = orphansCmd.Flags().Bool("reclaim", false, "close every descriptor of the given orphans")
+}
@@ -0,0 +1,56 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// punchHoleCmd represents the punchHole command
+var punchHoleCmd = &cobra.Command{
+	Use:   "punchHole [FILE_DESCRIPTOR] [OFFSET] [LENGTH]",
+	Short: "Deallocate part of a file",
+	Long: `Deallocate LENGTH bytes starting at OFFSET.
+The range reads as zeros and the file size doesn't change.
+
+Examples:
+punchHole fd 10 100
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 3 {
+			return fmt.Errorf("invalid argument")
+		}
+
+		offset, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid argument")
+		}
+
+		length, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid argument")
+		}
+
+		req := &fsservice.Request{
+			Request: &fsservice.Request_PunchHole{
+				PunchHole: &fsservice.PunchHoleRequest{
+					FileDescriptor: args[0],
+					Offset:         offset,
+					Length:         length,
+				},
+			},
+		}
+		fsclient.Session.DoRequest(req, fsclient.Session.PunchHole, noop)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(punchHoleCmd)
+}
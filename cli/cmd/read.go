@@ -0,0 +1,49 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+
+	"github.com/spf13/cobra"
+)
+
+// readCmd represents the read command
+var readCmd = &cobra.Command{
+	Use:   "read [FILE_DESCRIPTOR]",
+	Short: "Stream a file",
+	Long: `Print the content of [FILE_DESCRIPTOR] from the current offset
+to the end of the file, as it is received.
+Reading a fifo waits for the writers and stops when every writer has closed it.
+
+Examples:
+read fd
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("invalid argument")
+		}
+
+		req := &fsservice.Request{
+			Request: &fsservice.Request_ReadStream{
+				ReadStream: &fsservice.ReadStreamRequest{
+					FileDescriptor: args[0],
+				},
+			},
+		}
+		fsclient.Session.DoStreamRequest(req, fsclient.Session.ReadStream, printReadStream)
+		fmt.Println()
+		return nil
+	},
+}
+
+func printReadStream(resp *fsservice.Response) {
+	fmt.Print(string(resp.GetReadStream().GetContent()))
+}
+
+func init() {
+	rootCmd.AddCommand(readCmd)
+}
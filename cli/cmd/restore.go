@@ -0,0 +1,49 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore ID [PATH]",
+	Short: "Restore a file from the trash",
+	Long: `Move the trash entry ID back to its original path, or to PATH if specified.
+The entry ids are listed by trash.
+Supports absolute and relative paths.
+
+Examples:
+restore 0b7f6c1e-5a4d-4a8e-9a55-3c1e2f9d7b10
+restore 0b7f6c1e-5a4d-4a8e-9a55-3c1e2f9d7b10 /restored`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 || len(args) > 2 {
+			return fmt.Errorf("invalid argument")
+		}
+
+		restoreReq := &fsservice.RestoreRequest{Id: args[0]}
+		if len(args) == 2 {
+			restoreReq.Path = proto.String(args[1])
+		}
+		req := &fsservice.Request{
+			Request: &fsservice.Request_Restore{
+				Restore: restoreReq,
+			},
+		}
+		fsclient.Session.DoRequest(req, fsclient.Session.Restore, func(resp *fsservice.Response) {
+			fmt.Println(resp.GetRestore().GetPath())
+		})
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+}
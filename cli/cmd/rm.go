@@ -12,32 +12,42 @@ import (
 )
 
 var removeChildren *bool
+var removeContinueOnError *bool
 
 // rmCmd represents the rm command
 var rmCmd = &cobra.Command{
-	Use:   "rm [FILE]",
+	Use:   "rm [FILE]...",
 	Short: "Remove files or directories",
 	Long: `rm removes each specified file. By default, it does not remove directories.
-Supports absolute and relative paths
+Supports absolute and relative paths and wildcards.
+With -r -c, the files that can't be removed are kept together
+with their parent directories and printed with a summary.
 
 Examples:
 rm /file1
 rm -r /dir1/dir2
+rm -rc /dir1
 rm file1
+rm *.txt
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) != 1 {
+		if len(args) == 0 {
 			return fmt.Errorf("invalid argument")
 		}
-		req := &fsservice.Request{
-			Request: &fsservice.Request_Remove{
-				Remove: &fsservice.RemoveRequest{
-					Path:      args[0],
-					Recursive: removeChildren,
+		for _, path := range args {
+			req := &fsservice.Request{
+				Request: &fsservice.Request_Remove{
+					Remove: &fsservice.RemoveRequest{
+						Path:            path,
+						Recursive:       removeChildren,
+						ContinueOnError: removeContinueOnError,
+					},
 				},
-			},
+			}
+			fsclient.Session.DoReportRequest(req, fsclient.Session.Remove, func(resp *fsservice.Response) {
+				printReport("rm", "removed", resp.GetRemove().GetReport(), *removeContinueOnError)
+			})
 		}
-		fsclient.Session.DoRequest(req, fsclient.Session.Remove, noop)
 		return nil
 	},
 }
@@ -51,5 +61,6 @@ func init() {
 func rmPostRun(cmd *cobra.Command, args []string) {
 	rmCmd.ResetFlags()
 	removeChildren = rmCmd.Flags().BoolP("recursive", "r", false, "remove directories and their contents recursively")
+	removeContinueOnError = rmCmd.Flags().BoolP("continue-on-error", "c", false, "with -r, remove every file possible and print the files that failed")
 
 }
@@ -0,0 +1,82 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+var removeParentDirs *bool
+
+// rmdirCmd represents the rmdir command
+var rmdirCmd = &cobra.Command{
+	Use:   "rmdir [DIRECTORY]...",
+	Short: "Remove empty directories",
+	Long: `Remove the DIRECTORY(ies), if they are empty.
+With -p, every parent in the path of DIRECTORY is removed too,
+stopping at the first one which is not empty.
+Supports absolute and relative paths.
+
+Examples:
+rmdir dir
+rmdir /dir1 /dir2
+rmdir -p /dir1/dir2/dir3`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("invalid argument")
+		}
+		for _, path := range args {
+			rmdir(path, *removeParentDirs)
+		}
+		return nil
+	},
+}
+
+// rmdir removes the empty directory and, if parents is true,
+// its parents until one can't be removed
+func rmdir(path string, parents bool) {
+	path = filepath.Clean(path)
+	for removeEmptyDir(path) && parents {
+		parent := filepath.Dir(path)
+		if parent == "." || parent == "/" {
+			return
+		}
+		path = parent
+	}
+}
+
+// removeEmptyDir removes the empty directory and returns true if it was removed
+func removeEmptyDir(path string) bool {
+	req := &fsservice.Request{
+		Request: &fsservice.Request_Remove{
+			Remove: &fsservice.RemoveRequest{
+				Path:     path,
+				EmptyDir: proto.Bool(true),
+			},
+		},
+	}
+
+	removed := false
+	fsclient.Session.DoRequest(req, fsclient.Session.Remove, func(resp *fsservice.Response) {
+		removed = true
+	})
+	return removed
+}
+
+func init() {
+	rootCmd.AddCommand(rmdirCmd)
+	rmdirCmd.PostRun = rmdirPostRun
+	rmdirPostRun(nil, nil)
+}
+
+func rmdirPostRun(cmd *cobra.Command, args []string) {
+	rmdirCmd.ResetFlags()
+	removeParentDirs = rmdirCmd.Flags().BoolP("parents", "p", false, "remove DIRECTORY and its empty parents")
+}
@@ -0,0 +1,38 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+
+	"github.com/spf13/cobra"
+)
+
+// rollbackCmd represents the rollback command
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Discard the transaction",
+	Long: `Discard every change of the transaction started by begin.
+
+Examples:
+rollback`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("invalid argument")
+		}
+		req := &fsservice.Request{
+			Request: &fsservice.Request_Rollback{
+				Rollback: &fsservice.RollbackRequest{},
+			},
+		}
+		fsclient.Session.DoRequest(req, fsclient.Session.Rollback, noop)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+}
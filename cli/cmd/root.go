@@ -4,6 +4,7 @@ Copyright © 2022 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"fmt"
 	"material/filesystem/pb/proto/fsservice"
 
 	"github.com/spf13/cobra"
@@ -27,3 +28,17 @@ func Execute(args []string) {
 }
 
 func noop(resp *fsservice.Response) {}
+
+// printReport prints the files that failed and, if there is any failure or
+// summary is true, the number of files processed and failed
+func printReport(command string, done string, report *fsservice.Report, summary bool) {
+	if report == nil {
+		return
+	}
+	for _, failure := range report.GetFailed() {
+		fmt.Printf("%s: %s: %s\n", command, failure.GetPath(), failure.GetError())
+	}
+	if summary || len(report.GetFailed()) > 0 {
+		fmt.Printf("%s: %d %s, %d failed\n", command, len(report.GetSucceeded()), done, len(report.GetFailed()))
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+// rotateKeysCmd represents the rotate-keys command
+var rotateKeysCmd = &cobra.Command{
+	Use:   "rotate-keys [PATH]",
+	Short: "Rotate the encryption keys",
+	Long: `Without arguments, encrypt every data key with the current master key,
+which is the last key in the daemon keys file.
+With PATH, encrypt the files in PATH again with new data keys.
+Supports absolute and relative paths.
+
+Examples:
+rotate-keys
+rotate-keys /secrets`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 {
+			return fmt.Errorf("invalid argument")
+		}
+
+		rotateReq := &fsservice.RotateKeysRequest{}
+		if len(args) == 1 {
+			rotateReq.Path = proto.String(args[0])
+		}
+		req := &fsservice.Request{
+			Request: &fsservice.Request_RotateKeys{
+				RotateKeys: rotateReq,
+			},
+		}
+		fsclient.Session.DoRequest(req, fsclient.Session.RotateKeys, noop)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rotateKeysCmd)
+}
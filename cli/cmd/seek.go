@@ -0,0 +1,75 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// seekDataCmd represents the seekData command
+var seekDataCmd = &cobra.Command{
+	Use:   "seekData [FILE_DESCRIPTOR] [OFFSET]",
+	Short: "Move to the next data",
+	Long: `Move the file offset to the first position >= OFFSET
+containing data and print it.
+
+Examples:
+seekData fd 0
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return seek(args, fsservice.SeekWhence_DATA)
+	},
+}
+
+// seekHoleCmd represents the seekHole command
+var seekHoleCmd = &cobra.Command{
+	Use:   "seekHole [FILE_DESCRIPTOR] [OFFSET]",
+	Short: "Move to the next hole",
+	Long: `Move the file offset to the first position >= OFFSET
+in a hole and print it. The end of the file is a hole.
+
+Examples:
+seekHole fd 0
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return seek(args, fsservice.SeekWhence_HOLE)
+	},
+}
+
+func seek(args []string, whence fsservice.SeekWhence) error {
+	if len(args) != 2 {
+		return fmt.Errorf("invalid argument")
+	}
+
+	offset, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid argument")
+	}
+
+	req := &fsservice.Request{
+		Request: &fsservice.Request_Seek{
+			Seek: &fsservice.SeekRequest{
+				FileDescriptor: args[0],
+				Offset:         offset,
+				Whence:         whence,
+			},
+		},
+	}
+	fsclient.Session.DoRequest(req, fsclient.Session.Seek, printSeek)
+	return nil
+}
+
+func printSeek(resp *fsservice.Response) {
+	fmt.Println(resp.GetSeek().GetOffset())
+}
+
+func init() {
+	rootCmd.AddCommand(seekDataCmd)
+	rootCmd.AddCommand(seekHoleCmd)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var trashRecursive *bool
+
+// trashCmd represents the trash command
+var trashCmd = &cobra.Command{
+	Use:   "trash [FILE]...",
+	Short: "Move files to the trash or list the trash",
+	Long: `Without arguments, list the files in your trash, oldest first.
+With FILE arguments, move each file to your trash. By default, it does not trash directories.
+Trashed files can be restored with restore.
+Supports absolute and relative paths.
+
+Examples:
+trash
+trash /file1
+trash -r /dir1 file2`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			req := &fsservice.Request{
+				Request: &fsservice.Request_ListTrash{
+					ListTrash: &fsservice.ListTrashRequest{},
+				},
+			}
+			fsclient.Session.DoRequest(req, fsclient.Session.ListTrash, func(resp *fsservice.Response) {
+				printTrashEntries(resp.GetListTrash().GetEntries())
+			})
+			return nil
+		}
+
+		req := &fsservice.Request{
+			Request: &fsservice.Request_Trash{
+				Trash: &fsservice.TrashRequest{
+					Paths:     args,
+					Recursive: trashRecursive,
+				},
+			},
+		}
+		fsclient.Session.DoRequest(req, fsclient.Session.Trash, func(resp *fsservice.Response) {
+			printTrashEntries(resp.GetTrash().GetEntries())
+		})
+		return nil
+	},
+}
+
+// printTrashEntries prints one entry per line: id, deletion time, size and original path
+func printTrashEntries(entries []*fsservice.TrashEntry) {
+	for _, entry := range entries {
+		suffix := ""
+		if entry.GetFileType() == fsservice.FileType_DIRECTORY {
+			suffix = "/"
+		}
+		deletedAt := time.Unix(entry.GetDeletedAt(), 0).Format(time.RFC3339)
+		fmt.Printf("%s\t%s\t%d\t%s%s\n", entry.GetId(), deletedAt, entry.GetSize(), entry.GetOriginalPath(), suffix)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(trashCmd)
+	trashCmd.PostRun = trashPostRun
+	trashPostRun(nil, nil)
+}
+
+func trashPostRun(cmd *cobra.Command, args []string) {
+	trashCmd.ResetFlags()
+	trashRecursive = trashCmd.Flags().BoolP("recursive", "r", false, "trash directories and their contents")
+}
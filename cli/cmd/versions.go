@@ -0,0 +1,90 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var versionsShow *int64
+var versionsRestore *int64
+
+// versionsCmd represents the versions command
+var versionsCmd = &cobra.Command{
+	Use:   "versions FILE",
+	Short: "List, print or restore the versions of a file",
+	Long: `Without flags, list the versions of FILE, oldest first.
+A version is captured every time the file is closed after being written.
+With --show, print the content of a version.
+With --restore, replace the content of FILE with a version.
+Supports absolute and relative paths.
+
+Examples:
+versions /config
+versions --show 2 /config
+versions --restore 2 /config`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("invalid argument")
+		}
+		if cmd.Flags().Changed("show") && cmd.Flags().Changed("restore") {
+			return fmt.Errorf("--show and --restore are mutually exclusive")
+		}
+
+		if cmd.Flags().Changed("show") {
+			req := &fsservice.Request{
+				Request: &fsservice.Request_ReadVersion{
+					ReadVersion: &fsservice.ReadVersionRequest{Path: args[0], Id: *versionsShow},
+				},
+			}
+			fsclient.Session.DoRequest(req, fsclient.Session.ReadVersion, func(resp *fsservice.Response) {
+				fmt.Println(string(resp.GetReadVersion().GetContent()))
+			})
+			return nil
+		}
+
+		if cmd.Flags().Changed("restore") {
+			req := &fsservice.Request{
+				Request: &fsservice.Request_RestoreVersion{
+					RestoreVersion: &fsservice.RestoreVersionRequest{Path: args[0], Id: *versionsRestore},
+				},
+			}
+			fsclient.Session.DoRequest(req, fsclient.Session.RestoreVersion, noop)
+			return nil
+		}
+
+		req := &fsservice.Request{
+			Request: &fsservice.Request_ListVersions{
+				ListVersions: &fsservice.ListVersionsRequest{Path: args[0]},
+			},
+		}
+		fsclient.Session.DoRequest(req, fsclient.Session.ListVersions, printVersions)
+		return nil
+	},
+}
+
+// printVersions prints one version per line: id, capture time and size
+func printVersions(resp *fsservice.Response) {
+	for _, v := range resp.GetListVersions().GetVersions() {
+		createdAt := time.Unix(v.GetCreatedAt(), 0).Format(time.RFC3339)
+		fmt.Printf("%d\t%s\t%d\n", v.GetId(), createdAt, v.GetSize())
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(versionsCmd)
+	versionsCmd.PostRun = versionsPostRun
+	versionsPostRun(nil, nil)
+}
+
+func versionsPostRun(cmd *cobra.Command, args []string) {
+	versionsCmd.ResetFlags()
+	versionsShow = versionsCmd.Flags().Int64("show", 0, "print the content of the version with the given id")
+	versionsRestore = versionsCmd.Flags().Int64("restore", 0, "restore the version with the given id")
+}
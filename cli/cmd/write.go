@@ -0,0 +1,47 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"material/filesystem/cli/fsclient"
+	"material/filesystem/pb/proto/fsservice"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// writeCmd represents the write command
+var writeCmd = &cobra.Command{
+	Use:   "write [FILE_DESCRIPTOR] [CONTENT]",
+	Short: "Write a file",
+	Long: `Write CONTENT to [FILE_DESCRIPTOR] at the current offset.
+Writing a fifo waits while the readers are behind.
+
+Examples:
+write fd some text
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return fmt.Errorf("invalid argument")
+		}
+
+		text := strings.Join(args[1:], " ")
+
+		req := &fsservice.Request{
+			Request: &fsservice.Request_Write{
+				Write: &fsservice.WriteRequest{
+					FileDescriptor: args[0],
+					Content:        []byte(text),
+				},
+			},
+		}
+		fsclient.Session.DoRequest(req, fsclient.Session.Write, noop)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(writeCmd)
+}
@@ -3,6 +3,8 @@ package fsclient
 import (
 	"context"
 	"fmt"
+	"io"
+	"os/user"
 	"path/filepath"
 	"time"
 
@@ -27,6 +29,16 @@ type grpcFileSystemCall func(ctx context.Context, req *fsservice.Request, opts .
 type onSuccessFn func(*fsservice.Response)
 
 func (f *FileSystemSession) DoRequest(req *fsservice.Request, call grpcFileSystemCall, onSuonSuccessFn onSuccessFn) {
+	f.doRequest(req, call, onSuonSuccessFn, false)
+}
+
+// DoReportRequest calls onResponseFn also when the response has an error,
+// so that the partial results of the operation can be printed after the error
+func (f *FileSystemSession) DoReportRequest(req *fsservice.Request, call grpcFileSystemCall, onResponseFn onSuccessFn) {
+	f.doRequest(req, call, onResponseFn, true)
+}
+
+func (f *FileSystemSession) doRequest(req *fsservice.Request, call grpcFileSystemCall, onSuonSuccessFn onSuccessFn, onError bool) {
 	// TODO: make timeout configurable
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
@@ -41,8 +53,44 @@ func (f *FileSystemSession) DoRequest(req *fsservice.Request, call grpcFileSyste
 	defer f.updateWokingDirectory(resp)
 	if resp.GetError() != "" {
 		fmt.Println(resp.GetError())
-	} else {
-		onSuonSuccessFn(resp)
+		if !onError {
+			return
+		}
+	}
+	onSuonSuccessFn(resp)
+}
+
+type grpcFileSystemStreamCall func(ctx context.Context, req *fsservice.Request, opts ...grpc.CallOption) (fsservice.FileSystemService_ReadStreamClient, error)
+
+// DoStreamRequest calls onSuccessFn for every response of the stream, until the stream ends or a response has an error
+func (f *FileSystemSession) DoStreamRequest(req *fsservice.Request, call grpcFileSystemStreamCall, onSuccessFn onSuccessFn) {
+	// TODO: make timeout configurable
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	req.SessionId = f.sessionId
+	stream, err := call(ctx, req)
+	if err != nil {
+		fmt.Println(fmt.Errorf("unexpected error: %v", err))
+		return
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			fmt.Println(fmt.Errorf("unexpected error: %v", err))
+			return
+		}
+
+		f.updateWokingDirectory(resp)
+		if resp.GetError() != "" {
+			fmt.Println(resp.GetError())
+			return
+		}
+		onSuccessFn(resp)
 	}
 }
 
@@ -73,7 +121,7 @@ func Initialize(port string) error {
 	sessionClient := session.NewSessionServiceClient(conn)
 
 	ctx := context.Background()
-	resp, err := sessionClient.NewSession(ctx, &session.NewSessionRequest{})
+	resp, err := sessionClient.NewSession(ctx, &session.NewSessionRequest{User: currentUser()})
 	if err != nil {
 		conn.Close()
 		return fmt.Errorf("error creating new session: %w", err)
@@ -90,6 +138,15 @@ func Initialize(port string) error {
 	return nil
 }
 
+// currentUser returns the name of the OS user, empty if unknown
+func currentUser() string {
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return u.Username
+}
+
 func Close() {
 	ctx := context.Background()
 	if Session.sessionId != "" {
@@ -11,7 +11,6 @@ import (
 	"material/filesystem/cli/fsclient"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 )
 
@@ -55,7 +54,7 @@ func startCli() {
 		scanner := bufio.NewScanner(os.Stdin)
 		scanner.Scan()
 		text := scanner.Text()
-		args := strings.Split(text, " ")
+		args := cmd.ParseCommandLine(text)
 		cmd.Execute(args)
 	}
 }
@@ -1,22 +1,84 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
 	daemon "material/filesystem/daemon/service"
 	"material/filesystem/filesystem"
+	"material/filesystem/filesystem/fscrypt"
+	"material/filesystem/filesystem/fsname"
+	"material/filesystem/filesystem/fstrash"
+	"material/filesystem/filesystem/fsversion"
+	"material/filesystem/filesystem/memoryfs"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const defaultPort = "2222"
 
+var (
+	rootName        = flag.String("root-name", "/", "name of the root directory")
+	layout          = flag.String("layout", "", "comma separated directories created at startup, e.g. /home,/tmp")
+	maxLinkDepth    = flag.Int("max-link-depth", memoryfs.MAX_LINK_DEPTH, "maximum number of symbolic links followed to resolve a path")
+	maxFileSize     = flag.Int("max-file-size", 0, "maximum size of a file in bytes, 0 for no limit")
+	capacity        = flag.Int("capacity", 0, "maximum number of bytes stored, 0 for no limit")
+	caseInsensitive = flag.Bool("case-insensitive", false, "ignore case in file names")
+
+	maxNameLength     = flag.Int("max-name-length", fsname.DefaultPolicy().MaxNameLength, "maximum length of a file name in bytes, 0 for no limit")
+	maxPathLength     = flag.Int("max-path-length", fsname.DefaultPolicy().MaxPathLength, "maximum length of an absolute path in bytes, 0 for no limit")
+	maxPathDepth      = flag.Int("max-path-depth", 0, "maximum number of names in an absolute path, 0 for no limit")
+	forbiddenChars    = flag.String("forbidden-chars", "", "characters that can't be used in file names, in addition to NUL")
+	reservedNames     = flag.String("reserved-names", "", "comma separated names that can't be used as file names")
+	nameNormalization = flag.String("name-normalization", "", "normalization of the file names, nfc or nfd")
+)
+
 func main() {
+	flag.Parse()
+
 	// TODO: file system type should be a cli or en var
 	log.Println("Initializing file system daemon")
-	daemon, err := daemon.NewFileSystemDaemon(filesystem.InMemoryFileSystem)
+	options, err := fileSystemOptions()
+	if err != nil {
+		log.Fatal(err)
+		panic(err)
+	}
+	daemon, err := daemon.NewFileSystemDaemon(filesystem.InMemoryFileSystem, options...)
+	if err != nil {
+		log.Fatal(err)
+		panic(err)
+	}
+	keysFile := os.Getenv("FS_DAEMON_KEYS_FILE")
+	if keysFile != "" {
+		keys, err := fscrypt.NewFileKeyProvider(keysFile)
+		if err != nil {
+			log.Fatal(err)
+			panic(err)
+		}
+		daemon.SetKeyProvider(keys)
+	}
+	if os.Getenv("FS_DAEMON_TRASH") == "true" {
+		retention, err := trashRetention()
+		if err != nil {
+			log.Fatal(err)
+			panic(err)
+		}
+		daemon.EnableTrash(retention)
+	}
+	policy, err := versionPolicy()
 	if err != nil {
 		log.Fatal(err)
 		panic(err)
 	}
+	daemon.SetVersionPolicy(policy)
+	if os.Getenv("FS_DAEMON_VIRTUAL_FILES") == "true" {
+		if err := daemon.MountVirtualFiles(); err != nil {
+			log.Fatal(err)
+			panic(err)
+		}
+	}
 	log.Println("Starting daemon")
 	port := os.Getenv("FS_DAEMON_PORT")
 	if port == "" {
@@ -28,3 +90,90 @@ func main() {
 		panic(err)
 	}
 }
+
+// fileSystemOptions returns the file system options set by the flags
+func fileSystemOptions() ([]memoryfs.Option, error) {
+	names, err := namePolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	options := []memoryfs.Option{
+		memoryfs.WithRootName(*rootName),
+		memoryfs.WithMaxLinkDepth(*maxLinkDepth),
+		memoryfs.WithMaxFileSize(*maxFileSize),
+		memoryfs.WithCapacity(*capacity),
+		memoryfs.WithNamePolicy(names),
+	}
+	if *layout != "" {
+		options = append(options, memoryfs.WithLayout(strings.Split(*layout, ",")...))
+	}
+	if *caseInsensitive {
+		options = append(options, memoryfs.WithCaseInsensitiveNames())
+	}
+	return options, nil
+}
+
+// trashRetention reads the trash limits from FS_DAEMON_TRASH_MAX_AGE (duration)
+// and FS_DAEMON_TRASH_MAX_SIZE (bytes)
+func trashRetention() (fstrash.Retention, error) {
+	retention := fstrash.Retention{}
+	if maxAge := os.Getenv("FS_DAEMON_TRASH_MAX_AGE"); maxAge != "" {
+		d, err := time.ParseDuration(maxAge)
+		if err != nil {
+			return retention, err
+		}
+		retention.MaxAge = d
+	}
+	if maxSize := os.Getenv("FS_DAEMON_TRASH_MAX_SIZE"); maxSize != "" {
+		size, err := strconv.Atoi(maxSize)
+		if err != nil {
+			return retention, err
+		}
+		retention.MaxSize = size
+	}
+	return retention, nil
+}
+
+// versionPolicy reads the file versions limits from FS_DAEMON_VERSIONS_MAX (count)
+// and FS_DAEMON_VERSIONS_MAX_AGE (duration)
+func versionPolicy() (fsversion.Policy, error) {
+	policy := fsversion.Policy{}
+	if maxVersions := os.Getenv("FS_DAEMON_VERSIONS_MAX"); maxVersions != "" {
+		n, err := strconv.Atoi(maxVersions)
+		if err != nil {
+			return policy, err
+		}
+		policy.MaxVersions = n
+	}
+	if maxAge := os.Getenv("FS_DAEMON_VERSIONS_MAX_AGE"); maxAge != "" {
+		d, err := time.ParseDuration(maxAge)
+		if err != nil {
+			return policy, err
+		}
+		policy.MaxAge = d
+	}
+	return policy, nil
+}
+
+// namePolicy returns the name restrictions set by the flags
+func namePolicy() (fsname.Policy, error) {
+	policy := fsname.DefaultPolicy()
+	policy.MaxNameLength = *maxNameLength
+	policy.MaxPathLength = *maxPathLength
+	policy.MaxDepth = *maxPathDepth
+	policy.ForbiddenChars += *forbiddenChars
+	if *reservedNames != "" {
+		policy.ReservedNames = strings.Split(*reservedNames, ",")
+	}
+	switch strings.ToLower(*nameNormalization) {
+	case "":
+	case "nfc":
+		policy.Normalization = fsname.NFC
+	case "nfd":
+		policy.Normalization = fsname.NFD
+	default:
+		return policy, fmt.Errorf("invalid name normalization")
+	}
+	return policy, nil
+}
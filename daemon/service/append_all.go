@@ -22,7 +22,7 @@ func (daemon *FileSystemDaemon) AppendAll(ctx context.Context, request *pb.Reque
 	}
 
 	workDir := path.WorkingDir()
-	err = daemon.fs.AppendAll(path, appendReq.GetContent())
+	err = daemon.operations(request.GetSessionId()).AppendAll(path, appendReq.GetContent())
 	if err != nil {
 		log.Printf("%s - appendAll fs error: %s", request.GetSessionId(), err.Error())
 		return daemon.extractError(request.GetSessionId(), workDir, err)
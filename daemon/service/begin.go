@@ -0,0 +1,47 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	pb "material/filesystem/pb/proto/fsservice"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func (daemon *FileSystemDaemon) Begin(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - begin request recevied: {%+v}", request.GetSessionId(), request)
+	if request.GetBegin() == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	workDir, err := daemon.sessionStore.GetWorkingDirectoryForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - begin session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	tx, err := daemon.sessionStore.GetTransactionForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - begin session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+	if tx != nil {
+		return &pb.Response{
+			Error:          proto.String("transaction already open"),
+			WorkingDirPath: workDir.Info().AbsolutePath(),
+		}, nil
+	}
+
+	if err := daemon.sessionStore.SetTransaction(request.GetSessionId(), daemon.fs.Begin()); err != nil {
+		log.Printf("%s - begin session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_Begin{
+			Begin: &pb.BeginResponse{},
+		},
+	}, nil
+}
@@ -0,0 +1,50 @@
+package daemon
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"material/filesystem/filesystem/file"
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+var checksumAlgorithms = map[pb.ChecksumAlgorithm]file.ChecksumAlgorithm{
+	pb.ChecksumAlgorithm_SHA256: file.SHA256,
+	pb.ChecksumAlgorithm_SHA1:   file.SHA1,
+	pb.ChecksumAlgorithm_MD5:    file.MD5,
+	pb.ChecksumAlgorithm_CRC32C: file.CRC32C,
+}
+
+func (daemon *FileSystemDaemon) Checksum(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - checksum request recevied: {%+v}", request.GetSessionId(), request)
+	checksumReq := request.GetChecksum()
+	if checksumReq == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	algorithm, found := checksumAlgorithms[checksumReq.GetAlgorithm()]
+	if !found {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	path, err := daemon.getPath(request, func() string { return checksumReq.GetPath() })
+	if err != nil {
+		log.Printf("%s - checksum path error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	workDir := path.WorkingDir()
+	digest, err := daemon.fs.Checksum(path, algorithm)
+	if err != nil {
+		log.Printf("%s - checksum fs error: %s", request.GetSessionId(), err.Error())
+		return daemon.extractError(request.GetSessionId(), workDir, err)
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_Checksum{
+			Checksum: &pb.ChecksumResponse{Digest: hex.EncodeToString(digest)},
+		},
+	}, nil
+}
@@ -0,0 +1,50 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	pb "material/filesystem/pb/proto/fsservice"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func (daemon *FileSystemDaemon) Commit(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - commit request recevied: {%+v}", request.GetSessionId(), request)
+	if request.GetCommit() == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	workDir, err := daemon.sessionStore.GetWorkingDirectoryForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - commit session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	tx, err := daemon.sessionStore.GetTransactionForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - commit session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+	if tx == nil {
+		return &pb.Response{
+			Error:          proto.String("no open transaction"),
+			WorkingDirPath: workDir.Info().AbsolutePath(),
+		}, nil
+	}
+
+	// the transaction is closed even if the commit fails
+	err = tx.Commit()
+	daemon.sessionStore.SetTransaction(request.GetSessionId(), nil)
+	if err != nil {
+		log.Printf("%s - commit fs error: %s", request.GetSessionId(), err.Error())
+		return daemon.extractError(request.GetSessionId(), workDir, err)
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_Commit{
+			Commit: &pb.CommitResponse{},
+		},
+	}, nil
+}
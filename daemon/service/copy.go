@@ -27,16 +27,28 @@ func (daemon *FileSystemDaemon) Copy(ctx context.Context, request *pb.Request) (
 	}
 
 	workDir := srcPath.WorkingDir()
-	file, err := daemon.fs.Copy(srcPath, destPath)
+	copyFile := daemon.operations(request.GetSessionId()).CopyWithReport
+	if cpReq.GetPreserveLinks() {
+		copyFile = daemon.operations(request.GetSessionId()).CopyPreservingLinks
+	}
+	file, report, err := copyFile(srcPath, destPath, cpReq.GetContinueOnError())
+	response := &pb.CopyResponse{Report: toPbReport(report)}
+	if file != nil {
+		response.Name = file.Name()
+	}
 	if err != nil {
 		log.Printf("%s - copy daemon error: %s", request.GetSessionId(), err.Error())
-		return daemon.extractError(request.GetSessionId(), workDir, err)
+		resp, err := daemon.extractError(request.GetSessionId(), workDir, err)
+		if resp != nil {
+			resp.Response = &pb.Response_Copy{Copy: response}
+		}
+		return resp, err
 	}
 
 	return &pb.Response{
 		WorkingDirPath: workDir.Info().AbsolutePath(),
 		Response: &pb.Response_Copy{
-			Copy: &pb.CopyResponse{Name: file.Name()},
+			Copy: response,
 		},
 	}, nil
 }
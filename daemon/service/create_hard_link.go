@@ -27,7 +27,7 @@ func (daemon *FileSystemDaemon) CreateHardLink(ctx context.Context, request *pb.
 	}
 
 	workDir := srcPath.WorkingDir()
-	file, err := daemon.fs.CreateHardLink(srcPath, destPath)
+	file, err := daemon.operations(request.GetSessionId()).CreateHardLink(srcPath, destPath)
 	if err != nil {
 		log.Printf("%s - createHardLink fs error: %s", request.GetSessionId(), err.Error())
 		return daemon.extractError(request.GetSessionId(), workDir, err)
@@ -21,7 +21,7 @@ func (daemon *FileSystemDaemon) CreateRegularFile(ctx context.Context, request *
 		return nil, err
 	}
 
-	file, err := daemon.fs.CreateRegularFile(path)
+	file, err := daemon.operations(request.GetSessionId()).CreateRegularFile(path)
 	workDir := path.WorkingDir()
 	if err != nil {
 		log.Printf("%s - createRegularFile fs error: %s", request.GetSessionId(), err.Error())
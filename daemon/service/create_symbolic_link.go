@@ -27,7 +27,7 @@ func (daemon *FileSystemDaemon) CreateSymbolicLink(ctx context.Context, request
 	}
 
 	workDir := srcPath.WorkingDir()
-	file, err := daemon.fs.CreateSymbolicLink(srcPath, destPath)
+	file, err := daemon.operations(request.GetSessionId()).CreateSymbolicLink(srcPath, destPath)
 	if err != nil {
 		log.Printf("%s - createSymbolicLink fs error: %s", request.GetSessionId(), err.Error())
 		return daemon.extractError(request.GetSessionId(), workDir, err)
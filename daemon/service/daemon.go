@@ -5,6 +5,10 @@ import (
 	"log"
 	"material/filesystem/daemon/session"
 	"material/filesystem/filesystem"
+	"material/filesystem/filesystem/fscrypt"
+	"material/filesystem/filesystem/fstrash"
+	"material/filesystem/filesystem/fsversion"
+	"material/filesystem/filesystem/memoryfs"
 	pbFs "material/filesystem/pb/proto/fsservice"
 	pbSession "material/filesystem/pb/proto/session"
 
@@ -17,12 +21,14 @@ import (
 type FileSystemDaemon struct {
 	fs           filesystem.FileSystem
 	sessionStore *session.SessionStore
+	// if true, removed files are moved to the session user trash
+	trashEnabled bool
 	pbSession.UnimplementedSessionServiceServer
 	pbFs.UnimplementedFileSystemServiceServer
 }
 
-func NewFileSystemDaemon(fsType filesystem.FileSystemType) (*FileSystemDaemon, error) {
-	fs, err := filesystem.NewFileSystem(fsType)
+func NewFileSystemDaemon(fsType filesystem.FileSystemType, options ...memoryfs.Option) (*FileSystemDaemon, error) {
+	fs, err := filesystem.NewFileSystem(fsType, options...)
 	if err != nil {
 		return nil, fmt.Errorf("error creating filesystem: %w", err)
 	}
@@ -34,6 +40,23 @@ func NewFileSystemDaemon(fsType filesystem.FileSystemType) (*FileSystemDaemon, e
 	}, nil
 }
 
+// SetKeyProvider sets the provider of the master keys used to encrypt the file contents
+func (daemon *FileSystemDaemon) SetKeyProvider(keys fscrypt.KeyProvider) {
+	daemon.fs.SetKeyProvider(keys)
+}
+
+// EnableTrash moves the removed files to the session user trash,
+// applying the given retention
+func (daemon *FileSystemDaemon) EnableTrash(retention fstrash.Retention) {
+	daemon.trashEnabled = true
+	daemon.fs.SetTrashRetention(retention)
+}
+
+// SetVersionPolicy sets the limits of the versions kept for every regular file
+func (daemon *FileSystemDaemon) SetVersionPolicy(policy fsversion.Policy) {
+	daemon.fs.SetVersionPolicy(policy)
+}
+
 func (daemon *FileSystemDaemon) Run(port string) error {
 	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%s", port))
 	if err != nil {
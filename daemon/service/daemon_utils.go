@@ -5,6 +5,8 @@ import (
 	"material/filesystem/filesystem/file"
 	"material/filesystem/filesystem/fserrors"
 	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsreport"
+	"material/filesystem/filesystem/fstx"
 	pb "material/filesystem/pb/proto/fsservice"
 
 	"google.golang.org/protobuf/proto"
@@ -32,15 +34,17 @@ func (daemon *FileSystemDaemon) maybeChangeWorkDirectory(sessionId string, workD
 	return workDir
 }
 
-func (daemon *FileSystemDaemon) updateWorkingDirectory(sessionId string, deletedFile file.FileInfo) (file.File, error) {
+func (daemon *FileSystemDaemon) updateWorkingDirectory(sessionId string, deletedPaths ...string) (file.File, error) {
 	workingDir, err := daemon.sessionStore.GetWorkingDirectoryForSession(sessionId)
 	if err != nil {
 		return nil, err
 	}
 
-	if workingDir.Info().AbsolutePath() == deletedFile.AbsolutePath() {
-		daemon.sessionStore.ChangeWorkingDirectory(sessionId, daemon.fs.DefaultWorkingDirectory())
-		return daemon.fs.DefaultWorkingDirectory(), nil
+	for _, deletedPath := range deletedPaths {
+		if workingDir.Info().AbsolutePath() == deletedPath {
+			daemon.sessionStore.ChangeWorkingDirectory(sessionId, daemon.fs.DefaultWorkingDirectory())
+			return daemon.fs.DefaultWorkingDirectory(), nil
+		}
 	}
 
 	return workingDir, nil
@@ -54,3 +58,22 @@ func (daemon *FileSystemDaemon) getPath(req *pb.Request, pathpathExtractorFn pat
 
 	return fspath.NewFileSystemPath(pathpathExtractorFn(), workingDir)
 }
+
+func toPbReport(report *fsreport.Report) *pb.Report {
+	pbReport := &pb.Report{Succeeded: report.Succeeded}
+	for _, failure := range report.Failed {
+		pbReport.Failed = append(pbReport.Failed, &pb.FileFailure{
+			Path:  failure.Path,
+			Error: failure.Err.Error(),
+		})
+	}
+	return pbReport
+}
+
+// operations returns the open transaction of the session if any, the file system otherwise
+func (daemon *FileSystemDaemon) operations(sessionId string) fstx.Operations {
+	if tx, err := daemon.sessionStore.GetTransactionForSession(sessionId); err == nil && tx != nil {
+		return tx
+	}
+	return daemon.fs
+}
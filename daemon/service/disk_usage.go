@@ -0,0 +1,49 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+func (daemon *FileSystemDaemon) DiskUsage(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - diskUsage request recevied: {%+v}", request.GetSessionId(), request)
+	duReq := request.GetDiskUsage()
+	if duReq == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	path, err := daemon.getPath(request, func() string { return duReq.GetPath() })
+	if err != nil {
+		log.Printf("%s - diskUsage path error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	workDir := path.WorkingDir()
+	usages, err := daemon.fs.DiskUsage(path)
+	if err != nil {
+		log.Printf("%s - diskUsage fs error: %s", request.GetSessionId(), err.Error())
+		return daemon.extractError(request.GetSessionId(), workDir, err)
+	}
+
+	resp := &pb.DiskUsageResponse{}
+	for _, usage := range usages {
+		if duReq.MaxDepth != nil && usage.Depth > int(duReq.GetMaxDepth()) {
+			continue
+		}
+		resp.Usages = append(resp.Usages, &pb.DiskUsage{
+			Path:           usage.AbsolutePath,
+			ApparentSize:   int64(usage.ApparentSize),
+			AllocatedBytes: int64(usage.AllocatedBytes),
+			Inodes:         int64(usage.Inodes),
+		})
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_DiskUsage{
+			DiskUsage: resp,
+		},
+	}, nil
+}
@@ -0,0 +1,47 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"material/filesystem/filesystem/fstrash"
+	pb "material/filesystem/pb/proto/fsservice"
+	"time"
+)
+
+func (daemon *FileSystemDaemon) EmptyTrash(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - emptyTrash request recevied: {%+v}", request.GetSessionId(), request)
+	emptyReq := request.GetEmptyTrash()
+	if emptyReq == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	workDir, err := daemon.sessionStore.GetWorkingDirectoryForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - emptyTrash session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+	user, err := daemon.sessionStore.GetUserForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - emptyTrash session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	// without limits the whole trash is purged
+	var retention *fstrash.Retention
+	if emptyReq.MaxAge != nil || emptyReq.MaxSize != nil {
+		retention = &fstrash.Retention{
+			MaxAge:  time.Duration(emptyReq.GetMaxAge()) * time.Second,
+			MaxSize: int(emptyReq.GetMaxSize()),
+		}
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_EmptyTrash{
+			EmptyTrash: &pb.EmptyTrashResponse{
+				Entries: toPbTrashEntries(daemon.fs.EmptyTrash(user, retention)),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,299 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fsquery"
+	pb "material/filesystem/pb/proto/fsservice"
+	"time"
+)
+
+var fileTypes = map[pb.FileType]file.FileType{
+	pb.FileType_REGULAR_FILE:  file.RegularFile,
+	pb.FileType_DIRECTORY:     file.Directory,
+	pb.FileType_SYMBOLIC_LINK: file.SymbolicLink,
+	pb.FileType_FIFO:          file.Fifo,
+}
+
+func (daemon *FileSystemDaemon) FindFilesV2(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - findFilesV2 request recevied: {%+v}", request.GetSessionId(), request)
+	findReq := request.GetFindV2()
+	if findReq == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	path, err := daemon.getPath(request, func() string { return findReq.GetPath() })
+	if err != nil {
+		log.Printf("%s - findFilesV2 path error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	workDir := path.WorkingDir()
+	query, err := daemon.buildFindQuery(request, findReq)
+	if err != nil {
+		log.Printf("%s - findFilesV2 query error: %s", request.GetSessionId(), err.Error())
+		return daemon.extractError(request.GetSessionId(), workDir, err)
+	}
+
+	files, err := daemon.fs.Find(path, query)
+	if err != nil {
+		log.Printf("%s - findFilesV2 fs error: %s", request.GetSessionId(), err.Error())
+		return daemon.extractError(request.GetSessionId(), workDir, err)
+	}
+
+	paths := []string{}
+	for _, stat := range files {
+		paths = append(paths, stat.AbsolutePath())
+		// The working directory could have been deleted
+		if query.Action != fsquery.ActionList {
+			workDir, err = daemon.updateWorkingDirectory(request.GetSessionId(), stat.AbsolutePath())
+			if err != nil {
+				log.Printf("%s - findFilesV2 update working directory error: %s", request.GetSessionId(), err.Error())
+				return nil, err
+			}
+		}
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_Find{
+			Find: &pb.FindFilesResponse{Paths: paths},
+		},
+	}, nil
+}
+
+// buildFindQuery converts the request into a query combining every criteria and the match predicate with AND.
+// The deleted files are moved to the session user trash when the trash is enabled.
+//
+// Returns an error if any pattern or file type is invalid, the newer file doesn't exist or the session user is unknown.
+func (daemon *FileSystemDaemon) buildFindQuery(request *pb.Request, findReq *pb.FindFilesV2Request) (*fsquery.Query, error) {
+	query := fsquery.NewQuery()
+	predicates := []fsquery.Predicate{}
+
+	if findReq.NameRegex != nil {
+		predicate, err := fsquery.NameRegex(findReq.GetNameRegex())
+		if err != nil {
+			return nil, fserrors.ErrInvalid
+		}
+		predicates = append(predicates, predicate)
+	}
+
+	if findReq.Name != nil {
+		predicate, err := fsquery.NameGlob(findReq.GetName())
+		if err != nil {
+			return nil, fserrors.ErrInvalid
+		}
+		predicates = append(predicates, predicate)
+	}
+
+	if findReq.PathRegex != nil {
+		predicate, err := fsquery.PathRegex(findReq.GetPathRegex())
+		if err != nil {
+			return nil, fserrors.ErrInvalid
+		}
+		predicates = append(predicates, predicate)
+	}
+
+	if findReq.Type != nil {
+		fileType, found := fileTypes[findReq.GetType()]
+		if !found {
+			return nil, fserrors.ErrInvalid
+		}
+		predicates = append(predicates, fsquery.Type(fileType))
+	}
+
+	if findReq.MinSize != nil || findReq.MaxSize != nil {
+		maxSize := -1
+		if findReq.MaxSize != nil {
+			maxSize = int(findReq.GetMaxSize())
+		}
+		predicates = append(predicates, fsquery.SizeRange(int(findReq.GetMinSize()), maxSize))
+	}
+
+	if findReq.Newer != nil {
+		predicate, err := daemon.newerPredicate(request, findReq.GetNewer())
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, predicate)
+	}
+
+	if findReq.GetEmpty() {
+		predicates = append(predicates, fsquery.Empty())
+	}
+
+	if findReq.Match != nil {
+		predicate, err := daemon.buildFindPredicate(request, findReq.GetMatch())
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, predicate)
+	}
+
+	prune := []fsquery.Predicate{}
+	for _, pattern := range findReq.GetPrune() {
+		predicate, err := fsquery.NameGlob(pattern)
+		if err != nil {
+			return nil, fserrors.ErrInvalid
+		}
+		prune = append(prune, predicate)
+	}
+	if len(prune) > 0 {
+		query.Prune = fsquery.Or(prune...)
+	}
+
+	if findReq.MinDepth != nil {
+		query.MinDepth = int(findReq.GetMinDepth())
+	}
+	if findReq.MaxDepth != nil {
+		query.MaxDepth = int(findReq.GetMaxDepth())
+	}
+
+	query.Match = fsquery.And(predicates...)
+	query.FollowLinks = findReq.GetFollowLinks()
+	if findReq.GetDelete() && daemon.trashEnabled {
+		user, err := daemon.sessionStore.GetUserForSession(request.GetSessionId())
+		if err != nil {
+			return nil, err
+		}
+		query.Action = fsquery.ActionTrash
+		query.User = user
+	} else if findReq.GetDelete() {
+		query.Action = fsquery.ActionDelete
+	}
+	return query, nil
+}
+
+// buildFindPredicate converts the predicate tree into a query predicate.
+//
+// Returns an error if any pattern is malformed, a newer file doesn't exist
+// or a predicate is not set.
+func (daemon *FileSystemDaemon) buildFindPredicate(request *pb.Request, predicate *pb.FindPredicate) (fsquery.Predicate, error) {
+	switch p := predicate.GetPredicate().(type) {
+	case *pb.FindPredicate_And:
+		predicates, err := daemon.buildFindPredicates(request, p.And.GetPredicates())
+		if err != nil {
+			return nil, err
+		}
+		return fsquery.And(predicates...), nil
+	case *pb.FindPredicate_Or:
+		predicates, err := daemon.buildFindPredicates(request, p.Or.GetPredicates())
+		if err != nil {
+			return nil, err
+		}
+		return fsquery.Or(predicates...), nil
+	case *pb.FindPredicate_Not:
+		predicate, err := daemon.buildFindPredicate(request, p.Not)
+		if err != nil {
+			return nil, err
+		}
+		return fsquery.Not(predicate), nil
+	case *pb.FindPredicate_Leaf:
+		return daemon.buildFindCriterion(request, p.Leaf)
+	default:
+		return nil, fserrors.ErrInvalid
+	}
+}
+
+// buildFindPredicates converts every predicate of the list
+func (daemon *FileSystemDaemon) buildFindPredicates(request *pb.Request, predicates []*pb.FindPredicate) ([]fsquery.Predicate, error) {
+	result := []fsquery.Predicate{}
+	for _, predicate := range predicates {
+		p, err := daemon.buildFindPredicate(request, predicate)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// buildFindCriterion converts a single criterion into a query predicate
+func (daemon *FileSystemDaemon) buildFindCriterion(request *pb.Request, criterion *pb.FindCriterion) (fsquery.Predicate, error) {
+	var predicate fsquery.Predicate
+	var err error
+
+	switch c := criterion.GetCriterion().(type) {
+	case *pb.FindCriterion_NameRegex:
+		predicate, err = fsquery.NameRegex(c.NameRegex)
+	case *pb.FindCriterion_Name:
+		predicate, err = fsquery.NameGlob(c.Name)
+	case *pb.FindCriterion_PathRegex:
+		predicate, err = fsquery.PathRegex(c.PathRegex)
+	case *pb.FindCriterion_Type:
+		fileType, found := fileTypes[c.Type]
+		if !found {
+			return nil, fserrors.ErrInvalid
+		}
+		predicate = fsquery.Type(fileType)
+	case *pb.FindCriterion_Size:
+		min, max := intRange(c.Size)
+		predicate = fsquery.SizeRange(min, max)
+	case *pb.FindCriterion_ModTime:
+		after, before := timeRange(c.ModTime)
+		predicate = fsquery.ModTimeRange(after, before)
+	case *pb.FindCriterion_ChangeTime:
+		after, before := timeRange(c.ChangeTime)
+		predicate = fsquery.ChangeTimeRange(after, before)
+	case *pb.FindCriterion_Links:
+		min, max := intRange(c.Links)
+		predicate = fsquery.LinkCount(min, max)
+	case *pb.FindCriterion_Newer:
+		return daemon.newerPredicate(request, c.Newer)
+	case *pb.FindCriterion_Empty:
+		predicate = fsquery.Empty()
+		if !c.Empty {
+			predicate = fsquery.Not(predicate)
+		}
+	default:
+		return nil, fserrors.ErrInvalid
+	}
+
+	if err != nil {
+		return nil, fserrors.ErrInvalid
+	}
+	return predicate, nil
+}
+
+// newerPredicate matches the files modified more recently than the named file.
+//
+// Returns an error if the file doesn't exist.
+func (daemon *FileSystemDaemon) newerPredicate(request *pb.Request, newer string) (fsquery.Predicate, error) {
+	newerPath, err := daemon.getPath(request, func() string { return newer })
+	if err != nil {
+		return nil, err
+	}
+	stat, err := daemon.fs.Stat(newerPath)
+	if err != nil {
+		return nil, err
+	}
+	// strictly newer
+	return fsquery.ModTimeRange(stat.ModTime().Add(time.Nanosecond), time.Time{}), nil
+}
+
+// intRange converts the range into the min and max of a query predicate,
+// a negative max means no upper bound
+func intRange(r *pb.FindRange) (int, int) {
+	max := -1
+	if r.Max != nil {
+		max = int(r.GetMax())
+	}
+	return int(r.GetMin()), max
+}
+
+// timeRange converts the range in seconds since the epoch into the bounds of a query predicate,
+// a zero time means no bound
+func timeRange(r *pb.FindRange) (time.Time, time.Time) {
+	after, before := time.Time{}, time.Time{}
+	if r.Min != nil {
+		after = time.Unix(r.GetMin(), 0)
+	}
+	if r.Max != nil {
+		// include the whole last second
+		before = time.Unix(r.GetMax(), int64(time.Second-time.Nanosecond))
+	}
+	return after, before
+}
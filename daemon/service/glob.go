@@ -0,0 +1,41 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+func (daemon *FileSystemDaemon) Glob(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - glob request recevied: {%+v}", request.GetSessionId(), request)
+	globReq := request.GetGlob()
+	if globReq == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	pattern, err := daemon.getPath(request, func() string { return globReq.GetPattern() })
+	if err != nil {
+		log.Printf("%s - glob path error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	files, err := daemon.fs.Glob(pattern)
+	workDir := pattern.WorkingDir()
+	if err != nil {
+		log.Printf("%s - glob fs error: %s", request.GetSessionId(), err.Error())
+		return daemon.extractError(request.GetSessionId(), workDir, err)
+	}
+
+	paths := []string{}
+	for _, info := range files {
+		paths = append(paths, info.AbsolutePath())
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_Glob{
+			Glob: &pb.GlobResponse{Paths: paths},
+		},
+	}, nil
+}
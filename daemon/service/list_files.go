@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"material/filesystem/filesystem/file"
 	pb "material/filesystem/pb/proto/fsservice"
 )
 
@@ -21,21 +22,32 @@ func (daemon *FileSystemDaemon) ListFiles(ctx context.Context, request *pb.Reque
 	}
 
 	workDir := path.WorkingDir()
-	files, err := daemon.fs.ListFiles(path)
+	var files []file.FileInfo
+	nextCursor := ""
+	if lsReq.Limit != nil {
+		files, nextCursor, err = daemon.operations(request.GetSessionId()).ListFilesPage(path, lsReq.GetCursor(), int(lsReq.GetLimit()))
+	} else {
+		files, err = daemon.operations(request.GetSessionId()).ListFiles(path)
+	}
 	if err != nil {
 		log.Printf("%s - listFiles fs error: %s", request.GetSessionId(), err.Error())
 		return daemon.extractError(request.GetSessionId(), workDir, err)
 	}
 
-	names := []string{}
-	for _, info := range files {
-		names = append(names, info.Name())
-	}
-
 	return &pb.Response{
 		WorkingDirPath: workDir.Info().AbsolutePath(),
 		Response: &pb.Response_List{
-			List: &pb.ListFilesResponse{Names: names},
+			List: toListFilesResponse(files, nextCursor),
 		},
 	}, nil
 }
+
+func toListFilesResponse(files []file.FileInfo, nextCursor string) *pb.ListFilesResponse {
+	names := []string{}
+	types := []pb.FileType{}
+	for _, info := range files {
+		names = append(names, info.Name())
+		types = append(types, toPbFileType(info.FileType()))
+	}
+	return &pb.ListFilesResponse{Names: names, Types: types, NextCursor: nextCursor}
+}
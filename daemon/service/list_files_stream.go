@@ -0,0 +1,56 @@
+package daemon
+
+import (
+	"fmt"
+	"log"
+
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+// listFilesStreamPageSize is the number of files sent in each response when the request has no limit
+const listFilesStreamPageSize = 1000
+
+// ListFilesStream sends the files in the directory one page per response, in lexical order.
+func (daemon *FileSystemDaemon) ListFilesStream(request *pb.Request, stream pb.FileSystemService_ListFilesStreamServer) error {
+	log.Printf("%s - listFilesStream request recevied: {%+v}", request.GetSessionId(), request)
+	lsReq := request.GetList()
+	if lsReq == nil {
+		return fmt.Errorf("invalid request")
+	}
+
+	path, err := daemon.getPath(request, func() string { return lsReq.GetPath() })
+	if err != nil {
+		log.Printf("%s - listFilesStream path error: %s", request.GetSessionId(), err.Error())
+		return err
+	}
+
+	limit := listFilesStreamPageSize
+	if lsReq.Limit != nil {
+		limit = int(lsReq.GetLimit())
+	}
+
+	workDir := path.WorkingDir()
+	cursor := lsReq.GetCursor()
+	for {
+		files, nextCursor, err := daemon.operations(request.GetSessionId()).ListFilesPage(path, cursor, limit)
+		if err != nil {
+			log.Printf("%s - listFilesStream fs error: %s", request.GetSessionId(), err.Error())
+			resp, err := daemon.extractError(request.GetSessionId(), workDir, err)
+			if err != nil {
+				return err
+			}
+			return stream.Send(resp)
+		}
+
+		err = stream.Send(&pb.Response{
+			WorkingDirPath: workDir.Info().AbsolutePath(),
+			Response: &pb.Response_List{
+				List: toListFilesResponse(files, nextCursor),
+			},
+		})
+		if err != nil || nextCursor == "" {
+			return err
+		}
+		cursor = nextCursor
+	}
+}
@@ -0,0 +1,49 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"material/filesystem/filesystem/fsstats"
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+func (daemon *FileSystemDaemon) ListOrphans(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - listOrphans request recevied: {%+v}", request.GetSessionId(), request)
+	if request.GetListOrphans() == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	workDir, err := daemon.sessionStore.GetWorkingDirectoryForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - listOrphans session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	orphans := []*pb.Orphan{}
+	for _, o := range daemon.fs.ListOrphans() {
+		orphans = append(orphans, toPbOrphan(o))
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_ListOrphans{
+			ListOrphans: &pb.ListOrphansResponse{
+				Orphans: orphans,
+			},
+		},
+	}, nil
+}
+
+func toPbOrphan(o *fsstats.Orphan) *pb.Orphan {
+	return &pb.Orphan{
+		Id:             o.ID,
+		OriginalPath:   o.OriginalPath,
+		FileType:       toPbFileType(o.FileType),
+		DeletedAt:      o.DeletedAt.Unix(),
+		Size:           int64(o.Size),
+		AllocatedBytes: int64(o.AllocatedBytes),
+		Descriptors:    int64(o.Descriptors),
+		Owners:         o.Owners,
+	}
+}
@@ -0,0 +1,35 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+func (daemon *FileSystemDaemon) ListTrash(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - listTrash request recevied: {%+v}", request.GetSessionId(), request)
+	if request.GetListTrash() == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	workDir, err := daemon.sessionStore.GetWorkingDirectoryForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - listTrash session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+	user, err := daemon.sessionStore.GetUserForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - listTrash session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_ListTrash{
+			ListTrash: &pb.ListTrashResponse{
+				Entries: toPbTrashEntries(daemon.fs.ListTrash(user)),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,46 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+func (daemon *FileSystemDaemon) ListVersions(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - listVersions request recevied: {%+v}", request.GetSessionId(), request)
+	listReq := request.GetListVersions()
+	if listReq == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	path, err := daemon.getPath(request, func() string { return listReq.GetPath() })
+	if err != nil {
+		log.Printf("%s - listVersions path error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	workDir := path.WorkingDir()
+	versions, err := daemon.fs.ListVersions(path)
+	if err != nil {
+		log.Printf("%s - listVersions fs error: %s", request.GetSessionId(), err.Error())
+		return daemon.extractError(request.GetSessionId(), workDir, err)
+	}
+
+	pbVersions := []*pb.FileVersion{}
+	for _, v := range versions {
+		pbVersions = append(pbVersions, &pb.FileVersion{
+			Id:        int64(v.ID),
+			Size:      int64(v.Size),
+			ModTime:   v.ModTime.Unix(),
+			CreatedAt: v.CreatedAt.Unix(),
+		})
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_ListVersions{
+			ListVersions: &pb.ListVersionsResponse{Versions: pbVersions},
+		},
+	}, nil
+}
@@ -24,9 +24,9 @@ func (daemon *FileSystemDaemon) Mkdir(ctx context.Context, request *pb.Request)
 
 	var file file.File
 	if mkdirReq.GetRecursive() {
-		file, err = daemon.fs.MkdirAll(path)
+		file, err = daemon.operations(request.GetSessionId()).MkdirAll(path)
 	} else {
-		file, err = daemon.fs.Mkdir(path)
+		file, err = daemon.operations(request.GetSessionId()).Mkdir(path)
 	}
 
 	workDir := path.WorkingDir()
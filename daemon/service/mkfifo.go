@@ -0,0 +1,37 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+func (daemon *FileSystemDaemon) Mkfifo(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - mkfifo request recevied: {%+v}", request.GetSessionId(), request)
+	mkfifoReq := request.GetMkfifo()
+	if mkfifoReq == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	path, err := daemon.getPath(request, func() string { return mkfifoReq.GetPath() })
+	if err != nil {
+		log.Printf("%s - mkfifo path error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	file, err := daemon.fs.Mkfifo(path)
+	workDir := path.WorkingDir()
+	if err != nil {
+		log.Printf("%s - mkfifo fs error: %s", request.GetSessionId(), err.Error())
+		return daemon.extractError(request.GetSessionId(), workDir, err)
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_Mkfifo{
+			Mkfifo: &pb.MkfifoResponse{Name: file.Info().Name()},
+		},
+	}, nil
+}
@@ -27,16 +27,24 @@ func (daemon *FileSystemDaemon) Move(ctx context.Context, request *pb.Request) (
 	}
 
 	workDir := srcPath.WorkingDir()
-	file, err := daemon.fs.Move(srcPath, destPath)
+	file, report, err := daemon.operations(request.GetSessionId()).MoveWithReport(srcPath, destPath, mvReq.GetContinueOnError())
+	response := &pb.MoveResponse{Report: toPbReport(report)}
+	if file != nil {
+		response.Name = file.Name()
+	}
 	if err != nil {
 		log.Printf("%s - move fs error: %s", request.GetSessionId(), err.Error())
-		return daemon.extractError(request.GetSessionId(), workDir, err)
+		resp, err := daemon.extractError(request.GetSessionId(), workDir, err)
+		if resp != nil {
+			resp.Response = &pb.Response_Move{Move: response}
+		}
+		return resp, err
 	}
 
 	return &pb.Response{
 		WorkingDirPath: workDir.Info().AbsolutePath(),
 		Response: &pb.Response_Move{
-			Move: &pb.MoveResponse{Name: file.Name()},
+			Move: response,
 		},
 	}, nil
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fspath"
 
 	pb "material/filesystem/pb/proto/fsservice"
 )
@@ -22,7 +24,7 @@ func (daemon *FileSystemDaemon) Open(ctx context.Context, request *pb.Request) (
 	}
 
 	workDir := path.WorkingDir()
-	fd, err := daemon.fs.Open(path)
+	fd, err := daemon.openFile(ctx, request.GetSessionId(), path, openFlags(openReq))
 	if err != nil {
 		log.Printf("%s - open fs error: %s", request.GetSessionId(), err.Error())
 		return daemon.extractError(request.GetSessionId(), workDir, err)
@@ -35,3 +37,27 @@ func (daemon *FileSystemDaemon) Open(ctx context.Context, request *pb.Request) (
 		},
 	}, nil
 }
+
+// openFlags returns the flags of the request, read and write by default
+func openFlags(openReq *pb.OpenRequest) file.OpenFlag {
+	var flags file.OpenFlag
+	if openReq.GetRead() {
+		flags |= file.OpenRead
+	}
+	if openReq.GetWrite() {
+		flags |= file.OpenWrite
+	}
+	if flags == 0 {
+		flags = file.OpenReadWrite
+	}
+	if openReq.GetNonBlocking() {
+		flags |= file.OpenNonBlocking
+	}
+	return flags
+}
+
+// openFile opens the file on behalf of the session, giving up if the request is cancelled while waiting
+// for the other end of a fifo.
+func (daemon *FileSystemDaemon) openFile(ctx context.Context, sessionId string, path *fspath.FileSystemPath, flags file.OpenFlag) (string, error) {
+	return daemon.fs.OpenFileContext(ctx, path, flags, sessionId)
+}
@@ -0,0 +1,36 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+func (daemon *FileSystemDaemon) PunchHole(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - punchHole request recevied: {%+v}", request.GetSessionId(), request)
+	punchReq := request.GetPunchHole()
+	if punchReq == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	workDir, err := daemon.sessionStore.GetWorkingDirectoryForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - punchHole path error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	err = daemon.fs.PunchHole(punchReq.GetFileDescriptor(), int(punchReq.GetOffset()), int(punchReq.GetLength()))
+	if err != nil {
+		log.Printf("%s - punchHole fs error: %s", request.GetSessionId(), err.Error())
+		return daemon.extractError(request.GetSessionId(), workDir, err)
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_PunchHole{
+			PunchHole: &pb.PunchHoleResponse{},
+		},
+	}, nil
+}
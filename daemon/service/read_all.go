@@ -22,7 +22,7 @@ func (daemon *FileSystemDaemon) ReadAll(ctx context.Context, request *pb.Request
 	}
 
 	workDir := path.WorkingDir()
-	content, err := daemon.fs.ReadAll(path)
+	content, err := daemon.operations(request.GetSessionId()).ReadAll(path)
 	if err != nil {
 		log.Printf("%s - readAll fs error: %s", request.GetSessionId(), err.Error())
 		return daemon.extractError(request.GetSessionId(), workDir, err)
@@ -0,0 +1,56 @@
+package daemon
+
+import (
+	"fmt"
+	"log"
+
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+// readStreamChunkSize is the maximum size of a streamed chunk
+const readStreamChunkSize = 32 << 10
+
+// ReadStream sends the content from the descriptor offset in chunks, until the end of the file.
+// For a fifo, it waits for the writers and ends when every writer is closed,
+// or when the client cancels the stream.
+func (daemon *FileSystemDaemon) ReadStream(request *pb.Request, stream pb.FileSystemService_ReadStreamServer) error {
+	log.Printf("%s - readStream request recevied: {%+v}", request.GetSessionId(), request)
+	readReq := request.GetReadStream()
+	if readReq == nil {
+		return fmt.Errorf("invalid request")
+	}
+
+	workDir, err := daemon.sessionStore.GetWorkingDirectoryForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - readStream path error: %s", request.GetSessionId(), err.Error())
+		return err
+	}
+
+	buff := make([]byte, readStreamChunkSize)
+	for {
+		n, err := daemon.fs.ReadContext(stream.Context(), readReq.GetFileDescriptor(), buff)
+		if err != nil {
+			log.Printf("%s - readStream fs error: %s", request.GetSessionId(), err.Error())
+			resp, err := daemon.extractError(request.GetSessionId(), workDir, err)
+			if err != nil {
+				return err
+			}
+			return stream.Send(resp)
+		}
+		if n == 0 {
+			return nil
+		}
+
+		err = stream.Send(&pb.Response{
+			WorkingDirPath: workDir.Info().AbsolutePath(),
+			Response: &pb.Response_ReadStream{
+				ReadStream: &pb.ReadStreamResponse{
+					Content: buff[:n],
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+func (daemon *FileSystemDaemon) ReadVersion(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - readVersion request recevied: {%+v}", request.GetSessionId(), request)
+	readReq := request.GetReadVersion()
+	if readReq == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	path, err := daemon.getPath(request, func() string { return readReq.GetPath() })
+	if err != nil {
+		log.Printf("%s - readVersion path error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	workDir := path.WorkingDir()
+	content, err := daemon.fs.ReadVersion(path, int(readReq.GetId()))
+	if err != nil {
+		log.Printf("%s - readVersion fs error: %s", request.GetSessionId(), err.Error())
+		return daemon.extractError(request.GetSessionId(), workDir, err)
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_ReadVersion{
+			ReadVersion: &pb.ReadVersionResponse{Content: content},
+		},
+	}, nil
+}
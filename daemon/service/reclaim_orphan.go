@@ -0,0 +1,37 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+func (daemon *FileSystemDaemon) ReclaimOrphan(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - reclaimOrphan request recevied: {%+v}", request.GetSessionId(), request)
+	reclaimReq := request.GetReclaimOrphan()
+	if reclaimReq == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	workDir, err := daemon.sessionStore.GetWorkingDirectoryForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - reclaimOrphan session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	orphan, err := daemon.fs.ReclaimOrphan(reclaimReq.GetId())
+	if err != nil {
+		log.Printf("%s - reclaimOrphan fs error: %s", request.GetSessionId(), err.Error())
+		return daemon.extractError(request.GetSessionId(), workDir, err)
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_ReclaimOrphan{
+			ReclaimOrphan: &pb.ReclaimOrphanResponse{
+				Orphan: toPbOrphan(orphan),
+			},
+		},
+	}, nil
+}
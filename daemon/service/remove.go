@@ -4,7 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsreport"
 	pb "material/filesystem/pb/proto/fsservice"
 )
 
@@ -22,29 +23,79 @@ func (daemon *FileSystemDaemon) Remove(ctx context.Context, request *pb.Request)
 		return nil, err
 	}
 
-	var file file.FileInfo
-	if rmReq.GetRecursive() {
-		file, err = daemon.fs.RemoveAll(path)
+	var removedPaths []string
+	var report *fsreport.Report
+	if rmReq.GetEmptyDir() {
+		removedPaths, err = daemon.rmdir(request.GetSessionId(), path)
+	} else if daemon.trashEnabled {
+		removedPaths, err = daemon.trash(request.GetSessionId(), path, rmReq.GetRecursive())
+	} else if rmReq.GetRecursive() {
+		report, err = daemon.operations(request.GetSessionId()).RemoveAllWithReport(path, rmReq.GetContinueOnError())
+		removedPaths = report.Succeeded
 	} else {
-		file, err = daemon.fs.Remove(path)
+		removedPaths, err = daemon.remove(request.GetSessionId(), path)
 	}
 
-	workDir := path.WorkingDir()
-	if err != nil {
-		log.Printf("%s - remove fs error: %s", request.GetSessionId(), err.Error())
-		return daemon.extractError(request.GetSessionId(), workDir, err)
+	workDir, wdErr := daemon.updateWorkingDirectory(request.GetSessionId(), removedPaths...)
+	if wdErr != nil {
+		log.Printf("%s - remove update working directory error: %s", request.GetSessionId(), wdErr.Error())
+		return nil, wdErr
+	}
+
+	var response *pb.Response_Remove
+	if report != nil {
+		response = &pb.Response_Remove{
+			Remove: &pb.RemoveResponse{Report: toPbReport(report)},
+		}
 	}
 
-	workDir, err = daemon.updateWorkingDirectory(request.GetSessionId(), file)
 	if err != nil {
-		log.Printf("%s - remove update working directory error: %s", request.GetSessionId(), err.Error())
-		return nil, err
+		log.Printf("%s - remove fs error: %s", request.GetSessionId(), err.Error())
+		resp, err := daemon.extractError(request.GetSessionId(), workDir, err)
+		if resp != nil && response != nil {
+			resp.Response = response
+		}
+		return resp, err
 	}
 
+	if response == nil {
+		response = &pb.Response_Remove{
+			Remove: &pb.RemoveResponse{},
+		}
+	}
 	return &pb.Response{
 		WorkingDirPath: workDir.Info().AbsolutePath(),
-		Response: &pb.Response_Remove{
-			Remove: &pb.RemoveResponse{},
-		},
+		Response:       response,
 	}, nil
 }
+
+// remove removes the file and returns its absolute path
+func (daemon *FileSystemDaemon) remove(sessionId string, path *fspath.FileSystemPath) ([]string, error) {
+	file, err := daemon.operations(sessionId).Remove(path)
+	if err != nil {
+		return nil, err
+	}
+	return []string{file.AbsolutePath()}, nil
+}
+
+// rmdir removes the empty directory and returns its absolute path
+func (daemon *FileSystemDaemon) rmdir(sessionId string, path *fspath.FileSystemPath) ([]string, error) {
+	dir, err := daemon.operations(sessionId).Rmdir(path)
+	if err != nil {
+		return nil, err
+	}
+	return []string{dir.AbsolutePath()}, nil
+}
+
+// trash moves the file to the session user trash and returns its original absolute path
+func (daemon *FileSystemDaemon) trash(sessionId string, path *fspath.FileSystemPath, isRecursive bool) ([]string, error) {
+	user, err := daemon.sessionStore.GetUserForSession(sessionId)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := daemon.operations(sessionId).Trash(path, user, isRecursive)
+	if err != nil {
+		return nil, err
+	}
+	return []string{entry.OriginalPath}, nil
+}
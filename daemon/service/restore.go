@@ -0,0 +1,52 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"material/filesystem/filesystem/fspath"
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+func (daemon *FileSystemDaemon) Restore(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - restore request recevied: {%+v}", request.GetSessionId(), request)
+	restoreReq := request.GetRestore()
+	if restoreReq == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	workDir, err := daemon.sessionStore.GetWorkingDirectoryForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - restore session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+	user, err := daemon.sessionStore.GetUserForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - restore session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	var newPath *fspath.FileSystemPath
+	if restoreReq.Path != nil {
+		newPath, err = daemon.getPath(request, func() string { return restoreReq.GetPath() })
+		if err != nil {
+			log.Printf("%s - restore path error: %s", request.GetSessionId(), err.Error())
+			return nil, err
+		}
+	}
+
+	info, err := daemon.fs.Restore(user, restoreReq.GetId(), newPath)
+	if err != nil {
+		log.Printf("%s - restore fs error: %s", request.GetSessionId(), err.Error())
+		return daemon.extractError(request.GetSessionId(), workDir, err)
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_Restore{
+			Restore: &pb.RestoreResponse{
+				Path: info.AbsolutePath(),
+			},
+		},
+	}, nil
+}
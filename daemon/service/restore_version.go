@@ -0,0 +1,35 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+func (daemon *FileSystemDaemon) RestoreVersion(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - restoreVersion request recevied: {%+v}", request.GetSessionId(), request)
+	restoreReq := request.GetRestoreVersion()
+	if restoreReq == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	path, err := daemon.getPath(request, func() string { return restoreReq.GetPath() })
+	if err != nil {
+		log.Printf("%s - restoreVersion path error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	workDir := path.WorkingDir()
+	if err := daemon.fs.RestoreVersion(path, int(restoreReq.GetId())); err != nil {
+		log.Printf("%s - restoreVersion fs error: %s", request.GetSessionId(), err.Error())
+		return daemon.extractError(request.GetSessionId(), workDir, err)
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_RestoreVersion{
+			RestoreVersion: &pb.RestoreVersionResponse{},
+		},
+	}, nil
+}
@@ -0,0 +1,45 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	pb "material/filesystem/pb/proto/fsservice"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func (daemon *FileSystemDaemon) Rollback(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - rollback request recevied: {%+v}", request.GetSessionId(), request)
+	if request.GetRollback() == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	workDir, err := daemon.sessionStore.GetWorkingDirectoryForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - rollback session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	tx, err := daemon.sessionStore.GetTransactionForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - rollback session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+	if tx == nil {
+		return &pb.Response{
+			Error:          proto.String("no open transaction"),
+			WorkingDirPath: workDir.Info().AbsolutePath(),
+		}, nil
+	}
+
+	tx.Rollback()
+	daemon.sessionStore.SetTransaction(request.GetSessionId(), nil)
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_Rollback{
+			Rollback: &pb.RollbackResponse{},
+		},
+	}, nil
+}
@@ -0,0 +1,44 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+func (daemon *FileSystemDaemon) RotateKeys(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - rotateKeys request recevied: {%+v}", request.GetSessionId(), request)
+	rotateReq := request.GetRotateKeys()
+	if rotateReq == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	workDir, err := daemon.sessionStore.GetWorkingDirectoryForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - rotateKeys session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	if rotateReq.Path == nil {
+		err = daemon.fs.RotateMasterKey()
+	} else {
+		path, pathErr := daemon.getPath(request, func() string { return rotateReq.GetPath() })
+		if pathErr != nil {
+			log.Printf("%s - rotateKeys path error: %s", request.GetSessionId(), pathErr.Error())
+			return nil, pathErr
+		}
+		err = daemon.fs.RotateDataKeys(path)
+	}
+	if err != nil {
+		log.Printf("%s - rotateKeys fs error: %s", request.GetSessionId(), err.Error())
+		return daemon.extractError(request.GetSessionId(), workDir, err)
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_RotateKeys{
+			RotateKeys: &pb.RotateKeysResponse{},
+		},
+	}, nil
+}
@@ -0,0 +1,42 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+func (daemon *FileSystemDaemon) Seek(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - seek request recevied: {%+v}", request.GetSessionId(), request)
+	seekReq := request.GetSeek()
+	if seekReq == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	workDir, err := daemon.sessionStore.GetWorkingDirectoryForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - seek path error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	seekFn := daemon.fs.SeekData
+	if seekReq.GetWhence() == pb.SeekWhence_HOLE {
+		seekFn = daemon.fs.SeekHole
+	}
+	offset, err := seekFn(seekReq.GetFileDescriptor(), int(seekReq.GetOffset()))
+	if err != nil {
+		log.Printf("%s - seek fs error: %s", request.GetSessionId(), err.Error())
+		return daemon.extractError(request.GetSessionId(), workDir, err)
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_Seek{
+			Seek: &pb.SeekResponse{
+				Offset: int64(offset),
+			},
+		},
+	}, nil
+}
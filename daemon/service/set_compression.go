@@ -0,0 +1,47 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"material/filesystem/filesystem/file"
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+var compressions = map[pb.Compression]file.Compression{
+	pb.Compression_NONE:  file.NoCompression,
+	pb.Compression_FLATE: file.Flate,
+	pb.Compression_GZIP:  file.Gzip,
+}
+
+func (daemon *FileSystemDaemon) SetCompression(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - setCompression request recevied: {%+v}", request.GetSessionId(), request)
+	compressionReq := request.GetSetCompression()
+	if compressionReq == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	compression, found := compressions[compressionReq.GetCompression()]
+	if !found {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	path, err := daemon.getPath(request, func() string { return compressionReq.GetPath() })
+	if err != nil {
+		log.Printf("%s - setCompression path error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	workDir := path.WorkingDir()
+	if err := daemon.fs.SetCompression(path, compression); err != nil {
+		log.Printf("%s - setCompression fs error: %s", request.GetSessionId(), err.Error())
+		return daemon.extractError(request.GetSessionId(), workDir, err)
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_SetCompression{
+			SetCompression: &pb.SetCompressionResponse{},
+		},
+	}, nil
+}
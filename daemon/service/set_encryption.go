@@ -0,0 +1,35 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+func (daemon *FileSystemDaemon) SetEncryption(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - setEncryption request recevied: {%+v}", request.GetSessionId(), request)
+	encryptionReq := request.GetSetEncryption()
+	if encryptionReq == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	path, err := daemon.getPath(request, func() string { return encryptionReq.GetPath() })
+	if err != nil {
+		log.Printf("%s - setEncryption path error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	workDir := path.WorkingDir()
+	if err := daemon.fs.SetEncryption(path, encryptionReq.GetEnabled()); err != nil {
+		log.Printf("%s - setEncryption fs error: %s", request.GetSessionId(), err.Error())
+		return daemon.extractError(request.GetSessionId(), workDir, err)
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_SetEncryption{
+			SetEncryption: &pb.SetEncryptionResponse{},
+		},
+	}, nil
+}
@@ -0,0 +1,40 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+func (daemon *FileSystemDaemon) StatFS(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - statFS request recevied: {%+v}", request.GetSessionId(), request)
+	if request.GetStatFS() == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	workDir, err := daemon.sessionStore.GetWorkingDirectoryForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - statFS session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	stats := daemon.fs.StatFS()
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_StatFS{
+			StatFS: &pb.StatFSResponse{
+				Inodes:          int64(stats.Inodes),
+				ApparentSize:    int64(stats.ApparentSize),
+				AllocatedBytes:  int64(stats.AllocatedBytes),
+				OpenDescriptors: int64(stats.OpenDescriptors),
+				OrphanedFiles:   int64(stats.OrphanedFiles),
+				OrphanedBytes:   int64(stats.OrphanedBytes),
+				Blocks:          int64(stats.Blocks),
+				StoredBytes:     int64(stats.StoredBytes),
+				ReferencedBytes: int64(stats.ReferencedBytes),
+				DedupRatio:      stats.DedupRatio(),
+			},
+		},
+	}, nil
+}
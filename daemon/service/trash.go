@@ -0,0 +1,82 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fstrash"
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+func (daemon *FileSystemDaemon) Trash(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - trash request recevied: {%+v}", request.GetSessionId(), request)
+	trashReq := request.GetTrash()
+	if trashReq == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	user, err := daemon.sessionStore.GetUserForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - trash session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	entries := []*fstrash.Entry{}
+	for _, p := range trashReq.GetPaths() {
+		path, err := daemon.getPath(request, func() string { return p })
+		if err != nil {
+			log.Printf("%s - trash path error: %s", request.GetSessionId(), err.Error())
+			return nil, err
+		}
+
+		entry, err := daemon.operations(request.GetSessionId()).Trash(path, user, trashReq.GetRecursive())
+		if err != nil {
+			log.Printf("%s - trash fs error: %s", request.GetSessionId(), err.Error())
+			return daemon.extractError(request.GetSessionId(), path.WorkingDir(), err)
+		}
+		if _, err := daemon.updateWorkingDirectory(request.GetSessionId(), entry.OriginalPath); err != nil {
+			log.Printf("%s - trash update working directory error: %s", request.GetSessionId(), err.Error())
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	workDir, err := daemon.sessionStore.GetWorkingDirectoryForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - trash session error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_Trash{
+			Trash: &pb.TrashResponse{
+				Entries: toPbTrashEntries(entries),
+			},
+		},
+	}, nil
+}
+
+func toPbTrashEntries(entries []*fstrash.Entry) []*pb.TrashEntry {
+	pbEntries := []*pb.TrashEntry{}
+	for _, entry := range entries {
+		pbEntries = append(pbEntries, &pb.TrashEntry{
+			Id:           entry.ID,
+			OriginalPath: entry.OriginalPath,
+			FileType:     toPbFileType(entry.FileType),
+			DeletedAt:    entry.DeletedAt.Unix(),
+			Size:         int64(entry.Size),
+		})
+	}
+	return pbEntries
+}
+
+func toPbFileType(fileType file.FileType) pb.FileType {
+	for pbFileType, t := range fileTypes {
+		if t == fileType {
+			return pbFileType
+		}
+	}
+	return pb.FileType_REGULAR_FILE
+}
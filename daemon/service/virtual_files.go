@@ -0,0 +1,87 @@
+package daemon
+
+import (
+	"bytes"
+	"fmt"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsvirtual"
+	"runtime"
+	"strings"
+)
+
+// MountVirtualFiles mounts the daemon state at /proc and the standard devices at /dev
+func (daemon *FileSystemDaemon) MountVirtualFiles() error {
+	mounts := map[string]fsvirtual.Node{
+		"/proc": daemon.procDir(),
+		"/dev":  fsvirtual.Devices(),
+	}
+	for mountPoint, node := range mounts {
+		path, err := fspath.NewFileSystemPath(mountPoint, nil)
+		if err != nil {
+			return err
+		}
+		if err := daemon.fs.Mount(path, node); err != nil {
+			return fmt.Errorf("error mounting %s: %w", mountPoint, err)
+		}
+	}
+	return nil
+}
+
+// procDir returns the virtual files exposing the daemon state
+func (daemon *FileSystemDaemon) procDir() fsvirtual.Dir {
+	return fsvirtual.Dir{
+		"sessions": fsvirtual.FileFuncs{ReadFn: daemon.procSessions},
+		"fs":       fsvirtual.FileFuncs{ReadFn: daemon.procFS},
+		"memory":   fsvirtual.FileFuncs{ReadFn: daemon.procMemory},
+		"orphans":  fsvirtual.FileFuncs{ReadFn: daemon.procOrphans},
+	}
+}
+
+// procSessions prints one session per line: id, user, working directory and transaction state
+func (daemon *FileSystemDaemon) procSessions() ([]byte, error) {
+	var buff bytes.Buffer
+	for _, s := range daemon.sessionStore.ListSessions() {
+		fmt.Fprintf(&buff, "%s\t%s\t%s\t%t\n", s.SessionId, s.User, s.WorkingDirectory, s.InTransaction)
+	}
+	return buff.Bytes(), nil
+}
+
+// procFS prints the file system usage, including the open file descriptors
+func (daemon *FileSystemDaemon) procFS() ([]byte, error) {
+	stats := daemon.fs.StatFS()
+	var buff bytes.Buffer
+	fmt.Fprintf(&buff, "inodes %d\n", stats.Inodes)
+	fmt.Fprintf(&buff, "apparent_size %d\n", stats.ApparentSize)
+	fmt.Fprintf(&buff, "allocated_bytes %d\n", stats.AllocatedBytes)
+	fmt.Fprintf(&buff, "open_descriptors %d\n", stats.OpenDescriptors)
+	fmt.Fprintf(&buff, "orphaned_files %d\n", stats.OrphanedFiles)
+	fmt.Fprintf(&buff, "orphaned_bytes %d\n", stats.OrphanedBytes)
+	fmt.Fprintf(&buff, "blocks %d\n", stats.Blocks)
+	fmt.Fprintf(&buff, "stored_bytes %d\n", stats.StoredBytes)
+	fmt.Fprintf(&buff, "referenced_bytes %d\n", stats.ReferencedBytes)
+	fmt.Fprintf(&buff, "cached_paths %d\n", stats.CachedPaths)
+	return buff.Bytes(), nil
+}
+
+// procOrphans prints one deleted file still open per line:
+// id, original path, allocated bytes, descriptors and owning sessions
+func (daemon *FileSystemDaemon) procOrphans() ([]byte, error) {
+	var buff bytes.Buffer
+	for _, o := range daemon.fs.ListOrphans() {
+		fmt.Fprintf(&buff, "%s\t%s\t%d\t%d\t%s\n", o.ID, o.OriginalPath, o.AllocatedBytes, o.Descriptors, strings.Join(o.Owners, ","))
+	}
+	return buff.Bytes(), nil
+}
+
+// procMemory prints the memory used by the daemon process
+func (daemon *FileSystemDaemon) procMemory() ([]byte, error) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	var buff bytes.Buffer
+	fmt.Fprintf(&buff, "heap_alloc %d\n", mem.HeapAlloc)
+	fmt.Fprintf(&buff, "heap_sys %d\n", mem.HeapSys)
+	fmt.Fprintf(&buff, "sys %d\n", mem.Sys)
+	fmt.Fprintf(&buff, "num_gc %d\n", mem.NumGC)
+	fmt.Fprintf(&buff, "goroutines %d\n", runtime.NumGoroutine())
+	return buff.Bytes(), nil
+}
@@ -0,0 +1,39 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	pb "material/filesystem/pb/proto/fsservice"
+)
+
+func (daemon *FileSystemDaemon) Write(ctx context.Context, request *pb.Request) (*pb.Response, error) {
+	log.Printf("%s - write request recevied: {%+v}", request.GetSessionId(), request)
+
+	writeReq := request.GetWrite()
+	if writeReq == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	workDir, err := daemon.sessionStore.GetWorkingDirectoryForSession(request.GetSessionId())
+	if err != nil {
+		log.Printf("%s - write path error: %s", request.GetSessionId(), err.Error())
+		return nil, err
+	}
+
+	size, err := daemon.fs.Write(writeReq.GetFileDescriptor(), writeReq.GetContent())
+	if err != nil {
+		log.Printf("%s - write fs error: %s", request.GetSessionId(), err.Error())
+		return daemon.extractError(request.GetSessionId(), workDir, err)
+	}
+
+	return &pb.Response{
+		WorkingDirPath: workDir.Info().AbsolutePath(),
+		Response: &pb.Response_Write{
+			Write: &pb.WriteResponse{
+				NBytes: int32(size),
+			},
+		},
+	}, nil
+}
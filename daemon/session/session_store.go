@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"log"
 	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fstrash"
+	"material/filesystem/filesystem/fstx"
 	pb "material/filesystem/pb/proto/session"
+	"sort"
 	"sync"
 
 	"github.com/google/uuid"
@@ -12,7 +15,19 @@ import (
 
 type session struct {
 	sessionId        string
+	user             string
 	workingDirectory file.File
+	// open transaction, nil if the session is not in a transaction
+	transaction fstx.Transaction
+}
+
+// SessionInfo describes an open session
+type SessionInfo struct {
+	SessionId        string
+	User             string
+	WorkingDirectory string
+	// InTransaction is true if the session has an open transaction
+	InTransaction bool
 }
 
 // SessionStore stores any open "shell".
@@ -36,8 +51,13 @@ func (store *SessionStore) AddSession(request *pb.NewSessionRequest, workingDire
 	store.mutex.Lock()
 	defer store.mutex.Unlock()
 
+	user := request.GetUser()
+	if user == "" {
+		user = fstrash.DefaultUser
+	}
 	session := &session{
 		sessionId:        uuid.NewString(),
+		user:             user,
 		workingDirectory: workingDirectory,
 	}
 	// This should never happen
@@ -59,9 +79,13 @@ func (store *SessionStore) DeleteSession(request *pb.DeleteSessionRequest) (*pb.
 	store.mutex.Lock()
 	defer store.mutex.Unlock()
 
-	if _, found := store.sessions[request.SessionId]; !found {
+	session, found := store.sessions[request.SessionId]
+	if !found {
 		return nil, fmt.Errorf("session not found")
 	}
+	if session.transaction != nil {
+		session.transaction.Rollback()
+	}
 	delete(store.sessions, request.SessionId)
 	log.Printf("session deleted: %s", request.GetSessionId())
 
@@ -70,6 +94,24 @@ func (store *SessionStore) DeleteSession(request *pb.DeleteSessionRequest) (*pb.
 	}, nil
 }
 
+// ListSessions returns the open sessions sorted by id
+func (store *SessionStore) ListSessions() []SessionInfo {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	sessions := []SessionInfo{}
+	for _, session := range store.sessions {
+		sessions = append(sessions, SessionInfo{
+			SessionId:        session.sessionId,
+			User:             session.user,
+			WorkingDirectory: session.workingDirectory.Info().AbsolutePath(),
+			InTransaction:    session.transaction != nil,
+		})
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].SessionId < sessions[j].SessionId })
+	return sessions
+}
+
 // GetWorkingDirectoryForSession get the working directory for
 // the given sessionId.
 //
@@ -90,6 +132,61 @@ func (store *SessionStore) GetWorkingDirectoryForSession(sessionId string) (file
 	return session.workingDirectory, nil
 }
 
+// GetUserForSession get the user owning the given sessionId.
+//
+// Returns an error if the session is not found or invalid.
+func (store *SessionStore) GetUserForSession(sessionId string) (string, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	if sessionId == "" {
+		return "", fmt.Errorf("invalid session id")
+	}
+
+	session, found := store.sessions[sessionId]
+	if !found {
+		return "", fmt.Errorf("session not found")
+	}
+
+	return session.user, nil
+}
+
+// GetTransactionForSession get the open transaction for
+// the given sessionId, nil if there is none.
+//
+// Returns an error if the session is not found or invalid.
+func (store *SessionStore) GetTransactionForSession(sessionId string) (fstx.Transaction, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	if sessionId == "" {
+		return nil, fmt.Errorf("invalid session id")
+	}
+
+	session, found := store.sessions[sessionId]
+	if !found {
+		return nil, fmt.Errorf("session not found")
+	}
+
+	return session.transaction, nil
+}
+
+// SetTransaction sets the open transaction for the given sessionId,
+// nil to close it.
+//
+// Returns an error if the session is not found.
+func (store *SessionStore) SetTransaction(sessionId string, transaction fstx.Transaction) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	session, found := store.sessions[sessionId]
+	if !found {
+		return fmt.Errorf("session not found")
+	}
+	session.transaction = transaction
+	return nil
+}
+
 // ChangeWorkingDirectory changes the working directory
 // the given sessionId.
 //
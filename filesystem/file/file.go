@@ -1,24 +1,88 @@
 package file
 
+import (
+	"errors"
+	"time"
+)
+
 type FileType int
+type ChecksumAlgorithm int
+type Compression int
+type OpenFlag int
 type WalkFn func(File) error
 type FilterFn func(File) bool
 
+// WalkDirFn is called by WalkDir for every visited file with the path used to reach it,
+// the file attributes and the distance from the walk root.
+// Return SkipDir to skip a directory content, or the remaining files in the parent directory
+// if the file is not a directory. Return SkipAll to stop the walk.
+type WalkDirFn func(path string, stat FileStat, depth int) error
+
+var (
+	// SkipDir is used as a return value from WalkDirFn to skip a directory
+	SkipDir = errors.New("skip this directory")
+	// SkipAll is used as a return value from WalkDirFn to stop the walk
+	SkipAll = errors.New("skip everything and stop the walk")
+)
+
 const (
 	RegularFile FileType = iota
 	Directory
 	SymbolicLink
+	Fifo
+)
+
+const (
+	// OpenRead opens the file for reading
+	OpenRead OpenFlag = 1 << iota
+	// OpenWrite opens the file for writing
+	OpenWrite
+	// OpenNonBlocking makes open, read and write on a fifo fail
+	// instead of waiting for the other end
+	OpenNonBlocking
+	// OpenReadWrite opens the file for reading and writing
+	OpenReadWrite = OpenRead | OpenWrite
+)
+
+const (
+	SHA256 ChecksumAlgorithm = iota
+	SHA1
+	MD5
+	CRC32C
+)
+
+const (
+	NoCompression Compression = iota
+	Flate
+	Gzip
 )
 
 type FileInfo interface {
 	// Name returns the file name
 	Name() string
-	// FileType returns the filetype: RegularFile, Directory, SymbolicLink
+	// FileType returns the filetype: RegularFile, Directory, SymbolicLink, Fifo
 	FileType() FileType
 	// AbsolutePath returns the file absolute path
 	AbsolutePath() string
 }
 
+type FileStat interface {
+	FileInfo
+	// Size returns the size in byte
+	Size() int
+	// ModTime returns the last time the content was modified
+	ModTime() time.Time
+	// ChangeTime returns the last time the content or the attributes were modified
+	ChangeTime() time.Time
+	// Links returns the number of hard links to the file
+	Links() int
+	// Inode returns the inode number, the same for every hard link to the file
+	Inode() uint64
+	// PhysicalSize returns the memory allocated for the content in byte,
+	// after compression. Holes are not allocated.
+	PhysicalSize() int
+}
+
 type FileData interface {
 	// Data returns the file content
 	Data() []byte
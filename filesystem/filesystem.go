@@ -1,9 +1,18 @@
 package filesystem
 
 import (
+	"context"
 	"fmt"
 	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fscrypt"
 	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsquery"
+	"material/filesystem/filesystem/fsreport"
+	"material/filesystem/filesystem/fsstats"
+	"material/filesystem/filesystem/fstrash"
+	"material/filesystem/filesystem/fstx"
+	"material/filesystem/filesystem/fsversion"
+	"material/filesystem/filesystem/fsvirtual"
 	"material/filesystem/filesystem/memoryfs"
 )
 
@@ -15,81 +24,212 @@ const (
 
 type FileSystem interface {
 	// Mkdir creates a new directory at the specified path
-	// If there is an error, it will be of type *FileSystemError.
+	// If there is an error, it will be of type *PathError.
 	Mkdir(path *fspath.FileSystemPath) (file.File, error)
 	// MkdirAll creates a directory at the specified path,
 	// along with any necessary parents.
-	// If there is an error, it will be of type *FileSystemError.
+	// If there is an error, it will be of type *PathError.
 	MkdirAll(path *fspath.FileSystemPath) (file.File, error)
 	// CreateRegularFile creates a new file at the specified path
-	// If there is an error, it will be of type *FileSystemError.
+	// If there is an error, it will be of type *PathError.
 	CreateRegularFile(path *fspath.FileSystemPath) (file.File, error)
+	// Mkfifo creates a named pipe at the specified path
+	// If there is an error, it will be of type *PathError.
+	Mkfifo(path *fspath.FileSystemPath) (file.File, error)
+	// Stat returns the attributes of the file located at the specified path.
+	// If there is an error, it will be of type *PathError.
+	Stat(path *fspath.FileSystemPath) (file.FileStat, error)
+	// DiskUsage returns the space used by the directory located at path and by every subdirectory.
+	// If there is an error, it will be of type *PathError.
+	DiskUsage(path *fspath.FileSystemPath) ([]*fsstats.DiskUsage, error)
+	// StatFS returns the usage of the whole file system.
+	StatFS() *fsstats.FileSystemStats
+	// ListOrphans returns the deleted files that are still open, in deletion order.
+	ListOrphans() []*fsstats.Orphan
+	// ReclaimOrphan closes every descriptor of the orphan identified by id and returns it.
+	// If there is an error, it will be of type *PathError.
+	ReclaimOrphan(id string) (*fsstats.Orphan, error)
 	// DefaultWorkingDirectory returns the default working directory.
 	DefaultWorkingDirectory() file.File
 	// GetDirectory returns the directory located at the specified path.
-	// If there is an error, it will be of type *FileSystemError.
+	// If there is an error, it will be of type *PathError.
 	GetDirectory(path *fspath.FileSystemPath) (file.File, error)
 	// Remove removes the file located at the specified path.
-	// If there is an error, it will be of type *FileSystemError.
+	// If there is an error, it will be of type *PathError.
 	Remove(path *fspath.FileSystemPath) (file.FileInfo, error)
 	// RemoveAll removes the file located at the specified path
 	// and any children it contains.
-	// If there is an error, it will be of type *FileSystemError.
+	// If there is an error, it will be of type *PathError.
 	RemoveAll(path *fspath.FileSystemPath) (file.FileInfo, error)
+	// RemoveAllWithReport removes the file located at the specified path
+	// and any children it contains, and reports the removed files and the failures.
+	// If continueOnError is true, the files that can't be removed are skipped.
+	// If there is an error, it will be of type *PathError.
+	RemoveAllWithReport(path *fspath.FileSystemPath, continueOnError bool) (*fsreport.Report, error)
+	// Rmdir removes the empty directory located at the specified path.
+	// If there is an error, it will be of type *PathError.
+	Rmdir(path *fspath.FileSystemPath) (file.FileInfo, error)
 	// TODO: handle regex in name
 	FindFiles(name string, path *fspath.FileSystemPath) ([]file.FileInfo, error)
+	// Glob returns the files matching the pattern.
+	// The pattern supports path.Match syntax, "**" segments and brace alternation.
+	// If there is an error, it will be of type *PathError.
+	Glob(pattern *fspath.FileSystemPath) ([]file.FileInfo, error)
+	// Find walks the file tree rooted at path and returns the files matching the query.
+	// If there is an error, it will be of type *PathError.
+	Find(path *fspath.FileSystemPath, query *fsquery.Query) ([]file.FileStat, error)
 	// ListFiles lists the files at the specified path.
-	// If there is an error, it will be of type *FileSystemError.
+	// If there is an error, it will be of type *PathError.
 	ListFiles(path *fspath.FileSystemPath) ([]file.FileInfo, error)
+	// ListFilesPage lists at most limit files at the specified path, starting after cursor,
+	// and returns the cursor of the next page, empty after the last page.
+	// If there is an error, it will be of type *PathError.
+	ListFilesPage(path *fspath.FileSystemPath, cursor string, limit int) ([]file.FileInfo, string, error)
 	// Move moves (renames) srcPath to destPath.
-	// If there is an error, it will be of type *FileSystemError.
+	// If there is an error, it will be of type *PathError or *LinkError.
 	Move(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath) (file.FileInfo, error)
 	// Copy copies srcPath to destPath.
-	// If there is an error, it will be of type *FileSystemError.
+	// If there is an error, it will be of type *PathError or *LinkError.
 	Copy(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath) (file.FileInfo, error)
+	// MoveWithReport moves (renames) srcPath to destPath, and reports the moved files and the failures.
+	// If continueOnError is true, the files that can't be moved are skipped.
+	// If there is an error, it will be of type *PathError or *LinkError.
+	MoveWithReport(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath, continueOnError bool) (file.FileInfo, *fsreport.Report, error)
+	// CopyWithReport copies srcPath to destPath, and reports the copied files and the failures.
+	// If continueOnError is true, the files that can't be copied are skipped.
+	// If there is an error, it will be of type *PathError or *LinkError.
+	CopyWithReport(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath, continueOnError bool) (file.FileInfo, *fsreport.Report, error)
+	// CopyPreservingLinks copies srcPath to destPath like CopyWithReport, and the files
+	// hard linked to each other in srcPath are hard linked to each other in the copy.
+	// If there is an error, it will be of type *PathError or *LinkError.
+	CopyPreservingLinks(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath, continueOnError bool) (file.FileInfo, *fsreport.Report, error)
 	// Link creates srcPath as a hard link to the destPath file.
-	// If there is an error, it will be of type *FileSystemError.
+	// If there is an error, it will be of type *PathError.
 	CreateHardLink(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath) (file.FileInfo, error)
 	// Symlink creates srcPath as a symbolic link to destPath.
-	// If there is an error, it will be of type *FileSystemError.
+	// If there is an error, it will be of type *PathError.
 	CreateSymbolicLink(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath) (file.FileInfo, error)
 	// AppendAll writes data to the named file, creating it if necessary.
-	// If there is an error, it will be of type *FileSystemError.
+	// If there is an error, it will be of type *PathError.
 	AppendAll(path *fspath.FileSystemPath, content []byte) error
 	// ReadAll reads the named file and returns the contents.
-	// If there is an error, it will be of type *FileSystemError.
+	// If there is an error, it will be of type *PathError.
 	ReadAll(path *fspath.FileSystemPath) ([]byte, error)
-	// Open opens the named file for reading or writing.
-	// If there is an error, it will be of type *FileSystemError.
+	// Checksum returns the digest of the named file content computed with the given algorithm.
+	// If there is an error, it will be of type *PathError.
+	Checksum(path *fspath.FileSystemPath, algorithm file.ChecksumAlgorithm) ([]byte, error)
+	// SetCompression sets the compression of the named file content, or of every file
+	// in the tree if the file is a directory. New files inherit the directory compression.
+	// If there is an error, it will be of type *PathError.
+	SetCompression(path *fspath.FileSystemPath, compression file.Compression) error
+	// SetKeyProvider sets the provider of the master keys used by the encryption.
+	SetKeyProvider(keys fscrypt.KeyProvider)
+	// SetEncryption enables or disables the encryption of the named file content, or of every file
+	// in the tree if the file is a directory. New files inherit the directory encryption.
+	// If there is an error, it will be of type *PathError.
+	SetEncryption(path *fspath.FileSystemPath, enabled bool) error
+	// RotateMasterKey encrypts every data key with the current master key.
+	// If there is an error, it will be of type *PathError, recording a file using the key.
+	// Callers match the cause with errors.Is and the path with errors.As.
+	RotateMasterKey() error
+	// RotateDataKeys encrypts the named file content, or every file in the tree
+	// if the file is a directory, with new data keys.
+	// If there is an error, it will be of type *PathError.
+	RotateDataKeys(path *fspath.FileSystemPath) error
+	// EncryptionInfo returns how the named file content is encrypted.
+	// If there is an error, it will be of type *PathError.
+	EncryptionInfo(path *fspath.FileSystemPath) (*fscrypt.EncryptionInfo, error)
+	// SetTrashRetention sets the limits applied to every trash when a file is trashed.
+	SetTrashRetention(retention fstrash.Retention)
+	// Trash moves the named file, or directory if isRecursive is true, to the user trash.
+	// If there is an error, it will be of type *PathError.
+	Trash(path *fspath.FileSystemPath, user string, isRecursive bool) (*fstrash.Entry, error)
+	// ListTrash returns the entries in the user trash, oldest first.
+	ListTrash(user string) []*fstrash.Entry
+	// Restore moves a trash entry back to its original path, or to newPath if not nil.
+	// If there is an error, it will be of type *PathError.
+	Restore(user string, id string, newPath *fspath.FileSystemPath) (file.FileInfo, error)
+	// EmptyTrash purges the entries of the user trash exceeding the retention,
+	// or every entry if retention is nil, and returns them.
+	EmptyTrash(user string, retention *fstrash.Retention) []*fstrash.Entry
+	// SetVersionPolicy sets the limits of the versions kept for every regular file.
+	SetVersionPolicy(policy fsversion.Policy)
+	// ListVersions returns the versions of the named file, oldest first.
+	// If there is an error, it will be of type *PathError.
+	ListVersions(path *fspath.FileSystemPath) ([]*fsversion.Version, error)
+	// ReadVersion returns the content of a version of the named file.
+	// If there is an error, it will be of type *PathError.
+	ReadVersion(path *fspath.FileSystemPath, id int) ([]byte, error)
+	// RestoreVersion replaces the content of the named file with a version.
+	// If there is an error, it will be of type *PathError.
+	RestoreVersion(path *fspath.FileSystemPath, id int) error
+	// Begin starts a transaction. Its changes are isolated until Commit,
+	// which applies all of them atomically or fails on conflict.
+	Begin() fstx.Transaction
+	// Open opens the named file for reading and writing.
+	// If there is an error, it will be of type *PathError.
 	Open(path *fspath.FileSystemPath) (string, error)
+	// Mount adds a virtual file or directory at the specified path.
+	// The content of a virtual file is generated by the provider when it's opened.
+	// If there is an error, it will be of type *PathError.
+	Mount(path *fspath.FileSystemPath, node fsvirtual.Node) error
+	// OpenFile opens the named file for reading and/or writing, according to flags.
+	// Opening only one end of a fifo waits until the other end is open, unless file.OpenNonBlocking is set.
+	// If there is an error, it will be of type *PathError.
+	OpenFile(path *fspath.FileSystemPath, flags file.OpenFlag) (string, error)
+	// OpenFileWithOwner opens the named file like OpenFile, recording the owner of the descriptor.
+	// If there is an error, it will be of type *PathError.
+	OpenFileWithOwner(path *fspath.FileSystemPath, flags file.OpenFlag, owner string) (string, error)
+	// OpenFileContext opens the named file like OpenFileWithOwner, but stops waiting for
+	// the other end of a fifo when ctx is done and fails with ctx.Err().
+	// If there is an error, it will be of type *PathError.
+	OpenFileContext(ctx context.Context, path *fspath.FileSystemPath, flags file.OpenFlag, owner string) (string, error)
 	// Close closes the file associated to the given descriptor
 	Close(fileDescriptor string)
 	// ReadAt reads up of len(buff) bytes starting at the given offset and
 	// returns the number of bytes read.
-	// If there is an error, it will be of type *FileSystemError.
+	// If there is an error, it will be of type *PathError.
 	ReadAt(fileDescriptor string, buff []byte, offset int) (int, error)
 	// Read reads up of len(buff) bytes starting
-	// If there is an error, it will be of type *FileSystemError.
+	// If there is an error, it will be of type *PathError.
 	Read(fileDescriptor string, buff []byte) (int, error)
+	// ReadContext reads like Read, but stops waiting for the data of a fifo
+	// when ctx is done and returns ctx.Err().
+	// If there is an error, it will be of type *PathError.
+	ReadContext(ctx context.Context, fileDescriptor string, buff []byte) (int, error)
 	// Write writes content and returns the number of bytes written.
-	// If there is an error, it will be of type *FileSystemError.
+	// If there is an error, it will be of type *PathError.
 	Write(fileDescriptor string, content []byte) (int, error)
 	// WriteAt writes content to the file starting at offset
 	// and returns the number of bytes written.
-	// If there is an error, it will be of type *FileSystemError.
+	// If there is an error, it will be of type *PathError.
 	WriteAt(fileDescriptor string, content []byte, offset int) (int, error)
+	// SeekData moves the descriptor offset to the first position >= offset containing data.
+	// If there is an error, it will be of type *PathError.
+	SeekData(descriptor string, offset int) (int, error)
+	// SeekHole moves the descriptor offset to the first position >= offset in a hole.
+	// The end of the file is considered a hole.
+	// If there is an error, it will be of type *PathError.
+	SeekHole(descriptor string, offset int) (int, error)
+	// PunchHole deallocates length bytes starting at offset without changing the file size.
+	// If there is an error, it will be of type *PathError.
+	PunchHole(descriptor string, offset int, length int) error
 	// Walk walks the file tree rooted at root, calling filterFn for each file or directory in the tree, including root,
 	// and calls walkFn for each file or directory matching the filter.
 	// Optionally follow symbolic links.
 	Walk(path *fspath.FileSystemPath, walkFn file.WalkFn, filterFn file.FilterFn, followLinks bool) error
+	// WalkDir walks the file tree rooted at path in lexical order, calling walkFn for each file or directory
+	// in the tree, including path. walkFn can return file.SkipDir or file.SkipAll to skip part of the tree.
+	// Optionally follow symbolic links, every directory is visited at most once.
+	WalkDir(path *fspath.FileSystemPath, walkFn file.WalkDirFn, followLinks bool) error
 }
 
-// NewFileSystem creates a new filesystem for the given fsType.
-// Returns an error if the fsType is not supported.
-func NewFileSystem(fsType FileSystemType) (FileSystem, error) {
+// NewFileSystem creates a new filesystem for the given fsType, configured with the options.
+// Returns an error if the fsType is not supported or an option is invalid.
+func NewFileSystem(fsType FileSystemType, options ...memoryfs.Option) (FileSystem, error) {
 	switch fsType {
 	case InMemoryFileSystem:
-		return memoryfs.NewMemoryFileSystem(), nil
+		return memoryfs.NewMemoryFileSystem(options...)
 	default:
 		return nil, fmt.Errorf("unsupported filesystem type")
 	}
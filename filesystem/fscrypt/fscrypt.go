@@ -0,0 +1,129 @@
+package fscrypt
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeyProvider provides the master keys used to encrypt the file data keys.
+// Master keys must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+type KeyProvider interface {
+	// CurrentKey returns the id and the value of the key used to encrypt new data keys
+	CurrentKey() (string, []byte, error)
+	// Key returns the value of the key with the given id
+	Key(id string) ([]byte, error)
+}
+
+// EncryptionInfo describes how a file content is encrypted
+type EncryptionInfo struct {
+	// Encrypted is true if the content is encrypted
+	Encrypted bool
+	// MasterKeyID is the id of the master key encrypting the file data key
+	MasterKeyID string
+}
+
+// StaticKeyProvider is a KeyProvider holding the keys in memory
+type StaticKeyProvider struct {
+	currentID string
+	keys      map[string][]byte
+}
+
+// NewStaticKeyProvider creates a provider with the given keys.
+// currentID is the id of the key used to encrypt new data keys.
+func NewStaticKeyProvider(currentID string, keys map[string][]byte) *StaticKeyProvider {
+	return &StaticKeyProvider{
+		currentID: currentID,
+		keys:      keys,
+	}
+}
+
+func (p *StaticKeyProvider) CurrentKey() (string, []byte, error) {
+	key, err := p.Key(p.currentID)
+	return p.currentID, key, err
+}
+
+func (p *StaticKeyProvider) Key(id string) ([]byte, error) {
+	key, found := p.keys[id]
+	if !found {
+		return nil, fmt.Errorf("key %s not found", id)
+	}
+	return key, nil
+}
+
+// FileKeyProvider is a KeyProvider loading the keys from a local file.
+// The file contains one key per line in the format "id hex_encoded_key".
+// Empty lines and lines starting with # are ignored.
+// The last key is the current one, so keys can be rotated by appending a new line.
+// The file is read on every call.
+type FileKeyProvider struct {
+	path string
+}
+
+// NewFileKeyProvider creates a provider reading the keys from path.
+//
+// Returns an error if the file can't be read or it's malformed.
+func NewFileKeyProvider(path string) (*FileKeyProvider, error) {
+	p := &FileKeyProvider{path: path}
+	if _, _, err := p.CurrentKey(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *FileKeyProvider) CurrentKey() (string, []byte, error) {
+	ids, keys, err := p.load()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(ids) == 0 {
+		return "", nil, fmt.Errorf("no keys in %s", p.path)
+	}
+	currentID := ids[len(ids)-1]
+	return currentID, keys[currentID], nil
+}
+
+func (p *FileKeyProvider) Key(id string) ([]byte, error) {
+	_, keys, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	key, found := keys[id]
+	if !found {
+		return nil, fmt.Errorf("key %s not found", id)
+	}
+	return key, nil
+}
+
+// load returns the key ids in file order and the keys by id
+func (p *FileKeyProvider) load() ([]string, map[string][]byte, error) {
+	f, err := os.Open(p.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	ids := []string{}
+	keys := map[string][]byte{}
+	scanner := bufio.NewScanner(f)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, nil, fmt.Errorf("%s:%d: malformed key", p.path, lineNumber)
+		}
+		key, err := hex.DecodeString(fields[1])
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s:%d: malformed key: %w", p.path, lineNumber, err)
+		}
+		ids = append(ids, fields[0])
+		keys[fields[0]] = key
+	}
+	return ids, keys, scanner.Err()
+}
@@ -0,0 +1,70 @@
+package fscrypt_test
+
+import (
+	"material/filesystem/filesystem/fscrypt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileKeyProvider(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Content    string
+		Assertions func(t *testing.T, provider *fscrypt.FileKeyProvider, err error)
+	}{
+		{
+			CaseName: "Last key is the current key",
+			Content:  "# master keys\nkey1 0102\n\nkey2 0304\n",
+			Assertions: func(t *testing.T, provider *fscrypt.FileKeyProvider, err error) {
+				assert.Nil(t, err)
+				id, key, err := provider.CurrentKey()
+				assert.Nil(t, err)
+				assert.Equal(t, "key2", id)
+				assert.Equal(t, []byte{3, 4}, key)
+
+				key, err = provider.Key("key1")
+				assert.Nil(t, err)
+				assert.Equal(t, []byte{1, 2}, key)
+
+				_, err = provider.Key("key3")
+				assert.NotNil(t, err)
+			},
+		},
+		{
+			CaseName: "No keys",
+			Content:  "# master keys\n",
+			Assertions: func(t *testing.T, provider *fscrypt.FileKeyProvider, err error) {
+				assert.NotNil(t, err)
+				assert.Nil(t, provider)
+			},
+		},
+		{
+			CaseName: "Malformed line",
+			Content:  "key1 0102 0304\n",
+			Assertions: func(t *testing.T, provider *fscrypt.FileKeyProvider, err error) {
+				assert.NotNil(t, err)
+				assert.Nil(t, provider)
+			},
+		},
+		{
+			CaseName: "Malformed key",
+			Content:  "key1 xyz\n",
+			Assertions: func(t *testing.T, provider *fscrypt.FileKeyProvider, err error) {
+				assert.NotNil(t, err)
+				assert.Nil(t, provider)
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		path := filepath.Join(t.TempDir(), "keys")
+		if err := os.WriteFile(path, []byte(testCase.Content), 0600); err != nil {
+			t.Fatal("error writing keys file")
+		}
+		provider, err := fscrypt.NewFileKeyProvider(path)
+		testCase.Assertions(t, provider, err)
+	}
+}
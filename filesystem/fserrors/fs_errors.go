@@ -1,23 +1,90 @@
 package fserrors
 
-import "errors"
+import (
+	"errors"
+	"io/fs"
+	"syscall"
+)
 
 var (
-	ErrExist                   = &FileSystemError{err: errors.New("file already exists")}
-	ErrNotExist                = &FileSystemError{err: errors.New("file does not exist")}
-	ErrInvalid                 = &FileSystemError{err: errors.New("invalid argument")}
-	ErrInvalidFileType         = &FileSystemError{err: errors.New("file is not a directory")}
-	ErrOperationNotSupported   = &FileSystemError{err: errors.New("operation not supported")}
-	ErrInvalidWorkingDirectory = &FileSystemError{err: errors.New("invalid working directory")}
-	ErrSameFile                = &FileSystemError{err: errors.New("same file")}
-	ErrTooManyLinks            = &FileSystemError{err: errors.New("too many links")}
-	ErrNotOpen                 = &FileSystemError{err: errors.New("file is not open")}
+	ErrExist                   = newError("file already exists", syscall.EEXIST)
+	ErrNotExist                = newError("file does not exist", syscall.ENOENT)
+	ErrInvalid                 = newError("invalid argument", syscall.EINVAL)
+	ErrNotDir                  = newError("not a directory", syscall.ENOTDIR)
+	ErrIsDir                   = newError("is a directory", syscall.EISDIR)
+	ErrNotEmpty                = newError("directory not empty", syscall.ENOTEMPTY)
+	ErrOperationNotSupported   = newError("operation not supported", syscall.ENOTSUP)
+	ErrInvalidWorkingDirectory = newError("invalid working directory", syscall.ENOENT)
+	ErrSameFile                = newError("same file", syscall.EINVAL)
+	ErrTooManyLinks            = newError("too many links", syscall.ELOOP)
+	ErrNotOpen                 = newError("file is not open", syscall.EBADF)
+	ErrCorruptedData           = newError("corrupted data", syscall.EIO)
+	ErrNoKey                   = newError("encryption key not available", syscall.EACCES)
+	ErrOffsetOutOfRange        = newError("offset out of range", syscall.ENXIO)
+	ErrConflict                = newError("transaction conflict", 0)
+	ErrTransactionClosed       = newError("transaction is closed", 0)
+	ErrWouldBlock              = newError("operation would block", syscall.EAGAIN)
+	ErrBrokenPipe              = newError("broken pipe", syscall.EPIPE)
+	ErrBadDescriptor           = newError("bad file descriptor", syscall.EBADF)
+	ErrNameTooLong             = newError("file name too long", syscall.ENAMETOOLONG)
+	ErrInvalidName             = newError("invalid file name", syscall.EINVAL)
+	ErrFileTooLarge            = newError("file too large", syscall.EFBIG)
+	ErrNoSpace                 = newError("no space left on device", syscall.ENOSPC)
 )
 
 type FileSystemError struct {
 	err error
+	// errno is the matching system error, 0 if there is none
+	errno syscall.Errno
+}
+
+func newError(text string, errno syscall.Errno) *FileSystemError {
+	return &FileSystemError{err: errors.New(text), errno: errno}
 }
 
 func (e FileSystemError) Error() string { return e.err.Error() }
 
 func (e FileSystemError) Unwrap() error { return e.err }
+
+// Is maps the error to the matching syscall.Errno and io/fs errors,
+// e.g. errors.Is(ErrNotExist, fs.ErrNotExist) and errors.Is(ErrNotExist, syscall.ENOENT) are true
+func (e FileSystemError) Is(target error) bool {
+	if e.errno == 0 {
+		return false
+	}
+	if errno, ok := target.(syscall.Errno); ok {
+		return e.errno == errno
+	}
+	switch target {
+	case fs.ErrInvalid:
+		return e.errno == syscall.EINVAL
+	case fs.ErrClosed:
+		return e.errno == syscall.EBADF
+	}
+	return e.errno.Is(target)
+}
+
+// PathError records an error and the operation and path that caused it.
+// Path is the descriptor for the operations on open files.
+type PathError struct {
+	Op   string
+	Path string
+	Err  error
+}
+
+func (e *PathError) Error() string { return e.Op + " " + e.Path + ": " + e.Err.Error() }
+
+func (e *PathError) Unwrap() error { return e.Err }
+
+// LinkError records an error and the operation and paths that caused it,
+// when the error is not about one of the paths, e.g. moving a directory into itself
+type LinkError struct {
+	Op  string
+	Old string
+	New string
+	Err error
+}
+
+func (e *LinkError) Error() string { return e.Op + " " + e.Old + " " + e.New + ": " + e.Err.Error() }
+
+func (e *LinkError) Unwrap() error { return e.Err }
@@ -0,0 +1,107 @@
+package fsname
+
+import (
+	"material/filesystem/filesystem/fserrors"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalization is the Unicode normalization form applied to the file names
+type Normalization int
+
+const (
+	// NoNormalization keeps the names as they are
+	NoNormalization Normalization = iota
+	// NFC composes the characters, e.g. "e" followed by a combining acute accent becomes "é"
+	NFC
+	// NFD decomposes the characters, e.g. "é" becomes "e" followed by a combining acute accent
+	NFD
+)
+
+// Policy restricts the names and paths of the files.
+// Zero values disable the limit.
+type Policy struct {
+	// MaxNameLength is the maximum length of a file name in bytes
+	MaxNameLength int
+	// MaxPathLength is the maximum length of an absolute path in bytes
+	MaxPathLength int
+	// MaxDepth is the maximum number of names in an absolute path
+	MaxDepth int
+	// ForbiddenChars contains the characters that can't be used in a name
+	ForbiddenChars string
+	// ReservedNames can't be used as file names, they are compared ignoring case
+	ReservedNames []string
+	// Normalization is applied to the names before they are checked, stored and looked up.
+	// When set, names must be valid UTF-8.
+	Normalization Normalization
+}
+
+// DefaultPolicy returns the policy of a new file system:
+// names up to 255 bytes, paths up to 4096 bytes and no NUL bytes
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxNameLength:  255,
+		MaxPathLength:  4096,
+		ForbiddenChars: "\x00",
+	}
+}
+
+// Normalize returns the name in the normalization form of the policy
+func (p Policy) Normalize(name string) string {
+	switch p.Normalization {
+	case NFC:
+		return norm.NFC.String(name)
+	case NFD:
+		return norm.NFD.String(name)
+	default:
+		return name
+	}
+}
+
+// CheckName checks a normalized file name.
+//
+// Returns an error when:
+// - the name is too long (ErrNameTooLong)
+// - the name contains a forbidden character or is a reserved name (ErrInvalidName)
+// - the name is not valid UTF-8 and a normalization is set (ErrInvalidName)
+func (p Policy) CheckName(name string) error {
+	if p.MaxNameLength > 0 && len(name) > p.MaxNameLength {
+		return fserrors.ErrNameTooLong
+	}
+	if p.Normalization != NoNormalization && !utf8.ValidString(name) {
+		return fserrors.ErrInvalidName
+	}
+	if strings.ContainsAny(name, p.ForbiddenChars) {
+		return fserrors.ErrInvalidName
+	}
+	for _, reserved := range p.ReservedNames {
+		if strings.EqualFold(name, reserved) {
+			return fserrors.ErrInvalidName
+		}
+	}
+	return nil
+}
+
+// CheckPath checks the length and the depth of an absolute path.
+//
+// Returns ErrNameTooLong if the path exceeds a limit.
+func (p Policy) CheckPath(absolutePath string) error {
+	if p.MaxPathLength > 0 && len(absolutePath) > p.MaxPathLength {
+		return fserrors.ErrNameTooLong
+	}
+	if p.MaxDepth > 0 && depth(absolutePath) > p.MaxDepth {
+		return fserrors.ErrNameTooLong
+	}
+	return nil
+}
+
+// depth returns the number of names in the absolute path
+func depth(absolutePath string) int {
+	trimmed := strings.Trim(absolutePath, "/")
+	if trimmed == "" {
+		return 0
+	}
+	return strings.Count(trimmed, "/") + 1
+}
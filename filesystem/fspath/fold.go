@@ -0,0 +1,29 @@
+package fspath
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+)
+
+// FoldName returns the case folded form of name, used to compare
+// file names in case-insensitive file systems.
+// Two names are equal ignoring case if their folded forms are equal.
+// Unicode full case folding is applied, so "STRASSE" and "straße" are equal.
+func FoldName(name string) string {
+	for i := 0; i < len(name); i++ {
+		if name[i] >= utf8.RuneSelf {
+			// a Caser is not safe for concurrent use
+			return cases.Fold().String(name)
+		}
+	}
+
+	// fast path for ASCII names
+	folded := []byte(name)
+	for i, c := range folded {
+		if 'A' <= c && c <= 'Z' {
+			folded[i] = c + 'a' - 'A'
+		}
+	}
+	return string(folded)
+}
@@ -35,6 +35,11 @@ func (p *FileSystemPath) Base() string {
 	return filepath.Base(p.path)
 }
 
+// String returns the path as given, cleaned
+func (p *FileSystemPath) String() string {
+	return p.path
+}
+
 // NewFileSystemPath creates a new filesystem path from
 // the given path and workingDir
 func NewFileSystemPath(path string, workingDir file.File) (*FileSystemPath, error) {
@@ -112,3 +112,22 @@ func TestFileSystemPath(t *testing.T) {
 		testCase.Assertions(t, p, err)
 	}
 }
+
+func TestFoldName(t *testing.T) {
+	cases := []struct {
+		CaseName string
+		Names    []string
+		Equal    bool
+	}{
+		{CaseName: "ascii", Names: []string{"README.md", "readme.MD", "Readme.md"}, Equal: true},
+		{CaseName: "full case folding", Names: []string{"Straße", "STRASSE", "strasse"}, Equal: true},
+		{CaseName: "greek", Names: []string{"ΣΟΦΙΑ", "σοφια"}, Equal: true},
+		{CaseName: "accents are not ignored", Names: []string{"cafe", "café"}, Equal: false},
+	}
+	for _, testCase := range cases {
+		fmt.Println(testCase.CaseName)
+		for _, name := range testCase.Names[1:] {
+			assert.Equal(t, testCase.Equal, fspath.FoldName(testCase.Names[0]) == fspath.FoldName(name), name)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package fsquery
+
+import (
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fspath"
+	"path"
+	"regexp"
+	"time"
+)
+
+// UnlimitedDepth disables the maximum depth check
+const UnlimitedDepth = -1
+
+type Action int
+
+const (
+	// ActionList returns the matching files
+	ActionList Action = iota
+	// ActionDelete deletes the matching files and returns them
+	ActionDelete
+	// ActionTrash moves the matching files to the trash of the query user and returns them
+	ActionTrash
+)
+
+// Entry is a file visited by a query
+type Entry struct {
+	file.FileStat
+	// Depth is the distance from the query starting point
+	Depth int
+	// IsEmpty is true for empty regular files and directories
+	// without children
+	IsEmpty bool
+	// IgnoreCase is true if the file system is case-insensitive,
+	// the name and path predicates ignore case
+	IgnoreCase bool
+}
+
+// Predicate returns true if the entry matches
+type Predicate func(entry *Entry) bool
+
+// Query describes which files to find and what to do with them.
+type Query struct {
+	// Match selects the files, nil matches every file
+	Match Predicate
+	// Prune skips the matching files and, for directories, their content
+	Prune Predicate
+	// MinDepth is the minimum depth of the matching files
+	MinDepth int
+	// MaxDepth is the maximum depth to descend to, UnlimitedDepth to descend the whole tree
+	MaxDepth int
+	// FollowLinks resolves symbolic links and descends into linked directories
+	FollowLinks bool
+	// Action to perform on the matching files
+	Action Action
+	// User whose trash receives the matching files with ActionTrash
+	User string
+}
+
+// NewQuery creates a query matching every file in the tree
+func NewQuery() *Query {
+	return &Query{
+		MaxDepth: UnlimitedDepth,
+		Action:   ActionList,
+	}
+}
+
+// Matches returns true if the entry matches the query
+func (q *Query) Matches(entry *Entry) bool {
+	if entry.Depth < q.MinDepth {
+		return false
+	}
+	return q.Match == nil || q.Match(entry)
+}
+
+// IsPruned returns true if the entry should be skipped
+func (q *Query) IsPruned(entry *Entry) bool {
+	return q.Prune != nil && q.Prune(entry)
+}
+
+// CanDescend returns true if the query can visit the children of the entry
+func (q *Query) CanDescend(entry *Entry) bool {
+	return q.MaxDepth == UnlimitedDepth || entry.Depth < q.MaxDepth
+}
+
+// And matches if every predicate matches
+func And(predicates ...Predicate) Predicate {
+	return func(entry *Entry) bool {
+		for _, predicate := range predicates {
+			if !predicate(entry) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches if at least one predicate matches
+func Or(predicates ...Predicate) Predicate {
+	return func(entry *Entry) bool {
+		for _, predicate := range predicates {
+			if predicate(entry) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches if the predicate doesn't match
+func Not(predicate Predicate) Predicate {
+	return func(entry *Entry) bool {
+		return !predicate(entry)
+	}
+}
+
+// Type matches the files of the given type
+func Type(fileType file.FileType) Predicate {
+	return func(entry *Entry) bool {
+		return entry.FileType() == fileType
+	}
+}
+
+// SizeRange matches the files whose size is in [min, max].
+// A negative max means no upper bound.
+func SizeRange(min int, max int) Predicate {
+	return func(entry *Entry) bool {
+		return entry.Size() >= min && (max < 0 || entry.Size() <= max)
+	}
+}
+
+// ModTimeRange matches the files modified in [after, before].
+// A zero time means no bound.
+func ModTimeRange(after time.Time, before time.Time) Predicate {
+	return func(entry *Entry) bool {
+		return inTimeRange(entry.ModTime(), after, before)
+	}
+}
+
+// ChangeTimeRange matches the files changed in [after, before].
+// A zero time means no bound.
+func ChangeTimeRange(after time.Time, before time.Time) Predicate {
+	return func(entry *Entry) bool {
+		return inTimeRange(entry.ChangeTime(), after, before)
+	}
+}
+
+// LinkCount matches the files whose number of hard links is in [min, max].
+// A negative max means no upper bound.
+func LinkCount(min int, max int) Predicate {
+	return func(entry *Entry) bool {
+		return entry.Links() >= min && (max < 0 || entry.Links() <= max)
+	}
+}
+
+// Empty matches empty regular files and directories without children
+func Empty() Predicate {
+	return func(entry *Entry) bool {
+		return entry.IsEmpty
+	}
+}
+
+// NameGlob matches the files whose name matches the pattern.
+// The pattern follows path.Match syntax.
+//
+// Returns an error if the pattern is malformed.
+func NameGlob(pattern string) (Predicate, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	foldedPattern := fspath.FoldName(pattern)
+	return func(entry *Entry) bool {
+		if entry.IgnoreCase {
+			matched, _ := path.Match(foldedPattern, fspath.FoldName(entry.Name()))
+			return matched
+		}
+		matched, _ := path.Match(pattern, entry.Name())
+		return matched
+	}, nil
+}
+
+// NameRegex matches the files whose name matches the regular expression.
+//
+// Returns an error if the expression is malformed.
+func NameRegex(expr string) (Predicate, error) {
+	exp, foldedExp, err := compileRegex(expr)
+	if err != nil {
+		return nil, err
+	}
+	return func(entry *Entry) bool {
+		if entry.IgnoreCase {
+			return foldedExp.MatchString(entry.Name())
+		}
+		return exp.MatchString(entry.Name())
+	}, nil
+}
+
+// PathRegex matches the files whose absolute path matches the regular expression.
+//
+// Returns an error if the expression is malformed.
+func PathRegex(expr string) (Predicate, error) {
+	exp, foldedExp, err := compileRegex(expr)
+	if err != nil {
+		return nil, err
+	}
+	return func(entry *Entry) bool {
+		if entry.IgnoreCase {
+			return foldedExp.MatchString(entry.AbsolutePath())
+		}
+		return exp.MatchString(entry.AbsolutePath())
+	}, nil
+}
+
+// compileRegex compiles the expression and its case-insensitive version
+func compileRegex(expr string) (*regexp.Regexp, *regexp.Regexp, error) {
+	exp, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return exp, regexp.MustCompile("(?i)" + expr), nil
+}
+
+func inTimeRange(t time.Time, after time.Time, before time.Time) bool {
+	if !after.IsZero() && t.Before(after) {
+		return false
+	}
+	if !before.IsZero() && t.After(before) {
+		return false
+	}
+	return true
+}
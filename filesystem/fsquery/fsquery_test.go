@@ -0,0 +1,124 @@
+package fsquery_test
+
+import (
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fsquery"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestFileStat struct {
+	name         string
+	absolutePath string
+	fType        file.FileType
+	size         int
+	modTime      time.Time
+	links        int
+}
+
+func (stat TestFileStat) Name() string {
+	return stat.name
+}
+
+func (stat TestFileStat) FileType() file.FileType {
+	return stat.fType
+}
+
+func (stat TestFileStat) AbsolutePath() string {
+	return stat.absolutePath
+}
+
+func (stat TestFileStat) Size() int {
+	return stat.size
+}
+
+func (stat TestFileStat) ModTime() time.Time {
+	return stat.modTime
+}
+
+func (stat TestFileStat) ChangeTime() time.Time {
+	return stat.modTime
+}
+
+func (stat TestFileStat) Links() int {
+	return stat.links
+}
+
+func (stat TestFileStat) Inode() uint64 {
+	return 0
+}
+
+func (stat TestFileStat) PhysicalSize() int {
+	return stat.size
+}
+
+func TestPredicates(t *testing.T) {
+	now := time.Now()
+	entry := &fsquery.Entry{
+		FileStat: TestFileStat{
+			name:         "file.txt",
+			absolutePath: "/dir/file.txt",
+			fType:        file.RegularFile,
+			size:         10,
+			modTime:      now,
+			links:        2,
+		},
+		Depth: 1,
+	}
+
+	nameGlob, err := fsquery.NameGlob("*.txt")
+	assert.Nil(t, err)
+	_, err = fsquery.NameGlob("[a-")
+	assert.NotNil(t, err)
+
+	pathRegex, err := fsquery.PathRegex("^/dir/")
+	assert.Nil(t, err)
+	_, err = fsquery.PathRegex("(")
+	assert.NotNil(t, err)
+
+	cases := []struct {
+		CaseName  string
+		Predicate fsquery.Predicate
+		Expected  bool
+	}{
+		{CaseName: "Type matches", Predicate: fsquery.Type(file.RegularFile), Expected: true},
+		{CaseName: "Type doesn't match", Predicate: fsquery.Type(file.Directory), Expected: false},
+		{CaseName: "Size in range", Predicate: fsquery.SizeRange(10, 10), Expected: true},
+		{CaseName: "Size without upper bound", Predicate: fsquery.SizeRange(5, -1), Expected: true},
+		{CaseName: "Size out of range", Predicate: fsquery.SizeRange(11, -1), Expected: false},
+		{CaseName: "Modification time in range", Predicate: fsquery.ModTimeRange(now.Add(-time.Minute), now), Expected: true},
+		{CaseName: "Modification time out of range", Predicate: fsquery.ModTimeRange(now.Add(time.Minute), time.Time{}), Expected: false},
+		{CaseName: "Change time without bounds", Predicate: fsquery.ChangeTimeRange(time.Time{}, time.Time{}), Expected: true},
+		{CaseName: "Link count", Predicate: fsquery.LinkCount(2, 2), Expected: true},
+		{CaseName: "Empty", Predicate: fsquery.Empty(), Expected: false},
+		{CaseName: "Name glob", Predicate: nameGlob, Expected: true},
+		{CaseName: "Path regex", Predicate: pathRegex, Expected: true},
+		{CaseName: "And", Predicate: fsquery.And(nameGlob, fsquery.Empty()), Expected: false},
+		{CaseName: "Or", Predicate: fsquery.Or(fsquery.Empty(), nameGlob), Expected: true},
+		{CaseName: "Not", Predicate: fsquery.Not(fsquery.Empty()), Expected: true},
+	}
+	for _, testCase := range cases {
+		assert.Equal(t, testCase.Expected, testCase.Predicate(entry), testCase.CaseName)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	entry := &fsquery.Entry{
+		FileStat: TestFileStat{name: "file", fType: file.RegularFile},
+		Depth:    2,
+	}
+
+	query := fsquery.NewQuery()
+	assert.True(t, query.Matches(entry))
+	assert.True(t, query.CanDescend(entry))
+	assert.False(t, query.IsPruned(entry))
+
+	query.MinDepth = 3
+	query.MaxDepth = 2
+	query.Prune = fsquery.Type(file.RegularFile)
+	assert.False(t, query.Matches(entry))
+	assert.False(t, query.CanDescend(entry))
+	assert.True(t, query.IsPruned(entry))
+}
@@ -0,0 +1,21 @@
+package fsreport
+
+// Report records the outcome of an operation on a tree of files,
+// e.g. a recursive copy that continues after an error
+type Report struct {
+	// Succeeded holds the absolute paths of the processed files.
+	// A copied directory is listed after its content, a directory moved
+	// as a whole is listed without its content and a directory merged
+	// into an existing one is not listed.
+	Succeeded []string
+	// Failed holds the files that could not be processed
+	Failed []*Failure
+}
+
+// Failure is a file that could not be processed
+type Failure struct {
+	// Path is the absolute path of the file
+	Path string
+	// Err is the cause of the failure
+	Err error
+}
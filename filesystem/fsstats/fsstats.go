@@ -0,0 +1,75 @@
+package fsstats
+
+import (
+	"material/filesystem/filesystem/file"
+	"time"
+)
+
+// DiskUsage is the space used by a directory and all its content.
+// Files with multiple hard links are counted once.
+type DiskUsage struct {
+	// AbsolutePath is the directory absolute path
+	AbsolutePath string
+	// Depth is the distance from the directory where the computation started
+	Depth int
+	// ApparentSize is the sum of the file sizes in bytes
+	ApparentSize int
+	// AllocatedBytes is the memory allocated for the file contents
+	AllocatedBytes int
+	// Inodes is the number of files and directories, including the directory itself
+	Inodes int
+}
+
+// FileSystemStats describes the usage of the whole file system
+type FileSystemStats struct {
+	// Inodes is the number of files and directories reachable from the root.
+	// Files with multiple hard links are counted once.
+	Inodes int
+	// ApparentSize is the sum of the file sizes in bytes
+	ApparentSize int
+	// AllocatedBytes is the memory allocated for the file contents
+	AllocatedBytes int
+	// OpenDescriptors is the number of open file descriptors
+	OpenDescriptors int
+	// OrphanedFiles is the number of deleted files that are still open
+	OrphanedFiles int
+	// OrphanedBytes is the memory allocated for the deleted files that are still open
+	OrphanedBytes int
+	// Blocks is the number of unique content blocks
+	Blocks int
+	// StoredBytes is the memory used by the unique content blocks
+	StoredBytes int
+	// ReferencedBytes is the memory the content blocks would use without deduplication
+	ReferencedBytes int
+	// CachedPaths is the number of directories in the path resolution cache
+	CachedPaths int
+}
+
+// DedupRatio returns how many bytes are referenced for every stored byte.
+// Returns 1 if the file system is empty.
+func (stats *FileSystemStats) DedupRatio() float64 {
+	if stats.StoredBytes == 0 {
+		return 1
+	}
+	return float64(stats.ReferencedBytes) / float64(stats.StoredBytes)
+}
+
+// Orphan is a deleted file that is still open
+type Orphan struct {
+	// ID identifies the orphan until its last descriptor is closed
+	ID string
+	// OriginalPath is the absolute path of the file when it was deleted
+	OriginalPath string
+	// FileType is the type of the deleted file
+	FileType file.FileType
+	// DeletedAt is the time of the deletion
+	DeletedAt time.Time
+	// Size is the file size in bytes
+	Size int
+	// AllocatedBytes is the memory allocated for the file content
+	AllocatedBytes int
+	// Descriptors is the number of descriptors still open
+	Descriptors int
+	// Owners are the known owners of the open descriptors in lexical order, e.g. sessions
+	Owners []string
+}
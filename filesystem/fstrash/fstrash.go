@@ -0,0 +1,36 @@
+package fstrash
+
+import (
+	"material/filesystem/filesystem/file"
+	"time"
+)
+
+// DefaultUser owns the trash of the clients without a user
+const DefaultUser = "default"
+
+// Entry is a file or directory in the trash
+type Entry struct {
+	// ID identifies the entry in the user trash
+	ID string
+	// User is the owner of the trash
+	User string
+	// OriginalPath is the absolute path of the file before being trashed
+	OriginalPath string
+	// FileType is the type of the trashed file
+	FileType file.FileType
+	// DeletedAt is the time the file was trashed
+	DeletedAt time.Time
+	// Size is the sum of the file sizes in bytes.
+	// Files with multiple hard links are counted once.
+	Size int
+}
+
+// Retention limits the content of a trash.
+// Zero values disable the limit.
+type Retention struct {
+	// MaxAge is the maximum time an entry is kept in the trash
+	MaxAge time.Duration
+	// MaxSize is the maximum size of the trash in bytes.
+	// The oldest entries are purged first.
+	MaxSize int
+}
@@ -0,0 +1,48 @@
+package fstx
+
+import (
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsreport"
+	"material/filesystem/filesystem/fstrash"
+)
+
+// Operations are the file system operations supported in a transaction.
+// They have the same semantic of the file system ones.
+type Operations interface {
+	Mkdir(path *fspath.FileSystemPath) (file.File, error)
+	MkdirAll(path *fspath.FileSystemPath) (file.File, error)
+	CreateRegularFile(path *fspath.FileSystemPath) (file.File, error)
+	Stat(path *fspath.FileSystemPath) (file.FileStat, error)
+	Remove(path *fspath.FileSystemPath) (file.FileInfo, error)
+	RemoveAll(path *fspath.FileSystemPath) (file.FileInfo, error)
+	RemoveAllWithReport(path *fspath.FileSystemPath, continueOnError bool) (*fsreport.Report, error)
+	Rmdir(path *fspath.FileSystemPath) (file.FileInfo, error)
+	Trash(path *fspath.FileSystemPath, user string, isRecursive bool) (*fstrash.Entry, error)
+	ListFiles(path *fspath.FileSystemPath) ([]file.FileInfo, error)
+	ListFilesPage(path *fspath.FileSystemPath, cursor string, limit int) ([]file.FileInfo, string, error)
+	Move(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath) (file.FileInfo, error)
+	Copy(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath) (file.FileInfo, error)
+	MoveWithReport(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath, continueOnError bool) (file.FileInfo, *fsreport.Report, error)
+	CopyWithReport(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath, continueOnError bool) (file.FileInfo, *fsreport.Report, error)
+	CopyPreservingLinks(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath, continueOnError bool) (file.FileInfo, *fsreport.Report, error)
+	CreateHardLink(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath) (file.FileInfo, error)
+	CreateSymbolicLink(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath) (file.FileInfo, error)
+	AppendAll(path *fspath.FileSystemPath, content []byte) error
+	ReadAll(path *fspath.FileSystemPath) ([]byte, error)
+}
+
+// Transaction groups operations applied atomically to the file system.
+// The changes are visible only in the transaction until Commit.
+// After Commit or Rollback every operation fails.
+type Transaction interface {
+	Operations
+	// Commit applies every change atomically.
+	// If an operation conflicts with the current file system, the changes already
+	// applied are undone and the error records the operation and its paths as
+	// *fserrors.PathError or *fserrors.LinkError wrapping fserrors.ErrConflict.
+	// Committing a closed transaction returns fserrors.ErrTransactionClosed.
+	Commit() error
+	// Rollback discards every change
+	Rollback()
+}
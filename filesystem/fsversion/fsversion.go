@@ -0,0 +1,30 @@
+package fsversion
+
+import "time"
+
+// Version is a snapshot of a regular file content
+type Version struct {
+	// ID identifies the version in the file history, newer versions have greater ids
+	ID int
+	// Size is the content size in bytes
+	Size int
+	// ModTime is the last time the content was modified before the snapshot
+	ModTime time.Time
+	// CreatedAt is the time the snapshot was taken
+	CreatedAt time.Time
+}
+
+// Policy limits the versions kept for every regular file.
+// Versions are captured only if at least one limit is set.
+// The most recent version is always kept.
+type Policy struct {
+	// MaxVersions is the maximum number of versions kept, zero for no limit
+	MaxVersions int
+	// MaxAge is the maximum age of the versions kept, zero for no limit
+	MaxAge time.Duration
+}
+
+// Enabled returns true if versions are captured
+func (p Policy) Enabled() bool {
+	return p.MaxVersions > 0 || p.MaxAge > 0
+}
@@ -0,0 +1,57 @@
+package fsvirtual
+
+import "crypto/rand"
+
+var (
+	// Null reads as empty and discards every write
+	Null File = nullDevice{}
+	// Zero reads as an endless sequence of zeros and discards every write
+	Zero File = zeroDevice{}
+	// Urandom reads as an endless sequence of random bytes and discards every write
+	Urandom File = urandomDevice{}
+)
+
+// Devices returns a directory with the standard devices: null, zero and urandom
+func Devices() Dir {
+	return Dir{
+		"null":    Null,
+		"zero":    Zero,
+		"urandom": Urandom,
+	}
+}
+
+// The devices have no state, so they are their own handle
+type nullDevice struct{}
+type zeroDevice struct{}
+type urandomDevice struct{}
+
+func (d nullDevice) Open() (Handle, error) { return d, nil }
+
+func (nullDevice) ReadAt(buff []byte, offset int) (int, error) { return 0, nil }
+
+func (nullDevice) WriteAt(content []byte, offset int) (int, error) { return len(content), nil }
+
+func (nullDevice) Size() int { return 0 }
+
+func (d zeroDevice) Open() (Handle, error) { return d, nil }
+
+func (zeroDevice) ReadAt(buff []byte, offset int) (int, error) {
+	for i := range buff {
+		buff[i] = 0
+	}
+	return len(buff), nil
+}
+
+func (zeroDevice) WriteAt(content []byte, offset int) (int, error) { return len(content), nil }
+
+func (zeroDevice) Size() int { return Unbounded }
+
+func (d urandomDevice) Open() (Handle, error) { return d, nil }
+
+func (urandomDevice) ReadAt(buff []byte, offset int) (int, error) {
+	return rand.Read(buff)
+}
+
+func (urandomDevice) WriteAt(content []byte, offset int) (int, error) { return len(content), nil }
+
+func (urandomDevice) Size() int { return Unbounded }
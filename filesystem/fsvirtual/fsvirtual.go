@@ -0,0 +1,82 @@
+package fsvirtual
+
+import "material/filesystem/filesystem/fserrors"
+
+// Unbounded is the size of a content without end, like the one of a zero device
+const Unbounded = -1
+
+// Node is either a File or a Dir
+type Node interface{}
+
+// Dir is a virtual directory.
+// Its entries are fixed when the directory is mounted.
+type Dir map[string]Node
+
+// File is a virtual regular file
+type File interface {
+	// Open is called every time the file is opened and returns the handle
+	// used until the file is closed. Generated content should be generated here,
+	// so that it stays consistent across reads.
+	Open() (Handle, error)
+}
+
+// Handle reads and writes an open virtual file.
+// It's used concurrently and must be thread safe.
+// Errors should wrap the fserrors values, so that callers can match them with errors.Is.
+// The file system records the operation and the descriptor in a *fserrors.PathError,
+// that callers read with errors.As.
+type Handle interface {
+	// ReadAt reads up to len(buff) bytes starting at offset.
+	// Returns 0 bytes at the end of the content.
+	ReadAt(buff []byte, offset int) (int, error)
+	// WriteAt handles content written at offset
+	WriteAt(content []byte, offset int) (int, error)
+	// Size returns the content size in bytes, or Unbounded
+	Size() int
+}
+
+// FileFuncs is a File generating its content with ReadFn when opened,
+// and passing every write to WriteFn.
+// If WriteFn is nil, the file is read only.
+type FileFuncs struct {
+	ReadFn  func() ([]byte, error)
+	WriteFn func(content []byte) error
+}
+
+func (f FileFuncs) Open() (Handle, error) {
+	content := []byte{}
+	if f.ReadFn != nil {
+		var err error
+		if content, err = f.ReadFn(); err != nil {
+			return nil, err
+		}
+	}
+	return &contentHandle{content: content, writeFn: f.WriteFn}, nil
+}
+
+// contentHandle reads a content generated on open
+type contentHandle struct {
+	content []byte
+	writeFn func(content []byte) error
+}
+
+func (h *contentHandle) ReadAt(buff []byte, offset int) (int, error) {
+	if offset >= len(h.content) {
+		return 0, nil
+	}
+	return copy(buff, h.content[offset:]), nil
+}
+
+func (h *contentHandle) WriteAt(content []byte, offset int) (int, error) {
+	if h.writeFn == nil {
+		return 0, fserrors.ErrOperationNotSupported
+	}
+	if err := h.writeFn(content); err != nil {
+		return 0, err
+	}
+	return len(content), nil
+}
+
+func (h *contentHandle) Size() int {
+	return len(h.content)
+}
@@ -0,0 +1,65 @@
+package memoryfs
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+)
+
+// isValidCompression returns true if the compression is supported
+func isValidCompression(compression file.Compression) bool {
+	switch compression {
+	case file.NoCompression, file.Flate, file.Gzip:
+		return true
+	}
+	return false
+}
+
+// compress encodes the content with the given compression
+func compress(compression file.Compression, content []byte) ([]byte, error) {
+	var buff bytes.Buffer
+	var w io.WriteCloser
+	switch compression {
+	case file.Flate:
+		w, _ = flate.NewWriter(&buff, flate.DefaultCompression)
+	case file.Gzip:
+		w = gzip.NewWriter(&buff)
+	default:
+		return content, nil
+	}
+
+	if _, err := w.Write(content); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buff.Bytes(), nil
+}
+
+// decompress decodes the data encoded with the given compression.
+// size is the length of the decoded content.
+func decompress(compression file.Compression, data []byte, size int) ([]byte, error) {
+	var r io.Reader
+	switch compression {
+	case file.Flate:
+		r = flate.NewReader(bytes.NewReader(data))
+	case file.Gzip:
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fserrors.ErrCorruptedData
+		}
+		r = gr
+	default:
+		return data, nil
+	}
+
+	content := make([]byte, size)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return nil, fserrors.ErrCorruptedData
+	}
+	return content, nil
+}
@@ -0,0 +1,346 @@
+package memoryfs
+
+import (
+	"io"
+	"sort"
+)
+
+// blockList is the content of a file split in blocks.
+// Blocks are immutable: writes replace the blocks they touch with new ones.
+// Ranges never written are holes, which read as zeros without being allocated.
+type blockList struct {
+	blocks []*block
+	// offsets[i] is the position of blocks[i] in the file
+	offsets []int
+	size    int
+}
+
+// find returns the index of the block containing pos and the position
+// in the block. If pos is the end of the content, returns the last block.
+func (l *blockList) find(pos int) (int, int) {
+	if pos >= l.size {
+		last := len(l.blocks) - 1
+		return last, pos - l.offsets[last]
+	}
+	idx := sort.Search(len(l.offsets), func(i int) bool {
+		return l.offsets[i] > pos
+	}) - 1
+	return idx, pos - l.offsets[idx]
+}
+
+// insert inserts content at pos, shifting the following bytes.
+// The touched block is merged with content and chunked again.
+// If pos is in a hole, the hole is split around content.
+func (l *blockList) insert(store *blockStore, content []byte, pos int, encoding blockEncoding) error {
+	if len(content) == 0 {
+		return nil
+	}
+
+	if len(l.blocks) == 0 {
+		newBlocks, err := encode(store, content, encoding)
+		if err != nil {
+			return err
+		}
+		l.splice(store, 0, 0, newBlocks)
+		return nil
+	}
+
+	idx, blockPos := l.find(pos)
+	b := l.blocks[idx]
+	if b.isHole() {
+		newBlocks, err := encode(store, content, encoding)
+		if err != nil {
+			return err
+		}
+		newBlocks = append([]*block{newHole(blockPos)}, append(newBlocks, newHole(b.size-blockPos))...)
+		l.splice(store, idx, idx+1, newBlocks)
+		return nil
+	}
+
+	old, err := b.content()
+	if err != nil {
+		return err
+	}
+	merged := make([]byte, 0, len(old)+len(content))
+	merged = append(merged, old[:blockPos]...)
+	merged = append(merged, content...)
+	merged = append(merged, old[blockPos:]...)
+	newBlocks, err := encode(store, merged, encoding)
+	if err != nil {
+		return err
+	}
+	l.splice(store, idx, idx+1, newBlocks)
+	return nil
+}
+
+// appendHole appends a hole of the given size
+func (l *blockList) appendHole(size int) {
+	if size > 0 {
+		l.splice(nil, len(l.blocks), len(l.blocks), []*block{newHole(size)})
+	}
+}
+
+// punchHole replaces the content in [offset, offset+length) with a hole.
+// The size doesn't change.
+func (l *blockList) punchHole(store *blockStore, offset int, length int, encoding blockEncoding) error {
+	if offset >= l.size || length <= 0 {
+		return nil
+	}
+
+	end := offset + length
+	if end > l.size {
+		end = l.size
+	}
+	first, firstPos := l.find(offset)
+	last, lastPos := l.find(end - 1)
+
+	left, err := l.slice(store, first, 0, firstPos, encoding)
+	if err != nil {
+		return err
+	}
+	right, err := l.slice(store, last, lastPos+1, l.blocks[last].size, encoding)
+	if err != nil {
+		store.release(left)
+		return err
+	}
+
+	newBlocks := append(left, newHole(end-offset))
+	newBlocks = append(newBlocks, right...)
+	l.splice(store, first, last+1, newBlocks)
+	return nil
+}
+
+// slice returns the blocks storing the range [from, to) of the block at idx
+func (l *blockList) slice(store *blockStore, idx int, from int, to int, encoding blockEncoding) ([]*block, error) {
+	if from >= to {
+		return nil, nil
+	}
+
+	b := l.blocks[idx]
+	if b.isHole() {
+		return []*block{newHole(to - from)}, nil
+	}
+
+	content, err := b.content()
+	if err != nil {
+		return nil, err
+	}
+	return encode(store, content[from:to], encoding)
+}
+
+// splice replaces the blocks in [from, to) with newBlocks, releasing the old ones.
+// Empty holes are removed and adjacent holes are merged.
+func (l *blockList) splice(store *blockStore, from int, to int, newBlocks []*block) {
+	if store != nil {
+		store.release(l.blocks[from:to])
+	}
+
+	blocks := make([]*block, 0, len(l.blocks)-(to-from)+len(newBlocks))
+	for _, b := range append(append(append([]*block{}, l.blocks[:from]...), newBlocks...), l.blocks[to:]...) {
+		if !b.isHole() {
+			blocks = append(blocks, b)
+			continue
+		}
+		if b.size == 0 {
+			continue
+		}
+		if last := len(blocks) - 1; last >= 0 && blocks[last].isHole() {
+			// holes are shared by clones, so create a new one
+			blocks[last] = newHole(blocks[last].size + b.size)
+			continue
+		}
+		blocks = append(blocks, b)
+	}
+
+	l.blocks = blocks
+	l.updateOffsets()
+}
+
+// encode splits the content in chunks and stores them with the given encoding
+func encode(store *blockStore, content []byte, encoding blockEncoding) ([]*block, error) {
+	blocks := []*block{}
+	for _, c := range chunk(content) {
+		b, err := store.put(c, encoding)
+		if err != nil {
+			store.release(blocks)
+			return nil, err
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, nil
+}
+
+// reencode stores every block with the given encoding
+func (l *blockList) reencode(store *blockStore, encoding blockEncoding) error {
+	newBlocks := make([]*block, 0, len(l.blocks))
+	for _, b := range l.blocks {
+		if b.isHole() || b.isEncodedWith(encoding) {
+			store.acquire([]*block{b})
+			newBlocks = append(newBlocks, b)
+			continue
+		}
+
+		content, err := b.content()
+		if err == nil {
+			b, err = store.put(content, encoding)
+		}
+		if err != nil {
+			store.release(newBlocks)
+			return err
+		}
+		newBlocks = append(newBlocks, b)
+	}
+
+	store.release(l.blocks)
+	l.blocks = newBlocks
+	return nil
+}
+
+func (l *blockList) updateOffsets() {
+	l.offsets = make([]int, len(l.blocks))
+	l.size = 0
+	for i, b := range l.blocks {
+		l.offsets[i] = l.size
+		l.size += b.size
+	}
+}
+
+// read copies the content starting at start into buff.
+// Only the blocks overlapping the requested range are decoded.
+// Returns the number of bytes read.
+func (l *blockList) read(start int, buff []byte) (int, error) {
+	if start >= l.size {
+		return 0, nil
+	}
+
+	idx, blockPos := l.find(start)
+	n := 0
+	for ; idx < len(l.blocks) && n < len(buff); idx++ {
+		b := l.blocks[idx]
+		if b.isHole() {
+			n += zero(buff[n:], b.size-blockPos)
+			blockPos = 0
+			continue
+		}
+
+		content, err := b.content()
+		if err != nil {
+			return n, err
+		}
+		n += copy(buff[n:], content[blockPos:])
+		blockPos = 0
+	}
+	return n, nil
+}
+
+// writeTo writes the whole content to w without allocating the holes
+func (l *blockList) writeTo(w io.Writer) error {
+	zeros := []byte{}
+	for _, b := range l.blocks {
+		if !b.isHole() {
+			content, err := b.content()
+			if err != nil {
+				return err
+			}
+			w.Write(content)
+			continue
+		}
+
+		if len(zeros) == 0 {
+			zeros = make([]byte, maxBlockSize)
+		}
+		for remaining := b.size; remaining > 0; remaining -= maxBlockSize {
+			if remaining < maxBlockSize {
+				w.Write(zeros[:remaining])
+			} else {
+				w.Write(zeros)
+			}
+		}
+	}
+	return nil
+}
+
+// bytes returns a copy of the whole content
+func (l *blockList) bytes() ([]byte, error) {
+	content := make([]byte, l.size)
+	n, err := l.read(0, content)
+	return content[:n], err
+}
+
+// seekData returns the first position >= offset which is not in a hole.
+// Returns false if there is no data after offset.
+func (l *blockList) seekData(offset int) (int, bool) {
+	if offset >= l.size {
+		return 0, false
+	}
+	idx, _ := l.find(offset)
+	for ; idx < len(l.blocks); idx++ {
+		if !l.blocks[idx].isHole() {
+			return maxInt(offset, l.offsets[idx]), true
+		}
+	}
+	return 0, false
+}
+
+// seekHole returns the first position >= offset in a hole.
+// The end of the content is considered a hole.
+// Returns false if offset is past the end of the content.
+func (l *blockList) seekHole(offset int) (int, bool) {
+	if offset >= l.size {
+		return 0, false
+	}
+	idx, _ := l.find(offset)
+	for ; idx < len(l.blocks); idx++ {
+		if l.blocks[idx].isHole() {
+			return maxInt(offset, l.offsets[idx]), true
+		}
+	}
+	return l.size, true
+}
+
+// physicalSize returns the memory used by the blocks.
+// Holes are not allocated.
+func (l *blockList) physicalSize() int {
+	size := 0
+	for _, b := range l.blocks {
+		size += len(b.data)
+	}
+	return size
+}
+
+// clone returns a list sharing the same blocks
+func (l *blockList) clone(store *blockStore) *blockList {
+	store.acquire(l.blocks)
+	return &blockList{
+		blocks:  append([]*block{}, l.blocks...),
+		offsets: append([]int{}, l.offsets...),
+		size:    l.size,
+	}
+}
+
+// release releases every block and empties the list
+func (l *blockList) release(store *blockStore) {
+	store.release(l.blocks)
+	l.blocks = nil
+	l.offsets = nil
+	l.size = 0
+}
+
+// zero sets the first n bytes of buff to 0.
+// Returns the number of bytes set.
+func zero(buff []byte, n int) int {
+	if n > len(buff) {
+		n = len(buff)
+	}
+	for i := range buff[:n] {
+		buff[i] = 0
+	}
+	return n
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,268 @@
+package memoryfs
+
+import (
+	"crypto/sha256"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"sync"
+)
+
+// Content defined chunking parameters.
+// Chunk boundaries depend only on the content, so inserting bytes in a file
+// only changes the chunks around the insertion point.
+const (
+	minBlockSize = 2 << 10
+	maxBlockSize = 64 << 10
+	// a boundary is found on average every 8KB after minBlockSize.
+	// The mask uses the high bits of the hash, which depend on the last 64 bytes.
+	blockBoundaryMask = uint64(8<<10-1) << 51
+)
+
+// gearTable maps every byte to a random value used by the rolling hash
+var gearTable = newGearTable()
+
+type blockHash [sha256.Size]byte
+
+// blockKey identifies a block by its content and the requested compression.
+// The hash of encrypted blocks is keyed with the file data key.
+type blockKey struct {
+	hash        blockHash
+	compression file.Compression
+}
+
+// blockEncoding describes how new blocks are stored
+type blockEncoding struct {
+	compression file.Compression
+	// key encrypting the blocks, nil if the blocks are not encrypted
+	dataKey *dataKey
+}
+
+// block is an immutable piece of content shared by every file containing it
+type block struct {
+	key blockKey
+	// stored content, compressed if compression is set
+	data []byte
+	// length of the decoded content
+	size int
+	// compression used for data. NoCompression if compressing didn't save space
+	compression file.Compression
+	// key encrypting data, nil if data is not encrypted
+	dataKey *dataKey
+	// number of references from the file contents
+	refs int
+}
+
+// newHole creates a range of zeros which is not allocated.
+// Holes are not stored in the block store.
+func newHole(size int) *block {
+	return &block{size: size}
+}
+
+// isHole returns true if the block is a range of zeros which is not allocated
+func (b *block) isHole() bool {
+	return b.data == nil
+}
+
+// content returns the decoded content.
+// The returned slice must not be modified.
+func (b *block) content() ([]byte, error) {
+	if b.isHole() {
+		return make([]byte, b.size), nil
+	}
+
+	data := b.data
+	if b.dataKey != nil {
+		var err error
+		if data, err = b.dataKey.decrypt(data); err != nil {
+			return nil, err
+		}
+	}
+	return decompress(b.compression, data, b.size)
+}
+
+// isEncodedWith returns true if the block is stored with the given encoding
+func (b *block) isEncodedWith(encoding blockEncoding) bool {
+	return b.key.compression == encoding.compression && b.dataKey == encoding.dataKey
+}
+
+// blockStore is a content addressed store of blocks.
+// Identical blocks are stored once and released when no file references them.
+type blockStore struct {
+	blocks map[blockKey]*block
+	// bytes used by the blocks
+	storedBytes int
+	// maximum number of bytes used by the blocks, zero for no limit
+	capacity int
+	// maximum size of a file content, zero for no limit
+	maxFileSize int
+	sync.Mutex
+}
+
+// blockStoreStats describes the memory used by the block store
+type blockStoreStats struct {
+	// number of unique blocks
+	blocks int
+	// bytes used by the unique blocks, after compression
+	storedBytes int
+	// bytes the blocks would use without deduplication
+	referencedBytes int
+}
+
+func newBlockStore(capacity int, maxFileSize int) *blockStore {
+	return &blockStore{
+		blocks:      map[blockKey]*block{},
+		capacity:    capacity,
+		maxFileSize: maxFileSize,
+	}
+}
+
+// put returns the block holding content, creating it if it doesn't exist.
+// New blocks are compressed and then encrypted with the given encoding.
+// Fails with ErrNoSpace if a new block exceeds the capacity.
+// This implementation is thread safe.
+func (s *blockStore) put(content []byte, encoding blockEncoding) (*block, error) {
+	key := blockKey{hash: sha256.Sum256(content), compression: encoding.compression}
+	if encoding.dataKey != nil {
+		key.hash = encoding.dataKey.hash(content)
+	}
+	if b := s.acquireKey(key); b != nil {
+		return b, nil
+	}
+
+	// encode without holding the lock
+	b := &block{key: key, size: len(content), compression: encoding.compression, dataKey: encoding.dataKey, refs: 1}
+	data, err := compress(encoding.compression, content)
+	if err != nil {
+		return nil, err
+	}
+	if encoding.compression == file.NoCompression || len(data) >= len(content) {
+		data, b.compression = append([]byte{}, content...), file.NoCompression
+	}
+	if encoding.dataKey != nil {
+		if data, err = encoding.dataKey.encrypt(data); err != nil {
+			return nil, err
+		}
+	}
+	b.data = data
+
+	s.Lock()
+	defer s.Unlock()
+	// the same block could have been stored in the meantime
+	if existing, found := s.blocks[key]; found {
+		existing.refs++
+		return existing, nil
+	}
+	if s.capacity > 0 && s.storedBytes+len(b.data) > s.capacity {
+		return nil, fserrors.ErrNoSpace
+	}
+	s.blocks[key] = b
+	s.storedBytes += len(b.data)
+	return b, nil
+}
+
+// acquireKey adds a reference to the block with the given key.
+// Returns nil if the block doesn't exist.
+func (s *blockStore) acquireKey(key blockKey) *block {
+	s.Lock()
+	defer s.Unlock()
+	b, found := s.blocks[key]
+	if !found {
+		return nil
+	}
+	b.refs++
+	return b
+}
+
+// acquire adds a reference to every block.
+// This implementation is thread safe.
+func (s *blockStore) acquire(blocks []*block) {
+	s.Lock()
+	defer s.Unlock()
+	for _, b := range blocks {
+		if b.isHole() {
+			continue
+		}
+		b.refs++
+	}
+}
+
+// release removes a reference from every block,
+// deleting the blocks no longer referenced.
+// This implementation is thread safe.
+func (s *blockStore) release(blocks []*block) {
+	s.Lock()
+	defer s.Unlock()
+	for _, b := range blocks {
+		if b.isHole() {
+			continue
+		}
+		b.refs--
+		if b.refs <= 0 {
+			delete(s.blocks, b.key)
+			s.storedBytes -= len(b.data)
+		}
+	}
+}
+
+// stats returns the memory used by the store.
+// This implementation is thread safe.
+func (s *blockStore) stats() blockStoreStats {
+	s.Lock()
+	defer s.Unlock()
+
+	stats := blockStoreStats{blocks: len(s.blocks)}
+	for _, b := range s.blocks {
+		stats.storedBytes += len(b.data)
+		stats.referencedBytes += len(b.data) * b.refs
+	}
+	return stats
+}
+
+// chunk splits the content using a gear rolling hash.
+// Chunks are between minBlockSize and maxBlockSize long, except the last one
+// which can be shorter.
+func chunk(content []byte) [][]byte {
+	chunks := [][]byte{}
+	for len(content) > 0 {
+		size := nextBoundary(content)
+		chunks = append(chunks, content[:size])
+		content = content[size:]
+	}
+	return chunks
+}
+
+// nextBoundary returns the length of the first chunk of content
+func nextBoundary(content []byte) int {
+	if len(content) <= minBlockSize {
+		return len(content)
+	}
+
+	end := len(content)
+	if end > maxBlockSize {
+		end = maxBlockSize
+	}
+
+	var hash uint64
+	for i := minBlockSize; i < end; i++ {
+		hash = (hash << 1) + gearTable[content[i]]
+		if hash&blockBoundaryMask == 0 {
+			return i + 1
+		}
+	}
+	return end
+}
+
+// newGearTable generates the table using splitmix64 with a fixed seed,
+// so that boundaries are stable across runs.
+func newGearTable() [256]uint64 {
+	table := [256]uint64{}
+	seed := uint64(0x9e3779b97f4a7c15)
+	for i := range table {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		table[i] = z ^ (z >> 31)
+	}
+	return table
+}
@@ -0,0 +1,157 @@
+package memoryfs_test
+
+import (
+	"errors"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsquery"
+	"material/filesystem/filesystem/memoryfs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaseInsensitive(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem)
+	}{
+		{
+			CaseName: "Names differing only in case refer to the same file",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/README.md", nil)
+				_, err := fs.CreateRegularFile(p)
+				assert.True(t, errors.Is(err, fserrors.ErrExist))
+
+				p, _ = fspath.NewFileSystemPath("/DOCS/GUIDE.txt", nil)
+				stat, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.Equal(t, "/Docs/guide.txt", stat.AbsolutePath())
+
+				root, _ := fspath.NewFileSystemPath("/", nil)
+				files, err := fs.ListFiles(root)
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"Docs", "Readme.md"}, fileNames(files))
+			},
+		},
+		{
+			CaseName: "Unicode case folding",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/Straße", nil)
+				_, err := fs.CreateRegularFile(p)
+				assert.Nil(t, err)
+				p, _ = fspath.NewFileSystemPath("/ΣΟΦΙΑ", nil)
+				_, err = fs.CreateRegularFile(p)
+				assert.Nil(t, err)
+
+				for _, name := range []string{"/STRASSE", "/strasse", "/σοφια", "/Σοφια"} {
+					p, _ := fspath.NewFileSystemPath(name, nil)
+					_, err := fs.Stat(p)
+					assert.Nil(t, err, name)
+				}
+				p, _ = fspath.NewFileSystemPath("/σοφιά", nil)
+				_, err = fs.Stat(p)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+			},
+		},
+		{
+			CaseName: "Move to a name differing only in case renames the file",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				src, _ := fspath.NewFileSystemPath("/Readme.md", nil)
+				dest, _ := fspath.NewFileSystemPath("/README.md", nil)
+				info, err := fs.Move(src, dest)
+				assert.Nil(t, err)
+				assert.Equal(t, "/README.md", info.AbsolutePath())
+
+				root, _ := fspath.NewFileSystemPath("/", nil)
+				files, err := fs.ListFiles(root)
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"Docs", "README.md"}, fileNames(files))
+
+				_, err = fs.Copy(dest, src)
+				assert.True(t, errors.Is(err, fserrors.ErrSameFile))
+			},
+		},
+		{
+			CaseName: "Move detects conflicts ignoring case",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				src, _ := fspath.NewFileSystemPath("/new/DOCS", nil)
+				_, err := fs.MkdirAll(src)
+				assert.Nil(t, err)
+				src, _ = fspath.NewFileSystemPath("/new/DOCS/Guide.TXT", nil)
+				_, err = fs.CreateRegularFile(src)
+				assert.Nil(t, err)
+
+				// the directories are merged and the file is renamed
+				src, _ = fspath.NewFileSystemPath("/new/DOCS", nil)
+				dest, _ := fspath.NewFileSystemPath("/", nil)
+				info, err := fs.Move(src, dest)
+				assert.Nil(t, err)
+				assert.Equal(t, "/Docs", info.AbsolutePath())
+
+				p, _ := fspath.NewFileSystemPath("/docs", nil)
+				files, err := fs.ListFiles(p)
+				assert.Nil(t, err)
+				assert.Len(t, files, 2)
+				assert.NotContains(t, fileNames(files), "Guide.TXT")
+			},
+		},
+		{
+			CaseName: "Find and glob ignore case",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				root, _ := fspath.NewFileSystemPath("/", nil)
+				found, err := fs.FindFiles("^GUIDE", root)
+				assert.Nil(t, err)
+				assert.Len(t, found, 1)
+
+				query := fsquery.NewQuery()
+				query.Match, _ = fsquery.NameGlob("*.MD")
+				stats, err := fs.Find(root, query)
+				assert.Nil(t, err)
+				assert.Len(t, stats, 1)
+
+				p, _ := fspath.NewFileSystemPath("/docs/*.TXT", nil)
+				found, err = fs.Glob(p)
+				assert.Nil(t, err)
+				assert.Len(t, found, 1)
+				assert.Equal(t, "/Docs/guide.txt", found[0].AbsolutePath())
+
+				p, _ = fspath.NewFileSystemPath("/DOCS/[G]uide.txt", nil)
+				found, err = fs.Glob(p)
+				assert.Nil(t, err)
+				assert.Len(t, found, 1)
+			},
+		},
+		{
+			CaseName: "Transactions keep the mode",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				tx := fs.Begin()
+				defer tx.Rollback()
+
+				p, _ := fspath.NewFileSystemPath("/readme.MD", nil)
+				_, err := tx.CreateRegularFile(p)
+				assert.True(t, errors.Is(err, fserrors.ErrExist))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.CaseName, func(t *testing.T) {
+			fs, err := memoryfs.NewMemoryFileSystem(memoryfs.WithCaseInsensitiveNames())
+			if err != nil {
+				t.Fatal(err)
+			}
+			p, _ := fspath.NewFileSystemPath("/Docs", nil)
+			if _, err := fs.Mkdir(p); err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range []string{"/Readme.md", "/Docs/guide.txt"} {
+				p, _ := fspath.NewFileSystemPath(name, nil)
+				if _, err := fs.CreateRegularFile(p); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c.Assertions(t, fs)
+		})
+	}
+}
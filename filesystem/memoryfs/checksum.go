@@ -0,0 +1,54 @@
+package memoryfs
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"hash"
+	"hash/crc32"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+)
+
+// castagnoliTable is the CRC-32C polynomial table, built once and shared by every hash
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+var checksumHashes = map[file.ChecksumAlgorithm]func() hash.Hash{
+	file.SHA256: sha256.New,
+	file.SHA1:   sha1.New,
+	file.MD5:    md5.New,
+	file.CRC32C: func() hash.Hash { return crc32.New(castagnoliTable) },
+}
+
+// Checksum returns the digest of the named file content computed with the given algorithm.
+// Digests are cached until the file is modified.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the file does not exist
+// - the file is not a regular file
+// - the algorithm is not supported
+func (fs *MemoryFileSystem) Checksum(path *fspath.FileSystemPath, algorithm file.ChecksumAlgorithm) ([]byte, error) {
+	newHash, found := checksumHashes[algorithm]
+	if !found {
+		return nil, pathError("checksum", path, fserrors.ErrInvalid)
+	}
+
+	fs.RLock()
+	fileToHash, err := fs.traverseToBase(path)
+	fs.RUnlock()
+	if err != nil {
+		return nil, pathError("checksum", path, err)
+	}
+
+	if err := checkRegularFile(fileToHash); err != nil {
+		return nil, pathError("checksum", path, err)
+	}
+	if fileToHash.isVirtual {
+		return nil, pathError("checksum", path, fserrors.ErrOperationNotSupported)
+	}
+
+	sum, err := fileToHash.data.checksum(algorithm, newHash)
+	return sum, pathError("checksum", path, err)
+}
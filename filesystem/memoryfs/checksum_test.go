@@ -0,0 +1,168 @@
+package memoryfs_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/memoryfs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func initializeChecksumFile() (*memoryfs.MemoryFileSystem, file.File, error) {
+	fs := newFileSystem()
+	p, _ := fspath.NewFileSystemPath("/dir/file", nil)
+	if err := fs.AppendAll(p, []byte("hello world")); err != nil {
+		return nil, nil, err
+	}
+	return fs, nil, nil
+}
+
+func TestChecksum(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Path       string
+		Algorithm  file.ChecksumAlgorithm
+		Initialize func() (*memoryfs.MemoryFileSystem, file.File, error)
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem, digest string, err error)
+	}{
+		{
+			CaseName:   "SHA-256 using absolute path",
+			Path:       "/dir/file",
+			Algorithm:  file.SHA256,
+			Initialize: initializeChecksumFile,
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, digest string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", digest)
+			},
+		},
+		{
+			CaseName:  "SHA-1 using relative path",
+			Path:      "file",
+			Algorithm: file.SHA1,
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs, _, err := initializeChecksumFile()
+				if err != nil {
+					return nil, nil, err
+				}
+				p, _ := fspath.NewFileSystemPath("/dir", nil)
+				workingDir, err := fs.GetDirectory(p)
+				return fs, workingDir, err
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, digest string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "2aae6c35c94fcfb415dbe95f408b9ce91ee846ed", digest)
+			},
+		},
+		{
+			CaseName:   "MD5",
+			Path:       "/dir/file",
+			Algorithm:  file.MD5,
+			Initialize: initializeChecksumFile,
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, digest string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "5eb63bbbe01eeed093cb22bb8f5acdc3", digest)
+			},
+		},
+		{
+			CaseName:   "CRC32C",
+			Path:       "/dir/file",
+			Algorithm:  file.CRC32C,
+			Initialize: initializeChecksumFile,
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, digest string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "c99465aa", digest)
+			},
+		},
+		{
+			CaseName:  "Cached digest is invalidated by writes",
+			Path:      "/dir/file",
+			Algorithm: file.SHA256,
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs, _, err := initializeChecksumFile()
+				if err != nil {
+					return nil, nil, err
+				}
+				p, _ := fspath.NewFileSystemPath("/dir/file", nil)
+				// cache the digest of "hello world"
+				if _, err := fs.Checksum(p, file.SHA256); err != nil {
+					return nil, nil, err
+				}
+				fd, err := fs.Open(p)
+				if err != nil {
+					return nil, nil, err
+				}
+				defer fs.Close(fd)
+				if _, err := fs.WriteAt(fd, []byte("!"), 11); err != nil {
+					return nil, nil, err
+				}
+				return fs, nil, nil
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, digest string, err error) {
+				assert.Nil(t, err)
+				// sha256("hello world!")
+				assert.Equal(t, "7509e5bda0c762d2bac7f90d758b5b2263fa01ccbc542ab5e3df163be08e6ca9", digest)
+			},
+		},
+		{
+			CaseName:  "Hard links share the digest",
+			Path:      "/link",
+			Algorithm: file.MD5,
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs, _, err := initializeChecksumFile()
+				if err != nil {
+					return nil, nil, err
+				}
+				p1, _ := fspath.NewFileSystemPath("/dir/file", nil)
+				p2, _ := fspath.NewFileSystemPath("/link", nil)
+				if _, err := fs.CreateHardLink(p1, p2); err != nil {
+					return nil, nil, err
+				}
+				return fs, nil, nil
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, digest string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "5eb63bbbe01eeed093cb22bb8f5acdc3", digest)
+			},
+		},
+		{
+			CaseName:   "Unsupported algorithm",
+			Path:       "/dir/file",
+			Algorithm:  file.ChecksumAlgorithm(100),
+			Initialize: initializeChecksumFile,
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, digest string, err error) {
+				assert.True(t, errors.Is(err, fserrors.ErrInvalid))
+			},
+		},
+		{
+			CaseName:   "Checksum of a directory",
+			Path:       "/dir",
+			Algorithm:  file.SHA256,
+			Initialize: initializeChecksumFile,
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, digest string, err error) {
+				assert.True(t, errors.Is(err, fserrors.ErrIsDir))
+			},
+		},
+		{
+			CaseName:   "File does not exist",
+			Path:       "/invalid",
+			Algorithm:  file.SHA256,
+			Initialize: initializeChecksumFile,
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, digest string, err error) {
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+			},
+		},
+	}
+	for _, testCase := range cases {
+		fs, workingDir, err := testCase.Initialize()
+		if err != nil {
+			t.Fatal("error initializing file system")
+		}
+		p, _ := fspath.NewFileSystemPath(testCase.Path, workingDir)
+		digest, err := fs.Checksum(p, testCase.Algorithm)
+		testCase.Assertions(t, fs, hex.EncodeToString(digest), err)
+	}
+}
@@ -0,0 +1,35 @@
+package memoryfs
+
+import (
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+)
+
+// SetCompression sets the compression of the named file content.
+// If the file is a directory, the compression is set on every file
+// in the directory tree, and files created in it afterwards inherit it.
+// Existing content is recompressed. Symbolic links in the tree are not followed.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the file does not exist
+// - the compression is not supported
+func (fs *MemoryFileSystem) SetCompression(path *fspath.FileSystemPath, compression file.Compression) error {
+	if !isValidCompression(compression) {
+		return pathError("compress", path, fserrors.ErrInvalid)
+	}
+
+	fs.Lock()
+	defer fs.Unlock()
+
+	fileToCompress, err := fs.traverseToBase(path)
+	if err != nil {
+		return pathError("compress", path, err)
+	}
+
+	err = fs.doWalkDir(fileToCompress, fileToCompress.info.AbsolutePath(), 0, map[*inMemoryFile]bool{}, func(_ string, f *inMemoryFile, _ int) error {
+		return f.data.setCompression(compression)
+	})
+	return pathError("compress", path, err)
+}
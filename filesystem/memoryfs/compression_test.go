@@ -0,0 +1,179 @@
+package memoryfs_test
+
+import (
+	"bytes"
+	"errors"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/memoryfs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var logContent = bytes.Repeat([]byte("2022-10-19 INFO request served in 10ms\n"), 10000)
+
+func initializeCompressionTree() (*memoryfs.MemoryFileSystem, error) {
+	fs := newFileSystem()
+	p, _ := fspath.NewFileSystemPath("/logs/old", nil)
+	if _, err := fs.MkdirAll(p); err != nil {
+		return nil, err
+	}
+	for _, name := range []string{"/logs/app.log", "/logs/old/app.log", "/app.log"} {
+		if err := createFileWithContent(fs, name, logContent); err != nil {
+			return nil, err
+		}
+	}
+	return fs, nil
+}
+
+func TestSetCompression(t *testing.T) {
+	cases := []struct {
+		CaseName    string
+		Path        string
+		Compression file.Compression
+		Assertions  func(t *testing.T, fs *memoryfs.MemoryFileSystem, err error)
+	}{
+		{
+			CaseName:    "Compress directory tree",
+			Path:        "/logs",
+			Compression: file.Flate,
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, err error) {
+				assert.Nil(t, err)
+				for _, name := range []string{"/logs/app.log", "/logs/old/app.log"} {
+					p, _ := fspath.NewFileSystemPath(name, nil)
+					stat, err := fs.Stat(p)
+					assert.Nil(t, err)
+					assert.Equal(t, len(logContent), stat.Size())
+					assert.Less(t, stat.PhysicalSize(), len(logContent)/10)
+
+					data, err := fs.ReadAll(p)
+					assert.Nil(t, err)
+					assert.Equal(t, logContent, data)
+				}
+
+				p, _ := fspath.NewFileSystemPath("/app.log", nil)
+				stat, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.Equal(t, len(logContent), stat.PhysicalSize())
+			},
+		},
+		{
+			CaseName:    "Read at arbitrary offsets of compressed file",
+			Path:        "/logs/app.log",
+			Compression: file.Gzip,
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, err error) {
+				assert.Nil(t, err)
+				p, _ := fspath.NewFileSystemPath("/logs/app.log", nil)
+				fd, err := fs.Open(p)
+				assert.Nil(t, err)
+				defer fs.Close(fd)
+
+				for _, offset := range []int{0, 100, 70000, len(logContent) - 20} {
+					buff := make([]byte, 30)
+					n, err := fs.ReadAt(fd, buff, offset)
+					assert.Nil(t, err)
+					end := offset + 30
+					if end > len(logContent) {
+						end = len(logContent)
+					}
+					assert.Equal(t, logContent[offset:end], buff[:n])
+				}
+			},
+		},
+		{
+			CaseName:    "Write to compressed file",
+			Path:        "/logs/app.log",
+			Compression: file.Flate,
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, err error) {
+				assert.Nil(t, err)
+				p, _ := fspath.NewFileSystemPath("/logs/app.log", nil)
+				fd, err := fs.Open(p)
+				assert.Nil(t, err)
+				_, err = fs.WriteAt(fd, []byte("Hello world!"), 50000)
+				assert.Nil(t, err)
+				fs.Close(fd)
+
+				expected := append([]byte{}, logContent[:50000]...)
+				expected = append(expected, []byte("Hello world!")...)
+				expected = append(expected, logContent[50000:]...)
+				data, err := fs.ReadAll(p)
+				assert.Nil(t, err)
+				assert.Equal(t, expected, data)
+			},
+		},
+		{
+			CaseName:    "New files inherit the directory compression",
+			Path:        "/logs",
+			Compression: file.Flate,
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, err error) {
+				assert.Nil(t, err)
+				assert.Nil(t, createFileWithContent(fs, "/logs/old/new.log", logContent))
+				p, _ := fspath.NewFileSystemPath("/logs/old/new.log", nil)
+				stat, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.Less(t, stat.PhysicalSize(), len(logContent)/10)
+			},
+		},
+		{
+			CaseName:    "Disable compression",
+			Path:        "/logs",
+			Compression: file.NoCompression,
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, err error) {
+				assert.Nil(t, err)
+				p, _ := fspath.NewFileSystemPath("/logs", nil)
+				assert.Nil(t, fs.SetCompression(p, file.Flate))
+				assert.Nil(t, fs.SetCompression(p, file.NoCompression))
+
+				p, _ = fspath.NewFileSystemPath("/logs/app.log", nil)
+				stat, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.Equal(t, len(logContent), stat.PhysicalSize())
+			},
+		},
+		{
+			CaseName:    "Incompressible content is stored as is",
+			Path:        "/random",
+			Compression: file.Flate,
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, err error) {
+				assert.Nil(t, err)
+				p, _ := fspath.NewFileSystemPath("/random", nil)
+				stat, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.Equal(t, stat.Size(), stat.PhysicalSize())
+			},
+		},
+		{
+			CaseName:    "Invalid compression",
+			Path:        "/logs",
+			Compression: file.Compression(10),
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, err error) {
+				assert.NotNil(t, err)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalid))
+			},
+		},
+		{
+			CaseName:    "File does not exist",
+			Path:        "/invalid",
+			Compression: file.Flate,
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, err error) {
+				assert.NotNil(t, err)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		fs, err := initializeCompressionTree()
+		if err != nil {
+			t.Fatal("error initializing file system")
+		}
+		if err := createFileWithContent(fs, "/random", randomContent(1, 64<<10)); err != nil {
+			t.Fatal("error initializing file system")
+		}
+		p, _ := fspath.NewFileSystemPath(testCase.Path, nil)
+		err = fs.SetCompression(p, testCase.Compression)
+		testCase.Assertions(t, fs, err)
+	}
+}
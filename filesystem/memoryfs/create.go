@@ -18,7 +18,8 @@ func (fs *MemoryFileSystem) Mkdir(path *fspath.FileSystemPath) (file.File, error
 	// RW lock the fs
 	fs.Lock()
 	defer fs.Unlock()
-	return fs.createAt(path, file.Directory, false)
+	newDir, err := fs.createAt(path, file.Directory, false)
+	return newDir, pathError("mkdir", path, err)
 }
 
 // MkdirAll creates a directory at the specified path,
@@ -32,7 +33,8 @@ func (fs *MemoryFileSystem) MkdirAll(path *fspath.FileSystemPath) (file.File, er
 	// RW lock the fs
 	fs.Lock()
 	defer fs.Unlock()
-	return fs.createAt(path, file.Directory, true)
+	newDir, err := fs.createAt(path, file.Directory, true)
+	return newDir, pathError("mkdir", path, err)
 }
 
 // CreateRegularFile creates a new file at the specified path
@@ -47,10 +49,8 @@ func (fs *MemoryFileSystem) CreateRegularFile(path *fspath.FileSystemPath) (file
 	fs.Lock()
 	defer fs.Unlock()
 
-	if err := checkFilePath(path); err != nil {
-		return nil, err
-	}
-	return fs.createAt(path, file.RegularFile, false)
+	newFile, err := fs.createAt(path, file.RegularFile, false)
+	return newFile, pathError("create", path, err)
 }
 
 // Link creates srcPath along with any parent directories
@@ -68,26 +68,42 @@ func (fs *MemoryFileSystem) CreateRegularFile(path *fspath.FileSystemPath) (file
 func (fs *MemoryFileSystem) CreateHardLink(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath) (file.FileInfo, error) {
 	fs.Lock()
 	defer fs.Unlock()
+	return fs.createHardLinkLockFree(srcPath, destPath)
+}
 
+// This method should be called only if the caller has already acquired a lock
+func (fs *MemoryFileSystem) createHardLinkLockFree(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath) (file.FileInfo, error) {
 	// Locate file to link
 	fileToLink, err := fs.traverseToBase(srcPath)
 	if err != nil {
-		return nil, err
+		return nil, pathError("link", srcPath, err)
 	}
 
 	// Only hard links to regular file supported
-	if fileToLink.info.fileType != file.RegularFile {
-		return nil, fserrors.ErrInvalidFileType
+	if err := checkRegularFile(fileToLink); err != nil {
+		return nil, pathError("link", srcPath, err)
+	}
+	if fileToLink.isVirtual {
+		return nil, pathError("link", srcPath, fserrors.ErrOperationNotSupported)
 	}
 
-	// Create an empty file
-	hardLink, err := fs.createAt(destPath, file.RegularFile, true)
+	if err := checkFilePath(destPath); err != nil {
+		return nil, pathError("link", destPath, err)
+	}
+	parent, err := fs.traverseToDirWithCreateParentDirs(destPath, true)
 	if err != nil {
-		return nil, err
+		return nil, pathError("link", destPath, err)
+	}
+	name, err := fs.checkNewEntry(parent, destPath.Base())
+	if err != nil {
+		return nil, pathError("link", destPath, err)
 	}
 
-	// Point the file to the same underline data
-	hardLink.data = fileToLink.data
+	// Add an entry pointing to the same inode
+	fs.journal.saveInode(fileToLink.data)
+	hardLink := newEntry(filepath.Join(parent.info.AbsolutePath(), name), fileToLink.data)
+	fs.attachToParent(hardLink, parent)
+	hardLink.data.updateLinks(1)
 	return hardLink.info, nil
 }
 
@@ -105,17 +121,21 @@ func (fs *MemoryFileSystem) CreateHardLink(srcPath *fspath.FileSystemPath, destP
 func (fs *MemoryFileSystem) CreateSymbolicLink(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath) (file.FileInfo, error) {
 	fs.Lock()
 	defer fs.Unlock()
+	return fs.createSymbolicLinkLockFree(srcPath, destPath)
+}
 
+// This method should be called only if the caller has already acquired a lock
+func (fs *MemoryFileSystem) createSymbolicLinkLockFree(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath) (file.FileInfo, error) {
 	// Create an empty file
 	symLink, err := fs.createAt(destPath, file.SymbolicLink, false)
 	if err != nil {
-		return nil, err
+		return nil, pathError("symlink", destPath, err)
 	}
 
 	// Point the file to the original file
 	pathLink, err := fspath.NewFileSystemPath(srcPath.AbsolutePath(), nil)
 	if err != nil {
-		return nil, err
+		return nil, pathError("symlink", srcPath, err)
 	}
 
 	symLink.link = pathLink
@@ -138,19 +158,47 @@ func (fs *MemoryFileSystem) createAt(path *fspath.FileSystemPath, fileType file.
 }
 
 func (fs *MemoryFileSystem) create(fileName string, fileType file.FileType, parent *inMemoryFile) (*inMemoryFile, error) {
-	// check if file exists
-	if _, found := parent.fileMap[fileName]; found {
-		return nil, fserrors.ErrExist
+	fileName, err := fs.checkNewEntry(parent, fileName)
+	if err != nil {
+		return nil, err
 	}
 
 	// create new file and add to fs tree
 	absolutePath := filepath.Join(parent.info.AbsolutePath(), fileName)
-	newFile := newInMemoryFile(absolutePath, fileType)
+	newFile := fs.newFile(absolutePath, fileType)
+	// inherit the directory policy
+	if err := fs.inheritEncoding(newFile, parent); err != nil {
+		return nil, err
+	}
 	fs.attachToParent(newFile, parent)
 	return newFile, nil
 }
 
+// checkNewEntry returns the name of a new entry of parent,
+// failing if the name is invalid or the entry already exists
+func (fs *MemoryFileSystem) checkNewEntry(parent *inMemoryFile, fileName string) (string, error) {
+	fileName, err := fs.checkNewName(parent, fileName)
+	if err != nil {
+		return "", err
+	}
+
+	// check if file exists
+	if _, found := fs.lookup(parent, fileName); found {
+		return "", fserrors.ErrExist
+	}
+	// virtual directories can't be changed
+	if parent.isVirtual {
+		return "", fserrors.ErrOperationNotSupported
+	}
+	return fileName, nil
+}
+
 func (fs *MemoryFileSystem) attachToParent(newFile *inMemoryFile, parent *inMemoryFile) {
-	parent.fileMap[newFile.info.Name()] = newFile
+	fs.journal.saveEntry(parent)
+	fs.journal.saveEntry(newFile)
+	fs.journal.saveInode(parent.data)
+	parent.addChild(fs.nameKey(newFile.info.Name()), newFile)
 	newFile.fileMap[".."] = parent
+	newFile.info.setParent(parent.info)
+	parent.data.touch()
 }
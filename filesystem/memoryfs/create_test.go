@@ -22,7 +22,7 @@ func TestMkdir(t *testing.T) {
 			CaseName: "Create directory in root - absolute path, work dir nil",
 			Path:     "/dir1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				return memoryfs.NewMemoryFileSystem(), nil, nil
+				return newFileSystem(), nil, nil
 			},
 			Assertions: func(t *testing.T, res file.File, err error) {
 				assert.Nil(t, err)
@@ -36,7 +36,7 @@ func TestMkdir(t *testing.T) {
 			CaseName: "Create directory in subdir - absolute path, work dir nil",
 			Path:     "/dir1/dir2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -59,7 +59,7 @@ func TestMkdir(t *testing.T) {
 			CaseName: "Name conflict - absolute path, work dir nil",
 			Path:     "/dir1/dir2/dir3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -78,7 +78,7 @@ func TestMkdir(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrExist)
+				assert.True(t, errors.Is(err, fserrors.ErrExist))
 				assert.Nil(t, res)
 			},
 		},
@@ -86,7 +86,7 @@ func TestMkdir(t *testing.T) {
 			CaseName: "Parent directory does not exist - absolute path, work dir nil",
 			Path:     "/dir1/dir2/dir3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -105,7 +105,7 @@ func TestMkdir(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 				assert.Nil(t, res)
 			},
 		},
@@ -113,7 +113,7 @@ func TestMkdir(t *testing.T) {
 			CaseName: "Parent is not a directory - absolute path, work dir nil",
 			Path:     "/file1/dir1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -123,7 +123,7 @@ func TestMkdir(t *testing.T) {
 			Assertions: func(t *testing.T, res file.File, err error) {
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrInvalidFileType)
+				assert.True(t, errors.Is(err, fserrors.ErrNotDir))
 				assert.Nil(t, res)
 			},
 		},
@@ -131,7 +131,7 @@ func TestMkdir(t *testing.T) {
 			CaseName: "Create directory following symlink - absolute path",
 			Path:     "/dir2/dir3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -154,7 +154,7 @@ func TestMkdir(t *testing.T) {
 			CaseName: "Create directory in root - relative path, work dir not nil",
 			Path:     "dir1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				return fs, fs.DefaultWorkingDirectory(), nil
 			},
 			Assertions: func(t *testing.T, res file.File, err error) {
@@ -169,7 +169,7 @@ func TestMkdir(t *testing.T) {
 			CaseName: "Create directory in subdir - relative path, work dir not nil",
 			Path:     "../dir1/dir2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -194,7 +194,7 @@ func TestMkdir(t *testing.T) {
 			CaseName: "Name conflict - relative path, work dir not nil",
 			Path:     "./dir2/../dir2/.//dir3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -214,7 +214,7 @@ func TestMkdir(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrExist)
+				assert.True(t, errors.Is(err, fserrors.ErrExist))
 				assert.Nil(t, res)
 			},
 		},
@@ -222,7 +222,7 @@ func TestMkdir(t *testing.T) {
 			CaseName: "Parent directory does not exist - relative path, work dir not nil",
 			Path:     "./dir2/dir3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -242,7 +242,7 @@ func TestMkdir(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 				assert.Nil(t, res)
 			},
 		},
@@ -250,7 +250,7 @@ func TestMkdir(t *testing.T) {
 			CaseName: "Parent is not a directory - relative path, work dir not nil",
 			Path:     "file1/dir1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1/", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -261,7 +261,7 @@ func TestMkdir(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrInvalidFileType)
+				assert.True(t, errors.Is(err, fserrors.ErrNotDir))
 				assert.Nil(t, res)
 			},
 		},
@@ -269,7 +269,7 @@ func TestMkdir(t *testing.T) {
 			CaseName: "Working directory previously deleted",
 			Path:     "./dir2/dir3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -293,7 +293,7 @@ func TestMkdir(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrInvalidWorkingDirectory)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalidWorkingDirectory))
 				assert.Nil(t, res)
 			},
 		},
@@ -301,7 +301,7 @@ func TestMkdir(t *testing.T) {
 			CaseName: "Create directory following symlink - relative path",
 			Path:     "../dir2/dir3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p1)
 				if err != nil {
@@ -342,7 +342,7 @@ func TestCreateRegularFile(t *testing.T) {
 			CaseName: "Create file in root - absolute path, work dir nil",
 			Path:     "/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				return memoryfs.NewMemoryFileSystem(), nil, nil
+				return newFileSystem(), nil, nil
 			},
 			Assertions: func(t *testing.T, res file.File, err error) {
 				assert.Nil(t, err)
@@ -356,7 +356,7 @@ func TestCreateRegularFile(t *testing.T) {
 			CaseName: "Create file in subdir - absolute path, work dir nil",
 			Path:     "/dir1/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -379,7 +379,7 @@ func TestCreateRegularFile(t *testing.T) {
 			CaseName: "Name conflict in root with directory - absolute path, work dir nil",
 			Path:     "/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -390,7 +390,7 @@ func TestCreateRegularFile(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrExist)
+				assert.True(t, errors.Is(err, fserrors.ErrExist))
 				assert.Nil(t, res)
 			},
 		},
@@ -398,7 +398,7 @@ func TestCreateRegularFile(t *testing.T) {
 			CaseName: "Name conflict in root with regular file - absolute path, work dir nil",
 			Path:     "/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -408,8 +408,8 @@ func TestCreateRegularFile(t *testing.T) {
 			Assertions: func(t *testing.T, res file.File, err error) {
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrExist)
-				assert.Equal(t, err.Error(), "file already exists")
+				assert.True(t, errors.Is(err, fserrors.ErrExist))
+				assert.Equal(t, err.Error(), "create /file1: file already exists")
 				assert.Nil(t, res)
 			},
 		},
@@ -417,7 +417,7 @@ func TestCreateRegularFile(t *testing.T) {
 			CaseName: "Name conflict in subdirectory - absolute path, work dir nil",
 			Path:     "/dir1/dir2/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -432,7 +432,7 @@ func TestCreateRegularFile(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrExist)
+				assert.True(t, errors.Is(err, fserrors.ErrExist))
 				assert.Nil(t, res)
 			},
 		},
@@ -440,7 +440,7 @@ func TestCreateRegularFile(t *testing.T) {
 			CaseName: "Parent directory does not exists - absolute path, work dir nil",
 			Path:     "/dir1/dir2/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -451,7 +451,7 @@ func TestCreateRegularFile(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 				assert.Nil(t, res)
 			},
 		},
@@ -459,7 +459,7 @@ func TestCreateRegularFile(t *testing.T) {
 			CaseName: "Create file following symlink - absolute path",
 			Path:     "/dir2/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p1)
 				if err != nil {
@@ -481,7 +481,7 @@ func TestCreateRegularFile(t *testing.T) {
 			CaseName: "Create file in root - relative path, work dir not nil",
 			Path:     "file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				return fs, fs.DefaultWorkingDirectory(), nil
 			},
 			Assertions: func(t *testing.T, res file.File, err error) {
@@ -496,7 +496,7 @@ func TestCreateRegularFile(t *testing.T) {
 			CaseName: "Create file in subdir - relative path, work dir nil",
 			Path:     "./../dir2/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -520,7 +520,7 @@ func TestCreateRegularFile(t *testing.T) {
 			CaseName: "Name conflict in subdirectory - relative path, work dir not nil",
 			Path:     "dir2/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -538,7 +538,7 @@ func TestCreateRegularFile(t *testing.T) {
 			},
 			Assertions: func(t *testing.T, res file.File, err error) {
 				assert.NotNil(t, err)
-				assert.Equal(t, err.Error(), "file already exists")
+				assert.Equal(t, err.Error(), "create dir2/file1: file already exists")
 				assert.Nil(t, res)
 			},
 		},
@@ -546,7 +546,7 @@ func TestCreateRegularFile(t *testing.T) {
 			CaseName: "Parent directory does not exists - relative path, work dir not nil",
 			Path:     "./dir2/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -558,7 +558,7 @@ func TestCreateRegularFile(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 				assert.Nil(t, res)
 			},
 		},
@@ -566,7 +566,7 @@ func TestCreateRegularFile(t *testing.T) {
 			CaseName: "Working directory previously deleted",
 			Path:     "./dir2/dir3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -590,7 +590,7 @@ func TestCreateRegularFile(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrInvalidWorkingDirectory)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalidWorkingDirectory))
 				assert.Nil(t, res)
 			},
 		},
@@ -598,7 +598,7 @@ func TestCreateRegularFile(t *testing.T) {
 			CaseName: "Create file following symlink - relative path",
 			Path:     "../dir2/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p1)
 				if err != nil {
@@ -640,7 +640,7 @@ func TestMkdirAll(t *testing.T) {
 			CaseName: "Create directories in root - absolute path, work dir nil",
 			Path:     "/dir1/dir2/dir3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				return memoryfs.NewMemoryFileSystem(), nil, nil
+				return newFileSystem(), nil, nil
 			},
 			Assertions: func(t *testing.T, res file.File, err error) {
 				assert.Nil(t, err)
@@ -654,7 +654,7 @@ func TestMkdirAll(t *testing.T) {
 			CaseName: "Parent is not a directory - absolute path, work dir nil",
 			Path:     "/file1/dir2/dir3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -665,7 +665,7 @@ func TestMkdirAll(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrInvalidFileType)
+				assert.True(t, errors.Is(err, fserrors.ErrNotDir))
 				assert.Nil(t, res)
 			},
 		},
@@ -673,7 +673,7 @@ func TestMkdirAll(t *testing.T) {
 			CaseName: "Create directories - relative path, work dir not nil",
 			Path:     "dir1/dir2/dir3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -693,7 +693,7 @@ func TestMkdirAll(t *testing.T) {
 			CaseName: "Parent is not a directory - relative path, work dir not nil",
 			Path:     "./file1/dir2/dir3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1/", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -704,7 +704,7 @@ func TestMkdirAll(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrInvalidFileType)
+				assert.True(t, errors.Is(err, fserrors.ErrNotDir))
 				assert.Nil(t, res)
 			},
 		},
@@ -712,7 +712,7 @@ func TestMkdirAll(t *testing.T) {
 			CaseName: "Working directory previously deleted",
 			Path:     "./dir2/dir3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -735,7 +735,7 @@ func TestMkdirAll(t *testing.T) {
 			Assertions: func(t *testing.T, res file.File, err error) {
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrInvalidWorkingDirectory)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalidWorkingDirectory))
 				assert.Nil(t, res)
 			},
 		},
@@ -764,7 +764,7 @@ func TestCreateHardLink(t *testing.T) {
 			SrcPath:  "/file1",
 			DestPath: "/file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -788,7 +788,7 @@ func TestCreateHardLink(t *testing.T) {
 			SrcPath:  "/file1",
 			DestPath: "/file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -813,7 +813,7 @@ func TestCreateHardLink(t *testing.T) {
 			SrcPath:  "/file1",
 			DestPath: "/file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -838,7 +838,7 @@ func TestCreateHardLink(t *testing.T) {
 			SrcPath:  "/dir1",
 			DestPath: "/dir2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -848,7 +848,7 @@ func TestCreateHardLink(t *testing.T) {
 			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, src *fspath.FileSystemPath, res file.FileInfo, err error) {
 				assert.NotNil(t, err)
 				assert.Nil(t, res)
-				assert.Equal(t, err, fserrors.ErrInvalidFileType)
+				assert.True(t, errors.Is(err, fserrors.ErrIsDir))
 			},
 		},
 		{
@@ -856,7 +856,7 @@ func TestCreateHardLink(t *testing.T) {
 			SrcPath:  "/file1",
 			DestPath: "/dir2/dir3/dir4/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -880,7 +880,7 @@ func TestCreateHardLink(t *testing.T) {
 			SrcPath:  "/file1",
 			DestPath: "/file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -894,7 +894,7 @@ func TestCreateHardLink(t *testing.T) {
 			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, src *fspath.FileSystemPath, res file.FileInfo, err error) {
 				assert.NotNil(t, err)
 				assert.Nil(t, res)
-				assert.Equal(t, err, fserrors.ErrExist)
+				assert.True(t, errors.Is(err, fserrors.ErrExist))
 			},
 		},
 		{
@@ -902,7 +902,7 @@ func TestCreateHardLink(t *testing.T) {
 			SrcPath:  "/file2",
 			DestPath: "/file3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p1); err != nil {
 					return nil, nil, err
@@ -932,7 +932,7 @@ func TestCreateHardLink(t *testing.T) {
 			SrcPath:  "/dir2",
 			DestPath: "/file3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p1); err != nil {
 					return nil, nil, err
@@ -946,7 +946,7 @@ func TestCreateHardLink(t *testing.T) {
 			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, src *fspath.FileSystemPath, res file.FileInfo, err error) {
 				assert.NotNil(t, err)
 				assert.Nil(t, res)
-				assert.Equal(t, err, fserrors.ErrInvalidFileType)
+				assert.True(t, errors.Is(err, fserrors.ErrIsDir))
 			},
 		},
 		{
@@ -954,7 +954,7 @@ func TestCreateHardLink(t *testing.T) {
 			SrcPath:  "file1",
 			DestPath: "file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -978,7 +978,7 @@ func TestCreateHardLink(t *testing.T) {
 			SrcPath:  "file1",
 			DestPath: "file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -1003,7 +1003,7 @@ func TestCreateHardLink(t *testing.T) {
 			SrcPath:  "dir1",
 			DestPath: "dir2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -1013,7 +1013,7 @@ func TestCreateHardLink(t *testing.T) {
 			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, src *fspath.FileSystemPath, res file.FileInfo, err error) {
 				assert.NotNil(t, err)
 				assert.Nil(t, res)
-				assert.Equal(t, err, fserrors.ErrInvalidFileType)
+				assert.True(t, errors.Is(err, fserrors.ErrIsDir))
 			},
 		},
 		{
@@ -1021,7 +1021,7 @@ func TestCreateHardLink(t *testing.T) {
 			SrcPath:  "file1",
 			DestPath: "./dir2/dir3/dir4/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -1045,7 +1045,7 @@ func TestCreateHardLink(t *testing.T) {
 			SrcPath:  "file1",
 			DestPath: "file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -1059,7 +1059,7 @@ func TestCreateHardLink(t *testing.T) {
 			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, src *fspath.FileSystemPath, res file.FileInfo, err error) {
 				assert.NotNil(t, err)
 				assert.Nil(t, res)
-				assert.Equal(t, err, fserrors.ErrExist)
+				assert.True(t, errors.Is(err, fserrors.ErrExist))
 			},
 		},
 		{
@@ -1067,7 +1067,7 @@ func TestCreateHardLink(t *testing.T) {
 			SrcPath:  "file2",
 			DestPath: "file3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p1); err != nil {
 					return nil, nil, err
@@ -1097,7 +1097,7 @@ func TestCreateHardLink(t *testing.T) {
 			SrcPath:  "dir2",
 			DestPath: "file3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p1); err != nil {
 					return nil, nil, err
@@ -1111,7 +1111,7 @@ func TestCreateHardLink(t *testing.T) {
 			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, src *fspath.FileSystemPath, res file.FileInfo, err error) {
 				assert.NotNil(t, err)
 				assert.Nil(t, res)
-				assert.Equal(t, err, fserrors.ErrInvalidFileType)
+				assert.True(t, errors.Is(err, fserrors.ErrIsDir))
 			},
 		},
 	}
@@ -1141,7 +1141,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 			SrcPath:  "/file1",
 			DestPath: "/file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
@@ -1165,7 +1165,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 			SrcPath:  "/dir1",
 			DestPath: "/dir-link",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -1201,7 +1201,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 			SrcPath:  "/dir1/file1",
 			DestPath: "/file3-link",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -1232,7 +1232,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 			SrcPath:  "/dir1/file1-link",
 			DestPath: "/file3-link",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
 				if _, err := fs.MkdirAll(p1); err != nil {
 					return nil, nil, err
@@ -1267,7 +1267,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 			SrcPath:  "/file1",
 			DestPath: "/file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				return fs, nil, nil
 			},
 			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, src *fspath.FileSystemPath, res file.FileInfo, workDir file.File, err error) {
@@ -1278,7 +1278,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 				p, _ := fspath.NewFileSystemPath(res.AbsolutePath(), nil)
 				_, err = fs.ListFiles(p)
 				assert.NotNil(t, err)
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 			},
 		},
 		{
@@ -1286,7 +1286,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 			SrcPath:  "/file1",
 			DestPath: "/file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -1301,7 +1301,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 				p, _ := fspath.NewFileSystemPath(res.AbsolutePath(), nil)
 				_, err = fs.GetDirectory(p)
 				assert.NotNil(t, err)
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 			},
 		},
 		{
@@ -1309,7 +1309,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 			SrcPath:  "/file1",
 			DestPath: "/file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -1326,7 +1326,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 				p, _ = fspath.NewFileSystemPath(res.AbsolutePath(), nil)
 				_, err = fs.GetDirectory(p)
 				assert.NotNil(t, err)
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 			},
 		},
 		{
@@ -1334,7 +1334,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 			SrcPath:  "file1",
 			DestPath: "file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -1357,7 +1357,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 			SrcPath:  "..",
 			DestPath: "../../dir-link",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
 				workDir, err := fs.MkdirAll(p)
 				if err != nil {
@@ -1395,7 +1395,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 			SrcPath:  "../dir1/file1",
 			DestPath: "file3-link",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
 				if _, err := fs.MkdirAll(p1); err != nil {
 					return nil, nil, err
@@ -1426,7 +1426,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 			SrcPath:  "./dir1/file1-link",
 			DestPath: "file3-link",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
 				if _, err := fs.MkdirAll(p1); err != nil {
 					return nil, nil, err
@@ -1461,7 +1461,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 			SrcPath:  "file1",
 			DestPath: "file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				return fs, fs.DefaultWorkingDirectory(), nil
 			},
 			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, src *fspath.FileSystemPath, res file.FileInfo, workDir file.File, err error) {
@@ -1472,7 +1472,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 				p, _ := fspath.NewFileSystemPath(res.AbsolutePath(), nil)
 				_, err = fs.ListFiles(p)
 				assert.NotNil(t, err)
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 			},
 		},
 		{
@@ -1480,7 +1480,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 			SrcPath:  "./file1",
 			DestPath: "file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -1495,7 +1495,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 				p, _ := fspath.NewFileSystemPath(res.AbsolutePath(), nil)
 				_, err = fs.GetDirectory(p)
 				assert.NotNil(t, err)
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 			},
 		},
 		{
@@ -1503,7 +1503,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 			SrcPath:  "file1",
 			DestPath: "file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -1519,7 +1519,7 @@ func TestCreateSymbolicLink(t *testing.T) {
 				p, _ = fspath.NewFileSystemPath(res.AbsolutePath(), nil)
 				_, err = fs.GetDirectory(p)
 				assert.NotNil(t, err)
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 			},
 		},
 	}
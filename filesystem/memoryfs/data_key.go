@@ -0,0 +1,155 @@
+package memoryfs
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"material/filesystem/filesystem/fscrypt"
+	"material/filesystem/filesystem/fserrors"
+)
+
+const dataKeySize = 32
+
+// dataKey encrypts the blocks of a file.
+// The key material is kept in memory only wrapped with a master key,
+// and the ciphers derived from it.
+type dataKey struct {
+	// id of the master key wrapping the data key
+	masterKeyID string
+	// key material encrypted with the master key
+	wrapped []byte
+	// cipher encrypting the blocks
+	aead cipher.AEAD
+	// key identifying the blocks without revealing their content
+	macKey []byte
+}
+
+// newDataKey generates a new data key wrapped with the current master key
+func newDataKey(keys fscrypt.KeyProvider) (*dataKey, error) {
+	if keys == nil {
+		return nil, fserrors.ErrNoKey
+	}
+
+	material := make([]byte, 2*dataKeySize)
+	if _, err := rand.Read(material); err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(material[:dataKeySize])
+	if err != nil {
+		return nil, err
+	}
+
+	key := &dataKey{
+		aead:   aead,
+		macKey: material[dataKeySize:],
+	}
+	key.masterKeyID, key.wrapped, err = wrap(keys, material)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// rewrap returns the key material wrapped with the current master key
+// and the id of the master key. The data key is not modified.
+func (k *dataKey) rewrap(keys fscrypt.KeyProvider) (string, []byte, error) {
+	if keys == nil {
+		return "", nil, fserrors.ErrNoKey
+	}
+
+	masterKey, err := keys.Key(k.masterKeyID)
+	if err != nil {
+		return "", nil, fserrors.ErrNoKey
+	}
+	material, err := openAESGCM(masterKey, k.wrapped, []byte(k.masterKeyID))
+	if err != nil {
+		return "", nil, err
+	}
+	return wrap(keys, material)
+}
+
+// wrap encrypts the key material with the current master key
+// and returns the id of the master key and the wrapped material
+func wrap(keys fscrypt.KeyProvider, material []byte) (string, []byte, error) {
+	masterKeyID, masterKey, err := keys.CurrentKey()
+	if err != nil {
+		return "", nil, fserrors.ErrNoKey
+	}
+
+	wrapped, err := sealAESGCM(masterKey, material, []byte(masterKeyID))
+	if err != nil {
+		return "", nil, err
+	}
+	return masterKeyID, wrapped, nil
+}
+
+// hash returns the keyed hash of a block content
+func (k *dataKey) hash(content []byte) blockHash {
+	mac := hmac.New(sha256.New, k.macKey)
+	mac.Write(content)
+	var hash blockHash
+	copy(hash[:], mac.Sum(nil))
+	return hash
+}
+
+// encrypt encrypts the content, prefixing it with a random nonce
+func (k *dataKey) encrypt(content []byte) ([]byte, error) {
+	nonce := make([]byte, k.aead.NonceSize(), k.aead.NonceSize()+len(content)+k.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return k.aead.Seal(nonce, nonce, content, nil), nil
+}
+
+// decrypt decrypts the data produced by encrypt
+func (k *dataKey) decrypt(data []byte) ([]byte, error) {
+	if len(data) < k.aead.NonceSize() {
+		return nil, fserrors.ErrCorruptedData
+	}
+	nonce, ciphertext := data[:k.aead.NonceSize()], data[k.aead.NonceSize():]
+	content, err := k.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fserrors.ErrCorruptedData
+	}
+	return content, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fserrors.ErrInvalid
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealAESGCM encrypts plaintext with key using AES-GCM
+func sealAESGCM(key []byte, plaintext []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// openAESGCM decrypts the data produced by sealAESGCM
+func openAESGCM(key []byte, data []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, fserrors.ErrCorruptedData
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], additionalData)
+	if err != nil {
+		return nil, fserrors.ErrCorruptedData
+	}
+	return plaintext, nil
+}
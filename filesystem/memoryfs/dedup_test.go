@@ -0,0 +1,167 @@
+package memoryfs_test
+
+import (
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/memoryfs"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func randomContent(seed int64, size int) []byte {
+	content := make([]byte, size)
+	rand.New(rand.NewSource(seed)).Read(content)
+	return content
+}
+
+func createFileWithContent(fs *memoryfs.MemoryFileSystem, path string, content []byte) error {
+	p, _ := fspath.NewFileSystemPath(path, nil)
+	if _, err := fs.CreateRegularFile(p); err != nil {
+		return err
+	}
+	return fs.AppendAll(p, content)
+}
+
+func TestDeduplication(t *testing.T) {
+	content := randomContent(1, 256<<10)
+	cases := []struct {
+		CaseName   string
+		Initialize func(fs *memoryfs.MemoryFileSystem) error
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem)
+	}{
+		{
+			CaseName: "Identical files are stored once",
+			Initialize: func(fs *memoryfs.MemoryFileSystem) error {
+				if err := createFileWithContent(fs, "/file1", content); err != nil {
+					return err
+				}
+				return createFileWithContent(fs, "/file2", content)
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				stats := fs.StatFS()
+				assert.Equal(t, len(content), stats.StoredBytes)
+				assert.Equal(t, 2*len(content), stats.ReferencedBytes)
+				assert.Equal(t, 2.0, stats.DedupRatio())
+			},
+		},
+		{
+			CaseName: "Copy shares the blocks and writes copy on write",
+			Initialize: func(fs *memoryfs.MemoryFileSystem) error {
+				if err := createFileWithContent(fs, "/file1", content); err != nil {
+					return err
+				}
+				src, _ := fspath.NewFileSystemPath("/file1", nil)
+				dest, _ := fspath.NewFileSystemPath("/file2", nil)
+				if _, err := fs.Copy(src, dest); err != nil {
+					return err
+				}
+				return fs.AppendAll(dest, []byte("Hello world!"))
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/file1", nil)
+				original, err := fs.ReadAll(p)
+				assert.Nil(t, err)
+				assert.Equal(t, content, original)
+
+				p, _ = fspath.NewFileSystemPath("/file2", nil)
+				copied, err := fs.ReadAll(p)
+				assert.Nil(t, err)
+				assert.Equal(t, append(append([]byte{}, content...), []byte("Hello world!")...), copied)
+
+				stats := fs.StatFS()
+				assert.Less(t, stats.StoredBytes, 2*len(content))
+				assert.Equal(t, 2*len(content)+len("Hello world!"), stats.ReferencedBytes)
+			},
+		},
+		{
+			CaseName: "Inserting in the middle of a file only adds the touched blocks",
+			Initialize: func(fs *memoryfs.MemoryFileSystem) error {
+				if err := createFileWithContent(fs, "/file1", content); err != nil {
+					return err
+				}
+				if err := createFileWithContent(fs, "/file2", content); err != nil {
+					return err
+				}
+				p, _ := fspath.NewFileSystemPath("/file2", nil)
+				fd, err := fs.Open(p)
+				if err != nil {
+					return err
+				}
+				defer fs.Close(fd)
+				_, err = fs.WriteAt(fd, []byte("Hello world!"), len(content)/2)
+				return err
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				expected := append([]byte{}, content[:len(content)/2]...)
+				expected = append(expected, []byte("Hello world!")...)
+				expected = append(expected, content[len(content)/2:]...)
+
+				p, _ := fspath.NewFileSystemPath("/file2", nil)
+				data, err := fs.ReadAll(p)
+				assert.Nil(t, err)
+				assert.Equal(t, expected, data)
+
+				stats := fs.StatFS()
+				assert.Less(t, stats.StoredBytes, len(content)+len(content)/2)
+				assert.Greater(t, stats.DedupRatio(), 1.3)
+			},
+		},
+		{
+			CaseName: "Blocks are released when files are removed",
+			Initialize: func(fs *memoryfs.MemoryFileSystem) error {
+				if err := createFileWithContent(fs, "/file1", content); err != nil {
+					return err
+				}
+				if err := createFileWithContent(fs, "/file2", content); err != nil {
+					return err
+				}
+				p, _ := fspath.NewFileSystemPath("/file1", nil)
+				if _, err := fs.Remove(p); err != nil {
+					return err
+				}
+				p, _ = fspath.NewFileSystemPath("/file2", nil)
+				_, err := fs.Remove(p)
+				return err
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				stats := fs.StatFS()
+				assert.Equal(t, 0, stats.Blocks)
+				assert.Equal(t, 0, stats.StoredBytes)
+				assert.Equal(t, 1.0, stats.DedupRatio())
+			},
+		},
+		{
+			CaseName: "Blocks of deleted files are released when the last descriptor is closed",
+			Initialize: func(fs *memoryfs.MemoryFileSystem) error {
+				if err := createFileWithContent(fs, "/file1", content); err != nil {
+					return err
+				}
+				p, _ := fspath.NewFileSystemPath("/file1", nil)
+				fd, err := fs.Open(p)
+				if err != nil {
+					return err
+				}
+				if _, err := fs.Remove(p); err != nil {
+					return err
+				}
+				if fs.StatFS().StoredBytes != len(content) {
+					t.Error("blocks released while the file is still open")
+				}
+				fs.Close(fd)
+				return nil
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				assert.Equal(t, 0, fs.StatFS().StoredBytes)
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		fs := newFileSystem()
+		if err := testCase.Initialize(fs); err != nil {
+			t.Fatal("error initializing file system")
+		}
+		testCase.Assertions(t, fs)
+	}
+}
@@ -0,0 +1,96 @@
+package memoryfs
+
+import (
+	"material/filesystem/filesystem/fspath"
+	"sort"
+)
+
+// nameKey returns the key of the file name in the directory entries.
+// Names are normalized as in the name policy.
+// In a case-insensitive file system, names that differ only in case have the same key.
+func (fs *MemoryFileSystem) nameKey(name string) string {
+	name = fs.namePolicy.Normalize(name)
+	if fs.caseInsensitive {
+		return fspath.FoldName(name)
+	}
+	return name
+}
+
+// pathKey returns the key of the absolute path in the path cache,
+// normalized as the names in the directory entries
+func (fs *MemoryFileSystem) pathKey(absolutePath string) string {
+	return fs.nameKey(absolutePath)
+}
+
+// lookup returns the entry of the directory with the given name
+func (fs *MemoryFileSystem) lookup(dir *inMemoryFile, name string) (*inMemoryFile, bool) {
+	dir.materialize()
+	child, found := dir.fileMap[fs.nameKey(name)]
+	return child, found
+}
+
+// entry returns the entry of the directory with the given name, nil if not found
+func (fs *MemoryFileSystem) entry(dir *inMemoryFile, name string) *inMemoryFile {
+	child, _ := fs.lookup(dir, name)
+	return child
+}
+
+// addChild adds the child to the directory entries with the given key,
+// and its name to the ordered index
+func (dir *inMemoryFile) addChild(key string, child *inMemoryFile) {
+	dir.materialize()
+	dir.insertChild(key, child)
+}
+
+// insertChild adds the child to the directory entries without copying
+// the entries of a snapshot directory first
+func (dir *inMemoryFile) insertChild(key string, child *inMemoryFile) {
+	dir.fileMap[key] = child
+
+	name := child.info.Name()
+	i := sort.SearchStrings(dir.names, name)
+	if i < len(dir.names) && dir.names[i] == name {
+		return
+	}
+	dir.names = append(dir.names, "")
+	copy(dir.names[i+1:], dir.names[i:])
+	dir.names[i] = name
+}
+
+// removeChild removes the child with the given key from the directory entries
+// and its name from the ordered index
+func (dir *inMemoryFile) removeChild(key string) {
+	dir.materialize()
+	child, found := dir.fileMap[key]
+	if !found {
+		return
+	}
+	delete(dir.fileMap, key)
+
+	name := child.info.Name()
+	i := sort.SearchStrings(dir.names, name)
+	if i < len(dir.names) && dir.names[i] == name {
+		dir.names = append(dir.names[:i], dir.names[i+1:]...)
+	}
+}
+
+// namesAfter returns at most limit entry names following cursor in lexical order.
+// An empty cursor starts from the first entry.
+func (dir *inMemoryFile) namesAfter(cursor string, limit int) []string {
+	dir.materialize()
+	start := 0
+	if cursor != "" {
+		start = sort.Search(len(dir.names), func(i int) bool { return dir.names[i] > cursor })
+	}
+	end := start + limit
+	if end > len(dir.names) {
+		end = len(dir.names)
+	}
+	return dir.names[start:end]
+}
+
+// childNames returns the entry names in lexical order, without the special ones
+func (dir *inMemoryFile) childNames() []string {
+	dir.materialize()
+	return dir.names
+}
@@ -0,0 +1,107 @@
+package memoryfs
+
+import (
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsstats"
+)
+
+// DiskUsage returns the space used by the directory located at path and by every
+// subdirectory, children before their parents. The files are visited as in WalkDir,
+// symbolic links are not followed and files with multiple hard links are counted once.
+// If path is not a directory, only the file usage is returned.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - path does not exist
+func (fs *MemoryFileSystem) DiskUsage(path *fspath.FileSystemPath) ([]*fsstats.DiskUsage, error) {
+	fs.RLock()
+	defer fs.RUnlock()
+
+	// Initialize result
+	usages := []*fsstats.DiskUsage{}
+
+	pathRoot, err := fs.traverseToBase(path)
+	if err != nil {
+		return usages, pathError("du", path, err)
+	}
+
+	// directories being visited, from the root to the current one
+	stack := []*fsstats.DiskUsage{}
+	seen := map[*inMemoryFileData]bool{}
+
+	fs.doWalkDir(pathRoot, path.AbsolutePath(), 0, map[*inMemoryFile]bool{}, func(currPath string, f *inMemoryFile, depth int) error {
+		// every directory deeper than the current file is complete
+		for len(stack) > depth {
+			usages = append(usages, stack[len(stack)-1])
+			stack = stack[:len(stack)-1]
+		}
+
+		usage := &fsstats.DiskUsage{
+			AbsolutePath: currPath,
+			Depth:        depth,
+		}
+		if !seen[f.data] {
+			seen[f.data] = true
+			size, allocated := f.data.usage()
+			usage.Inodes = 1
+			usage.ApparentSize = size
+			usage.AllocatedBytes = allocated
+		}
+
+		for _, dir := range stack {
+			dir.Inodes += usage.Inodes
+			dir.ApparentSize += usage.ApparentSize
+			dir.AllocatedBytes += usage.AllocatedBytes
+		}
+
+		if f.data.fileType == file.Directory || depth == 0 {
+			stack = append(stack, usage)
+		}
+		return nil
+	})
+
+	for len(stack) > 0 {
+		usages = append(usages, stack[len(stack)-1])
+		stack = stack[:len(stack)-1]
+	}
+	return usages, nil
+}
+
+// StatFS returns the usage of the whole file system.
+// This implementation is thread safe.
+func (fs *MemoryFileSystem) StatFS() *fsstats.FileSystemStats {
+	fs.RLock()
+	defer fs.RUnlock()
+
+	stats := &fsstats.FileSystemStats{}
+	seen := map[*inMemoryFileData]bool{}
+	fs.doWalkDir(fs.root, "/", 0, map[*inMemoryFile]bool{}, func(_ string, f *inMemoryFile, _ int) error {
+		if seen[f.data] {
+			return nil
+		}
+		seen[f.data] = true
+		size, allocated := f.data.usage()
+		stats.Inodes++
+		stats.ApparentSize += size
+		stats.AllocatedBytes += allocated
+		return nil
+	})
+
+	fs.openFiles.RLock()
+	defer fs.openFiles.RUnlock()
+
+	stats.OpenDescriptors = len(fs.openFiles.table)
+	for data := range fs.openFiles.orphans {
+		_, allocated := data.usage()
+		stats.OrphanedFiles++
+		stats.OrphanedBytes += allocated
+	}
+
+	blockStats := fs.blocks.stats()
+	stats.Blocks = blockStats.blocks
+	stats.StoredBytes = blockStats.storedBytes
+	stats.ReferencedBytes = blockStats.referencedBytes
+	stats.CachedPaths = fs.pathCache.len()
+	return stats
+}
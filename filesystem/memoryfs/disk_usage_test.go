@@ -0,0 +1,164 @@
+package memoryfs_test
+
+import (
+	"errors"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsstats"
+	"material/filesystem/filesystem/memoryfs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func initializeDiskUsageTree() (*memoryfs.MemoryFileSystem, file.File, error) {
+	fs := newFileSystem()
+	p, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
+	if _, err := fs.MkdirAll(p); err != nil {
+		return nil, nil, err
+	}
+	files := map[string]string{
+		"/file":           "12345",
+		"/dir1/file":      "1234567890",
+		"/dir1/dir2/file": "123",
+	}
+	for name, content := range files {
+		p, _ := fspath.NewFileSystemPath(name, nil)
+		if err := fs.AppendAll(p, []byte(content)); err != nil {
+			return nil, nil, err
+		}
+	}
+	// hard links are counted once
+	p1, _ := fspath.NewFileSystemPath("/dir1/file", nil)
+	p2, _ := fspath.NewFileSystemPath("/dir1/dir2/link", nil)
+	if _, err := fs.CreateHardLink(p1, p2); err != nil {
+		return nil, nil, err
+	}
+	return fs, nil, nil
+}
+
+func TestDiskUsage(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Path       string
+		Initialize func() (*memoryfs.MemoryFileSystem, file.File, error)
+		Assertions func(t *testing.T, usages []*fsstats.DiskUsage, err error)
+	}{
+		{
+			CaseName:   "Disk usage from root",
+			Path:       "/",
+			Initialize: initializeDiskUsageTree,
+			Assertions: func(t *testing.T, usages []*fsstats.DiskUsage, err error) {
+				assert.Nil(t, err)
+				assert.Len(t, usages, 3)
+
+				assert.Equal(t, "/dir1/dir2", usages[0].AbsolutePath)
+				assert.Equal(t, 2, usages[0].Depth)
+				// the hard link is visited first
+				assert.Equal(t, 13, usages[0].ApparentSize)
+				assert.Equal(t, 3, usages[0].Inodes)
+
+				assert.Equal(t, "/dir1", usages[1].AbsolutePath)
+				assert.Equal(t, 1, usages[1].Depth)
+				assert.Equal(t, 13, usages[1].ApparentSize)
+				assert.Equal(t, 4, usages[1].Inodes)
+
+				assert.Equal(t, "/", usages[2].AbsolutePath)
+				assert.Equal(t, 0, usages[2].Depth)
+				assert.Equal(t, 18, usages[2].ApparentSize)
+				assert.Equal(t, 6, usages[2].Inodes)
+				assert.GreaterOrEqual(t, usages[2].AllocatedBytes, usages[2].ApparentSize)
+			},
+		},
+		{
+			CaseName: "Disk usage using relative path",
+			Path:     "dir2",
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs, _, err := initializeDiskUsageTree()
+				if err != nil {
+					return nil, nil, err
+				}
+				p, _ := fspath.NewFileSystemPath("/dir1", nil)
+				workingDir, err := fs.GetDirectory(p)
+				return fs, workingDir, err
+			},
+			Assertions: func(t *testing.T, usages []*fsstats.DiskUsage, err error) {
+				assert.Nil(t, err)
+				assert.Len(t, usages, 1)
+				assert.Equal(t, "/dir1/dir2", usages[0].AbsolutePath)
+				assert.Equal(t, 13, usages[0].ApparentSize)
+				assert.Equal(t, 3, usages[0].Inodes)
+			},
+		},
+		{
+			CaseName:   "Disk usage of a regular file",
+			Path:       "/file",
+			Initialize: initializeDiskUsageTree,
+			Assertions: func(t *testing.T, usages []*fsstats.DiskUsage, err error) {
+				assert.Nil(t, err)
+				assert.Len(t, usages, 1)
+				assert.Equal(t, "/file", usages[0].AbsolutePath)
+				assert.Equal(t, 5, usages[0].ApparentSize)
+				assert.Equal(t, 1, usages[0].Inodes)
+			},
+		},
+		{
+			CaseName:   "Path does not exist",
+			Path:       "/invalid",
+			Initialize: initializeDiskUsageTree,
+			Assertions: func(t *testing.T, usages []*fsstats.DiskUsage, err error) {
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+				assert.Empty(t, usages)
+			},
+		},
+	}
+	for _, testCase := range cases {
+		fs, workingDir, err := testCase.Initialize()
+		if err != nil {
+			t.Fatal("error initializing file system")
+		}
+		p, _ := fspath.NewFileSystemPath(testCase.Path, workingDir)
+		usages, err := fs.DiskUsage(p)
+		testCase.Assertions(t, usages, err)
+	}
+}
+
+func TestStatFS(t *testing.T) {
+	fs, _, err := initializeDiskUsageTree()
+	if err != nil {
+		t.Fatal("error initializing file system")
+	}
+
+	stats := fs.StatFS()
+	assert.Equal(t, 6, stats.Inodes)
+	assert.Equal(t, 18, stats.ApparentSize)
+	assert.Equal(t, 0, stats.OpenDescriptors)
+	assert.Equal(t, 0, stats.OrphanedFiles)
+
+	// open and remove a file
+	p, _ := fspath.NewFileSystemPath("/file", nil)
+	fd, err := fs.Open(p)
+	assert.Nil(t, err)
+	_, err = fs.Remove(p)
+	assert.Nil(t, err)
+	// remove one of the hard links of an open file
+	p, _ = fspath.NewFileSystemPath("/dir1/file", nil)
+	_, err = fs.Open(p)
+	assert.Nil(t, err)
+	_, err = fs.Remove(p)
+	assert.Nil(t, err)
+
+	stats = fs.StatFS()
+	assert.Equal(t, 5, stats.Inodes)
+	assert.Equal(t, 13, stats.ApparentSize)
+	assert.Equal(t, 2, stats.OpenDescriptors)
+	assert.Equal(t, 1, stats.OrphanedFiles)
+	assert.GreaterOrEqual(t, stats.OrphanedBytes, 5)
+
+	fs.Close(fd)
+	stats = fs.StatFS()
+	assert.Equal(t, 1, stats.OpenDescriptors)
+	assert.Equal(t, 0, stats.OrphanedFiles)
+	assert.Equal(t, 0, stats.OrphanedBytes)
+}
@@ -0,0 +1,231 @@
+package memoryfs
+
+import (
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fscrypt"
+	"material/filesystem/filesystem/fspath"
+)
+
+// SetKeyProvider sets the provider of the master keys used to encrypt the file data keys.
+// This implementation is thread safe.
+func (fs *MemoryFileSystem) SetKeyProvider(keys fscrypt.KeyProvider) {
+	fs.Lock()
+	defer fs.Unlock()
+	fs.keys = keys
+}
+
+// SetEncryption enables or disables the encryption of the named file content.
+// If the file is a directory, the encryption is set on every file in the directory tree,
+// and files created in it afterwards inherit it.
+// Every encrypted file has its own data key, encrypted with the current master key.
+// Symbolic links in the tree are not followed.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the file does not exist
+// - the key provider is not set or can't provide the current master key
+func (fs *MemoryFileSystem) SetEncryption(path *fspath.FileSystemPath, enabled bool) error {
+	fs.Lock()
+	defer fs.Unlock()
+
+	fileToEncrypt, err := fs.traverseToBase(path)
+	if err != nil {
+		return pathError("encrypt", path, err)
+	}
+
+	err = fs.doWalkDir(fileToEncrypt, fileToEncrypt.info.AbsolutePath(), 0, map[*inMemoryFile]bool{}, func(_ string, f *inMemoryFile, _ int) error {
+		return f.data.setEncryption(fs.keys, enabled, f.data.fileType == file.RegularFile)
+	})
+	return pathError("encrypt", path, err)
+}
+
+// RotateMasterKey encrypts every data key with the current master key,
+// including the keys of the trashed files, of the file versions and of
+// the deleted files still open.
+// File contents are not encrypted again.
+// Either every data key is encrypted with the current master key or,
+// on error, none is. The error records the path of a file using the key
+// that can't be encrypted, or its descriptor if the file is deleted.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the key provider is not set
+// - a master key used by the data keys is not available anymore
+func (fs *MemoryFileSystem) RotateMasterKey() error {
+	fs.Lock()
+	defer fs.Unlock()
+
+	// data keys by the path of a file using them
+	keys := map[*dataKey]string{}
+	collectFn := func(path string, f *inMemoryFile, _ int) error {
+		f.data.collectDataKeys(keys, path)
+		return nil
+	}
+	fs.doWalkDir(fs.root, "/", 0, map[*inMemoryFile]bool{}, collectFn)
+	for _, entries := range fs.trash {
+		for _, entry := range entries {
+			fs.doWalkDir(entry.file, entry.OriginalPath, 0, map[*inMemoryFile]bool{}, collectFn)
+		}
+	}
+
+	// deleted files still open
+	fs.openFiles.RLock()
+	for descriptor, fd := range fs.openFiles.table {
+		fd.data.collectDataKeys(keys, descriptor)
+	}
+	fs.openFiles.RUnlock()
+
+	type wrappedKey struct {
+		masterKeyID string
+		wrapped     []byte
+	}
+	rewrapped := make(map[*dataKey]wrappedKey, len(keys))
+	for key, path := range keys {
+		masterKeyID, wrapped, err := key.rewrap(fs.keys)
+		if err != nil {
+			return nameError("rotate", path, err)
+		}
+		rewrapped[key] = wrappedKey{masterKeyID: masterKeyID, wrapped: wrapped}
+	}
+
+	for key, w := range rewrapped {
+		key.masterKeyID, key.wrapped = w.masterKeyID, w.wrapped
+	}
+	return nil
+}
+
+// RotateDataKeys generates a new data key for the named encrypted file,
+// or for every encrypted file in the tree if the file is a directory,
+// and encrypts the content again.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the file does not exist
+// - the key provider is not set or can't provide the current master key
+func (fs *MemoryFileSystem) RotateDataKeys(path *fspath.FileSystemPath) error {
+	fs.Lock()
+	defer fs.Unlock()
+
+	fileToRotate, err := fs.traverseToBase(path)
+	if err != nil {
+		return pathError("rotatekeys", path, err)
+	}
+
+	seen := map[*inMemoryFileData]bool{}
+	err = fs.doWalkDir(fileToRotate, fileToRotate.info.AbsolutePath(), 0, map[*inMemoryFile]bool{}, func(_ string, f *inMemoryFile, _ int) error {
+		if seen[f.data] {
+			return nil
+		}
+		seen[f.data] = true
+		return f.data.rotateDataKey(fs.keys)
+	})
+	return pathError("rotatekeys", path, err)
+}
+
+// EncryptionInfo returns how the named file content is encrypted.
+// If the file is a symbolic link, the link is resolved.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the file does not exist
+func (fs *MemoryFileSystem) EncryptionInfo(path *fspath.FileSystemPath) (*fscrypt.EncryptionInfo, error) {
+	fs.RLock()
+	defer fs.RUnlock()
+
+	f, err := fs.traverseToBase(path)
+	if err != nil {
+		return nil, pathError("encryptioninfo", path, err)
+	}
+
+	f.data.RLock()
+	defer f.data.RUnlock()
+	info := &fscrypt.EncryptionInfo{Encrypted: f.data.encrypted}
+	if key := f.data.encoding.dataKey; key != nil {
+		info.MasterKeyID = key.masterKeyID
+	}
+	return info, nil
+}
+
+// inheritEncoding sets the encoding of a new file from its parent directory
+func (fs *MemoryFileSystem) inheritEncoding(newFile *inMemoryFile, parent *inMemoryFile) error {
+	newFile.data.encoding.compression = parent.data.encoding.compression
+	newFile.data.encrypted = parent.data.encrypted
+	if !parent.data.encrypted || newFile.data.fileType != file.RegularFile {
+		return nil
+	}
+
+	key, err := newDataKey(fs.keys)
+	if err != nil {
+		return err
+	}
+	newFile.data.encoding.dataKey = key
+	return nil
+}
+
+// collectDataKeys adds to keys the data key of the file and the keys
+// encrypting the blocks of its content and of its versions.
+// The keys already collected keep their path.
+// This implementation is thread safe.
+func (d *inMemoryFileData) collectDataKeys(keys map[*dataKey]string, path string) {
+	d.RLock()
+	defer d.RUnlock()
+
+	add := func(key *dataKey) {
+		if _, found := keys[key]; key != nil && !found {
+			keys[key] = path
+		}
+	}
+	add(d.encoding.dataKey)
+	lists := []*blockList{d.content}
+	for _, v := range d.versions {
+		lists = append(lists, v.content)
+	}
+	for _, l := range lists {
+		for _, b := range l.blocks {
+			add(b.dataKey)
+		}
+	}
+}
+
+// setEncryption enables or disables the encryption.
+// Only the content of regular files is encrypted.
+// This implementation is thread safe.
+func (d *inMemoryFileData) setEncryption(keys fscrypt.KeyProvider, enabled bool, isRegularFile bool) error {
+	d.Lock()
+	defer d.Unlock()
+
+	encoding := blockEncoding{compression: d.encoding.compression}
+	if enabled && isRegularFile {
+		encoding.dataKey = d.encoding.dataKey
+		if encoding.dataKey == nil {
+			key, err := newDataKey(keys)
+			if err != nil {
+				return err
+			}
+			encoding.dataKey = key
+		}
+	}
+
+	if err := d.setEncoding(encoding); err != nil {
+		return err
+	}
+	d.encrypted = enabled
+	return nil
+}
+
+// rotateDataKey encrypts the content with a new data key.
+// This is a noop if the content is not encrypted.
+// This implementation is thread safe.
+func (d *inMemoryFileData) rotateDataKey(keys fscrypt.KeyProvider) error {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.encoding.dataKey == nil {
+		return nil
+	}
+	key, err := newDataKey(keys)
+	if err != nil {
+		return err
+	}
+	return d.setEncoding(blockEncoding{compression: d.encoding.compression, dataKey: key})
+}
@@ -0,0 +1,329 @@
+package memoryfs_test
+
+import (
+	"bytes"
+	"errors"
+	"material/filesystem/filesystem/fscrypt"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/memoryfs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var secretContent = bytes.Repeat([]byte("password=secret\n"), 1000)
+
+func newTestKeyProvider(currentID string, ids ...string) fscrypt.KeyProvider {
+	keys := map[string][]byte{}
+	for i, id := range ids {
+		keys[id] = bytes.Repeat([]byte{byte(i + 1)}, 32)
+	}
+	return fscrypt.NewStaticKeyProvider(currentID, keys)
+}
+
+func initializeEncryptionTree() (*memoryfs.MemoryFileSystem, error) {
+	fs := newFileSystem()
+	fs.SetKeyProvider(newTestKeyProvider("key1", "key1"))
+	p, _ := fspath.NewFileSystemPath("/secrets", nil)
+	if _, err := fs.Mkdir(p); err != nil {
+		return nil, err
+	}
+	for _, name := range []string{"/secrets/file1", "/secrets/file2", "/public"} {
+		if err := createFileWithContent(fs, name, secretContent); err != nil {
+			return nil, err
+		}
+	}
+	p, _ = fspath.NewFileSystemPath("/secrets", nil)
+	return fs, fs.SetEncryption(p, true)
+}
+
+func assertContent(t *testing.T, fs *memoryfs.MemoryFileSystem, path string, expected []byte) {
+	p, _ := fspath.NewFileSystemPath(path, nil)
+	data, err := fs.ReadAll(p)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, data)
+}
+
+func assertEncryption(t *testing.T, fs *memoryfs.MemoryFileSystem, path string, encrypted bool, masterKeyID string) {
+	p, _ := fspath.NewFileSystemPath(path, nil)
+	info, err := fs.EncryptionInfo(p)
+	assert.Nil(t, err)
+	assert.Equal(t, encrypted, info.Encrypted)
+	assert.Equal(t, masterKeyID, info.MasterKeyID)
+}
+
+func TestEncryption(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem)
+	}{
+		{
+			CaseName: "Encrypt directory tree",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				assertEncryption(t, fs, "/secrets", true, "")
+				assertEncryption(t, fs, "/secrets/file1", true, "key1")
+				assertEncryption(t, fs, "/public", false, "")
+				assertContent(t, fs, "/secrets/file1", secretContent)
+
+				p, _ := fspath.NewFileSystemPath("/secrets/file1", nil)
+				stat, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.Equal(t, len(secretContent), stat.Size())
+				assert.Greater(t, stat.PhysicalSize(), stat.Size())
+			},
+		},
+		{
+			CaseName: "Encrypted files are not deduplicated",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				stats := fs.StatFS()
+				assert.Greater(t, stats.StoredBytes, 3*len(secretContent))
+				assert.Equal(t, 1.0, stats.DedupRatio())
+			},
+		},
+		{
+			CaseName: "Write and read encrypted file",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/secrets/file1", nil)
+				fd, err := fs.Open(p)
+				assert.Nil(t, err)
+				_, err = fs.WriteAt(fd, []byte("Hello world!"), 100)
+				assert.Nil(t, err)
+				buff := make([]byte, 12)
+				_, err = fs.ReadAt(fd, buff, 100)
+				assert.Nil(t, err)
+				assert.Equal(t, []byte("Hello world!"), buff)
+				fs.Close(fd)
+			},
+		},
+		{
+			CaseName: "New files inherit the directory encryption",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				assert.Nil(t, createFileWithContent(fs, "/secrets/file3", secretContent))
+				assertEncryption(t, fs, "/secrets/file3", true, "key1")
+				assertContent(t, fs, "/secrets/file3", secretContent)
+
+				src, _ := fspath.NewFileSystemPath("/secrets/file3", nil)
+				dest, _ := fspath.NewFileSystemPath("/copy", nil)
+				_, err := fs.Copy(src, dest)
+				assert.Nil(t, err)
+				assertEncryption(t, fs, "/copy", false, "")
+				assertContent(t, fs, "/copy", secretContent)
+			},
+		},
+		{
+			CaseName: "Copies of encrypted files have their own data key",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				src, _ := fspath.NewFileSystemPath("/secrets/file1", nil)
+				dest, _ := fspath.NewFileSystemPath("/secrets/copy", nil)
+				_, err := fs.Copy(src, dest)
+				assert.Nil(t, err)
+				assertContent(t, fs, "/secrets/copy", secretContent)
+
+				// the copy blocks are encrypted with another key
+				stats := fs.StatFS()
+				assert.Greater(t, stats.StoredBytes, 4*len(secretContent))
+				assert.Equal(t, 1.0, stats.DedupRatio())
+
+				fs.SetKeyProvider(newTestKeyProvider("key2", "key1", "key2"))
+				assert.Nil(t, fs.RotateDataKeys(src))
+				assertEncryption(t, fs, "/secrets/file1", true, "key2")
+				assertEncryption(t, fs, "/secrets/copy", true, "key1")
+				assertContent(t, fs, "/secrets/copy", secretContent)
+			},
+		},
+		{
+			CaseName: "Copy into an encrypted directory",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				src, _ := fspath.NewFileSystemPath("/public", nil)
+				dest, _ := fspath.NewFileSystemPath("/secrets/public", nil)
+				_, err := fs.Copy(src, dest)
+				assert.Nil(t, err)
+				assertEncryption(t, fs, "/secrets/public", true, "key1")
+				assertEncryption(t, fs, "/public", false, "")
+				assertContent(t, fs, "/secrets/public", secretContent)
+
+				p, _ := fspath.NewFileSystemPath("/secrets/public", nil)
+				stat, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.Greater(t, stat.PhysicalSize(), stat.Size())
+			},
+		},
+		{
+			CaseName: "Move into an encrypted directory",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/dir", nil)
+				_, err := fs.Mkdir(p)
+				assert.Nil(t, err)
+				src, _ := fspath.NewFileSystemPath("/public", nil)
+				dest, _ := fspath.NewFileSystemPath("/dir/public", nil)
+				_, err = fs.Move(src, dest)
+				assert.Nil(t, err)
+
+				src, _ = fspath.NewFileSystemPath("/dir", nil)
+				dest, _ = fspath.NewFileSystemPath("/secrets/dir", nil)
+				_, err = fs.Move(src, dest)
+				assert.Nil(t, err)
+				assertEncryption(t, fs, "/secrets/dir", true, "")
+				assertEncryption(t, fs, "/secrets/dir/public", true, "key1")
+				assertContent(t, fs, "/secrets/dir/public", secretContent)
+
+				// moved out, the file is decrypted
+				src, _ = fspath.NewFileSystemPath("/secrets/file1", nil)
+				dest, _ = fspath.NewFileSystemPath("/file1", nil)
+				_, err = fs.Move(src, dest)
+				assert.Nil(t, err)
+				assertEncryption(t, fs, "/file1", false, "")
+				assertContent(t, fs, "/file1", secretContent)
+			},
+		},
+		{
+			CaseName: "Copy and move into an encrypted directory without key provider",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				fs.SetKeyProvider(nil)
+				src, _ := fspath.NewFileSystemPath("/public", nil)
+				dest, _ := fspath.NewFileSystemPath("/secrets/public", nil)
+				_, err := fs.Copy(src, dest)
+				assert.True(t, errors.Is(err, fserrors.ErrNoKey))
+				_, err = fs.Move(src, dest)
+				assert.True(t, errors.Is(err, fserrors.ErrNoKey))
+
+				// the file is kept in place
+				assertEncryption(t, fs, "/public", false, "")
+				assertContent(t, fs, "/public", secretContent)
+				_, err = fs.Stat(dest)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+			},
+		},
+		{
+			CaseName: "Disable encryption",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/secrets", nil)
+				assert.Nil(t, fs.SetEncryption(p, false))
+				assertEncryption(t, fs, "/secrets/file1", false, "")
+				assertContent(t, fs, "/secrets/file1", secretContent)
+
+				p, _ = fspath.NewFileSystemPath("/secrets/file1", nil)
+				stat, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.Equal(t, stat.Size(), stat.PhysicalSize())
+			},
+		},
+		{
+			CaseName: "Rotate master key",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				fs.SetKeyProvider(newTestKeyProvider("key2", "key1", "key2"))
+				assert.Nil(t, fs.RotateMasterKey())
+				assertEncryption(t, fs, "/secrets/file1", true, "key2")
+				assertContent(t, fs, "/secrets/file1", secretContent)
+
+				// key2 is required to unwrap the data keys
+				fs.SetKeyProvider(newTestKeyProvider("key3", "key1", "key3"))
+				err := fs.RotateMasterKey()
+				assert.NotNil(t, err)
+				assert.True(t, errors.Is(err, fserrors.ErrNoKey))
+			},
+		},
+		{
+			CaseName: "Rotate master key of trashed files",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/secrets/file1", nil)
+				entry, err := fs.Trash(p, "alice", false)
+				assert.Nil(t, err)
+
+				fs.SetKeyProvider(newTestKeyProvider("key2", "key1", "key2"))
+				assert.Nil(t, fs.RotateMasterKey())
+
+				// key1 is retired
+				fs.SetKeyProvider(fscrypt.NewStaticKeyProvider("key2", map[string][]byte{"key2": bytes.Repeat([]byte{2}, 32)}))
+				_, err = fs.Restore("alice", entry.ID, nil)
+				assert.Nil(t, err)
+				assertEncryption(t, fs, "/secrets/file1", true, "key2")
+				assertContent(t, fs, "/secrets/file1", secretContent)
+				assert.Nil(t, fs.RotateMasterKey())
+			},
+		},
+		{
+			CaseName: "Rotate master key fails without changing any key",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				fs.SetKeyProvider(newTestKeyProvider("key2", "key1", "key2"))
+				p, _ := fspath.NewFileSystemPath("/secrets/file2", nil)
+				assert.Nil(t, fs.RotateDataKeys(p))
+
+				// key1 is required to unwrap the data key of file1
+				fs.SetKeyProvider(fscrypt.NewStaticKeyProvider("key3", map[string][]byte{
+					"key2": bytes.Repeat([]byte{2}, 32),
+					"key3": bytes.Repeat([]byte{3}, 32),
+				}))
+				err := fs.RotateMasterKey()
+				assert.NotNil(t, err)
+				assert.True(t, errors.Is(err, fserrors.ErrNoKey))
+				var pathErr *fserrors.PathError
+				assert.True(t, errors.As(err, &pathErr))
+				assert.Equal(t, "/secrets/file1", pathErr.Path)
+				assertEncryption(t, fs, "/secrets/file1", true, "key1")
+				assertEncryption(t, fs, "/secrets/file2", true, "key2")
+			},
+		},
+		{
+			CaseName: "Rotate data keys",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				fs.SetKeyProvider(newTestKeyProvider("key2", "key1", "key2"))
+				p, _ := fspath.NewFileSystemPath("/secrets", nil)
+				assert.Nil(t, fs.RotateDataKeys(p))
+				assertEncryption(t, fs, "/secrets/file2", true, "key2")
+				assertContent(t, fs, "/secrets/file2", secretContent)
+			},
+		},
+		{
+			CaseName: "Encryption without key provider",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				fs.SetKeyProvider(nil)
+				p, _ := fspath.NewFileSystemPath("/public", nil)
+				err := fs.SetEncryption(p, true)
+				assert.NotNil(t, err)
+				assert.True(t, errors.Is(err, fserrors.ErrNoKey))
+
+				p, _ = fspath.NewFileSystemPath("/secrets/file3", nil)
+				_, err = fs.CreateRegularFile(p)
+				assert.NotNil(t, err)
+				assert.True(t, errors.Is(err, fserrors.ErrNoKey))
+			},
+		},
+		{
+			CaseName: "Hard link into an encrypted directory without key provider",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				fs.SetKeyProvider(nil)
+				src, _ := fspath.NewFileSystemPath("/public", nil)
+				dest, _ := fspath.NewFileSystemPath("/secrets/public", nil)
+				_, err := fs.CreateHardLink(src, dest)
+				assert.Nil(t, err)
+
+				// the link shares the file inode and encoding
+				assertEncryption(t, fs, "/secrets/public", false, "")
+				assertContent(t, fs, "/secrets/public", secretContent)
+				stat, err := fs.Stat(dest)
+				assert.Nil(t, err)
+				assert.Equal(t, 2, stat.Links())
+			},
+		},
+		{
+			CaseName: "Invalid master key",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				fs.SetKeyProvider(fscrypt.NewStaticKeyProvider("short", map[string][]byte{"short": []byte("short")}))
+				p, _ := fspath.NewFileSystemPath("/public", nil)
+				err := fs.SetEncryption(p, true)
+				assert.NotNil(t, err)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalid))
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		fs, err := initializeEncryptionTree()
+		if err != nil {
+			t.Fatal("error initializing file system")
+		}
+		testCase.Assertions(t, fs)
+	}
+}
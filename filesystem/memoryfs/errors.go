@@ -0,0 +1,69 @@
+package memoryfs
+
+import (
+	"errors"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+)
+
+// pathError records the operation and the path that caused err.
+// Errors already recording a path are returned unchanged.
+func pathError(op string, path *fspath.FileSystemPath, err error) error {
+	if err == nil || hasPath(err) {
+		return err
+	}
+	return &fserrors.PathError{Op: op, Path: path.String(), Err: err}
+}
+
+// nameError records the operation and the descriptor or trash entry id that caused err
+func nameError(op string, name string, err error) error {
+	if err == nil || hasPath(err) {
+		return err
+	}
+	return &fserrors.PathError{Op: op, Path: name, Err: err}
+}
+
+// linkError records the operation and the paths that caused err.
+// Errors already recording a path are returned unchanged.
+func linkError(op string, oldPath *fspath.FileSystemPath, newPath *fspath.FileSystemPath, err error) error {
+	if err == nil || hasPath(err) {
+		return err
+	}
+	return &fserrors.LinkError{Op: op, Old: oldPath.String(), New: newPath.String(), Err: err}
+}
+
+// conflictError replaces the cause of err, recording the operation and the paths
+// of an operation that failed on commit, with ErrConflict
+func conflictError(err error) error {
+	var pathErr *fserrors.PathError
+	var linkErr *fserrors.LinkError
+	switch {
+	case errors.As(err, &pathErr):
+		return &fserrors.PathError{Op: pathErr.Op, Path: pathErr.Path, Err: fserrors.ErrConflict}
+	case errors.As(err, &linkErr):
+		return &fserrors.LinkError{Op: linkErr.Op, Old: linkErr.Old, New: linkErr.New, Err: fserrors.ErrConflict}
+	default:
+		return fserrors.ErrConflict
+	}
+}
+
+// hasPath returns true if err already records the paths that caused it
+func hasPath(err error) bool {
+	var pathErr *fserrors.PathError
+	var linkErr *fserrors.LinkError
+	return errors.As(err, &pathErr) || errors.As(err, &linkErr)
+}
+
+// checkRegularFile returns ErrIsDir if f is a directory
+// and ErrInvalid if f is not a regular file
+func checkRegularFile(f *inMemoryFile) error {
+	switch f.data.fileType {
+	case file.RegularFile:
+		return nil
+	case file.Directory:
+		return fserrors.ErrIsDir
+	default:
+		return fserrors.ErrInvalid
+	}
+}
@@ -0,0 +1,138 @@
+package memoryfs_test
+
+import (
+	"errors"
+	iofs "io/fs"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/memoryfs"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem)
+	}{
+		{
+			CaseName: "Path errors record the operation and the path",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/missing/file", nil)
+				_, err := fs.CreateRegularFile(p)
+
+				pathErr := &fserrors.PathError{}
+				assert.True(t, errors.As(err, &pathErr))
+				assert.Equal(t, "create", pathErr.Op)
+				assert.Equal(t, "/missing/file", pathErr.Path)
+				assert.Equal(t, "create /missing/file: file does not exist", err.Error())
+
+				target := &fserrors.FileSystemError{}
+				assert.True(t, errors.As(err, &target))
+				assert.Equal(t, fserrors.ErrNotExist, target)
+			},
+		},
+		{
+			CaseName: "Two-path operations record the path that caused the error",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				src, _ := fspath.NewFileSystemPath("/missing", nil)
+				dest, _ := fspath.NewFileSystemPath("/dir/copy", nil)
+				_, err := fs.Copy(src, dest)
+				assert.Equal(t, "copy /missing: file does not exist", err.Error())
+
+				src, _ = fspath.NewFileSystemPath("/dir", nil)
+				dest, _ = fspath.NewFileSystemPath("/dir/file/dir", nil)
+				_, err = fs.Move(src, dest)
+				assert.Equal(t, "move /dir/file/dir: not a directory", err.Error())
+
+				_, err = fs.CreateHardLink(src, dest)
+				assert.Equal(t, "link /dir: is a directory", err.Error())
+			},
+		},
+		{
+			CaseName: "Link errors record both paths",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				dest, _ := fspath.NewFileSystemPath("/dir/sub", nil)
+				_, err := fs.Mkdir(dest)
+				assert.Nil(t, err)
+
+				src, _ := fspath.NewFileSystemPath("/dir", nil)
+				_, err = fs.Move(src, dest)
+
+				linkErr := &fserrors.LinkError{}
+				assert.True(t, errors.As(err, &linkErr))
+				assert.Equal(t, "move", linkErr.Op)
+				assert.Equal(t, "/dir", linkErr.Old)
+				assert.Equal(t, "/dir/sub", linkErr.New)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalid))
+			},
+		},
+		{
+			CaseName: "Descriptor errors record the descriptor",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				_, err := fs.Write("closed", []byte("Hello world!"))
+				assert.Equal(t, "write closed: file is not open", err.Error())
+				assert.True(t, errors.Is(err, iofs.ErrClosed))
+				assert.True(t, errors.Is(err, syscall.EBADF))
+			},
+		},
+		{
+			CaseName: "Errors match the io/fs and syscall errors",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/missing", nil)
+				_, err := fs.Stat(p)
+				assert.True(t, errors.Is(err, iofs.ErrNotExist))
+				assert.True(t, errors.Is(err, syscall.ENOENT))
+				assert.False(t, errors.Is(err, iofs.ErrExist))
+
+				p, _ = fspath.NewFileSystemPath("/dir", nil)
+				_, err = fs.Mkdir(p)
+				assert.True(t, errors.Is(err, iofs.ErrExist))
+				assert.True(t, errors.Is(err, syscall.EEXIST))
+
+				_, err = fs.ReadAll(p)
+				assert.True(t, errors.Is(err, fserrors.ErrIsDir))
+				assert.True(t, errors.Is(err, syscall.EISDIR))
+
+				p, _ = fspath.NewFileSystemPath("/dir/file/other", nil)
+				_, err = fs.Stat(p)
+				assert.True(t, errors.Is(err, fserrors.ErrNotDir))
+				assert.True(t, errors.Is(err, syscall.ENOTDIR))
+
+				p, _ = fspath.NewFileSystemPath("/dir/..", nil)
+				_, err = fs.Mkdir(p)
+				assert.True(t, errors.Is(err, iofs.ErrInvalid))
+				assert.True(t, errors.Is(err, syscall.EINVAL))
+			},
+		},
+		{
+			CaseName: "Transactions record the path",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				tx := fs.Begin()
+				defer tx.Rollback()
+
+				p, _ := fspath.NewFileSystemPath("/dir/file", nil)
+				_, err := tx.Mkdir(p)
+				assert.Equal(t, "mkdir /dir/file: file already exists", err.Error())
+				assert.True(t, errors.Is(err, iofs.ErrExist))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.CaseName, func(t *testing.T) {
+			fs := newFileSystem()
+			p, _ := fspath.NewFileSystemPath("/dir", nil)
+			if _, err := fs.Mkdir(p); err != nil {
+				t.Fatal(err)
+			}
+			p, _ = fspath.NewFileSystemPath("/dir/file", nil)
+			if _, err := fs.CreateRegularFile(p); err != nil {
+				t.Fatal(err)
+			}
+			c.Assertions(t, fs)
+		})
+	}
+}
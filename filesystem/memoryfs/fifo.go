@@ -0,0 +1,239 @@
+package memoryfs
+
+import (
+	"context"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsversion"
+	"sync"
+)
+
+// fifoBufferSize is the maximum number of bytes buffered in a fifo.
+// Writers wait for the readers when the buffer is full.
+const fifoBufferSize = 64 << 10
+
+// pipe is the buffer of a fifo.
+// Data written by any writer is read once by any reader.
+type pipe struct {
+	buff []byte
+	// number of open read and write ends
+	readers int
+	writers int
+	// number of read and write ends opened so far,
+	// used to wake up an open waiting for an end that was opened and closed
+	readerOpens int
+	writerOpens int
+	cond        *sync.Cond
+	sync.Mutex
+}
+
+func newPipe() *pipe {
+	p := &pipe{}
+	p.cond = sync.NewCond(&p.Mutex)
+	return p
+}
+
+// snapshotPipe returns an empty pipe if p is not nil.
+// Buffered data is not part of the fifo content.
+func snapshotPipe(p *pipe) *pipe {
+	if p == nil {
+		return nil
+	}
+	return newPipe()
+}
+
+// Mkfifo creates a named pipe at the specified path.
+// A fifo has no content: the data written by a writer is buffered until a reader reads it.
+// This implementation is thead safe.
+//
+// Returns an error when:
+// - the file name is invalid
+// - the file already exists
+// - any of the directory in the path does not exist
+func (fs *MemoryFileSystem) Mkfifo(path *fspath.FileSystemPath) (file.File, error) {
+	// RW lock the fs
+	fs.Lock()
+	defer fs.Unlock()
+	fifo, err := fs.createAt(path, file.Fifo, false)
+	return fifo, pathError("mkfifo", path, err)
+}
+
+// openFifo opens the ends of the fifo in flags.
+// The caller must have registered an open descriptor on data.
+// This method should be called without holding the fs lock, since it may wait for the other end.
+func (fs *MemoryFileSystem) openFifo(ctx context.Context, data *inMemoryFileData, flags file.OpenFlag, owner string) (string, error) {
+	if err := data.pipe.open(ctx, flags); err != nil {
+		// the fifo may have been deleted while waiting
+		fs.openFiles.Lock()
+		defer fs.openFiles.Unlock()
+		fs.closeData(data, fsversion.Policy{})
+		return "", err
+	}
+	return fs.addDescriptor(&fileDescriptor{data: data, offset: 0, flags: flags, owner: owner}), nil
+}
+
+// open opens the read and/or write end.
+// Opening a single end waits until the other end is open,
+// or fails with ErrWouldBlock in non-blocking mode.
+// Opening both ends never waits.
+// It stops waiting when ctx is done, closes the end and returns ctx.Err().
+// This implementation is thread safe.
+func (p *pipe) open(ctx context.Context, flags file.OpenFlag) error {
+	stop := p.wakeOnDone(ctx)
+	defer stop()
+	p.Lock()
+	defer p.Unlock()
+
+	isReader := flags&file.OpenRead != 0
+	isWriter := flags&file.OpenWrite != 0
+	if flags&file.OpenNonBlocking != 0 && ((isReader && !isWriter && p.writers == 0) || (isWriter && !isReader && p.readers == 0)) {
+		return fserrors.ErrWouldBlock
+	}
+
+	if isReader {
+		p.readers++
+		p.readerOpens++
+	}
+	if isWriter {
+		p.writers++
+		p.writerOpens++
+	}
+	p.cond.Broadcast()
+
+	if isReader && !isWriter {
+		writerOpens := p.writerOpens
+		for p.writers == 0 && p.writerOpens == writerOpens {
+			if err := ctx.Err(); err != nil {
+				p.readers--
+				p.cond.Broadcast()
+				return err
+			}
+			p.cond.Wait()
+		}
+	}
+	if isWriter && !isReader {
+		readerOpens := p.readerOpens
+		for p.readers == 0 && p.readerOpens == readerOpens {
+			if err := ctx.Err(); err != nil {
+				p.writers--
+				p.cond.Broadcast()
+				return err
+			}
+			p.cond.Wait()
+		}
+	}
+	return nil
+}
+
+// close closes the ends in flags and wakes up the waiting readers and writers.
+// The buffered data is discarded once both ends are closed.
+// This implementation is thread safe.
+func (p *pipe) close(flags file.OpenFlag) {
+	p.Lock()
+	defer p.Unlock()
+
+	if flags&file.OpenRead != 0 {
+		p.readers--
+	}
+	if flags&file.OpenWrite != 0 {
+		p.writers--
+	}
+	if p.readers == 0 && p.writers == 0 {
+		p.buff = nil
+	}
+	p.cond.Broadcast()
+}
+
+// read reads up to len(buff) buffered bytes.
+// It waits for data while there is at least a writer,
+// and returns 0 bytes at the end of the stream, when every writer is closed.
+// In non-blocking mode it fails with ErrWouldBlock instead of waiting.
+// It stops waiting when ctx is done and returns ctx.Err().
+// This implementation is thread safe.
+func (p *pipe) read(ctx context.Context, buff []byte, nonBlocking bool) (int, error) {
+	stop := p.wakeOnDone(ctx)
+	defer stop()
+	p.Lock()
+	defer p.Unlock()
+
+	if len(buff) == 0 {
+		return 0, nil
+	}
+
+	for len(p.buff) == 0 {
+		if p.writers == 0 {
+			return 0, nil
+		}
+		if nonBlocking {
+			return 0, fserrors.ErrWouldBlock
+		}
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		p.cond.Wait()
+	}
+
+	n := copy(buff, p.buff)
+	p.buff = append(p.buff[:0], p.buff[n:]...)
+	p.cond.Broadcast()
+	return n, nil
+}
+
+// wakeOnDone wakes up the waiting readers and writers when ctx is done,
+// so that they can give up. The returned function stops watching ctx.
+func (p *pipe) wakeOnDone(ctx context.Context) func() {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			p.Lock()
+			p.cond.Broadcast()
+			p.Unlock()
+		case <-stop:
+		}
+	}()
+	return func() { close(stop) }
+}
+
+// write buffers content, waiting for the readers while the buffer is full.
+// In non-blocking mode it returns the bytes written so far instead of waiting,
+// or fails with ErrWouldBlock if nothing was written.
+// Fails with ErrBrokenPipe if there are no readers.
+// This implementation is thread safe.
+func (p *pipe) write(content []byte, nonBlocking bool) (int, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	written := 0
+	for written < len(content) {
+		if p.readers == 0 {
+			return written, fserrors.ErrBrokenPipe
+		}
+
+		free := fifoBufferSize - len(p.buff)
+		if free == 0 {
+			if nonBlocking {
+				if written > 0 {
+					return written, nil
+				}
+				return 0, fserrors.ErrWouldBlock
+			}
+			p.cond.Wait()
+			continue
+		}
+
+		n := len(content) - written
+		if n > free {
+			n = free
+		}
+		p.buff = append(p.buff, content[written:written+n]...)
+		written += n
+		p.cond.Broadcast()
+	}
+	return written, nil
+}
@@ -0,0 +1,289 @@
+package memoryfs_test
+
+import (
+	"context"
+	"errors"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/memoryfs"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// openAsync opens the fifo in a goroutine and returns a channel receiving the descriptor
+func openAsync(fs *memoryfs.MemoryFileSystem, p *fspath.FileSystemPath, flags file.OpenFlag) chan string {
+	ch := make(chan string, 1)
+	go func() {
+		fd, _ := fs.OpenFile(p, flags)
+		ch <- fd
+	}()
+	return ch
+}
+
+func TestFifo(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem, p *fspath.FileSystemPath)
+	}{
+		{
+			CaseName: "A fifo is listed and has the fifo type",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, p *fspath.FileSystemPath) {
+				stat, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.Equal(t, file.Fifo, stat.FileType())
+				assert.Equal(t, 0, stat.Size())
+
+				root, _ := fspath.NewFileSystemPath("/", nil)
+				files, err := fs.ListFiles(root)
+				assert.Nil(t, err)
+				assert.Len(t, files, 1)
+				assert.Equal(t, file.Fifo, files[0].FileType())
+
+				_, err = fs.Mkfifo(p)
+				assert.True(t, errors.Is(err, fserrors.ErrExist))
+			},
+		},
+		{
+			CaseName: "Open waits for the other end",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, p *fspath.FileSystemPath) {
+				reader := openAsync(fs, p, file.OpenRead)
+				select {
+				case <-reader:
+					t.Fatal("open returned without a writer")
+				case <-time.After(50 * time.Millisecond):
+				}
+
+				writer, err := fs.OpenFile(p, file.OpenWrite)
+				assert.Nil(t, err)
+				defer fs.Close(writer)
+
+				select {
+				case fd := <-reader:
+					assert.NotEmpty(t, fd)
+					fs.Close(fd)
+				case <-time.After(time.Second):
+					t.Fatal("open did not return after the writer opened")
+				}
+			},
+		},
+		{
+			CaseName: "Non-blocking open fails without the other end",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, p *fspath.FileSystemPath) {
+				_, err := fs.OpenFile(p, file.OpenRead|file.OpenNonBlocking)
+				assert.True(t, errors.Is(err, fserrors.ErrWouldBlock))
+				_, err = fs.OpenFile(p, file.OpenWrite|file.OpenNonBlocking)
+				assert.True(t, errors.Is(err, fserrors.ErrWouldBlock))
+
+				// both ends never wait
+				fd, err := fs.Open(p)
+				assert.Nil(t, err)
+				defer fs.Close(fd)
+
+				reader, err := fs.OpenFile(p, file.OpenRead|file.OpenNonBlocking)
+				assert.Nil(t, err)
+				defer fs.Close(reader)
+
+				_, err = fs.Read(reader, make([]byte, 10))
+				assert.True(t, errors.Is(err, fserrors.ErrWouldBlock))
+			},
+		},
+		{
+			CaseName: "Read returns the written data, then EOF when every writer is closed",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, p *fspath.FileSystemPath) {
+				reader := openAsync(fs, p, file.OpenRead)
+				writer, err := fs.OpenFile(p, file.OpenWrite)
+				assert.Nil(t, err)
+				fd := <-reader
+				defer fs.Close(fd)
+
+				n, err := fs.Write(writer, []byte("Hello"))
+				assert.Nil(t, err)
+				assert.Equal(t, 5, n)
+				fs.Close(writer)
+
+				buff := make([]byte, 10)
+				n, err = fs.Read(fd, buff)
+				assert.Nil(t, err)
+				assert.Equal(t, []byte("Hello"), buff[:n])
+
+				n, err = fs.Read(fd, buff)
+				assert.Nil(t, err)
+				assert.Equal(t, 0, n)
+			},
+		},
+		{
+			CaseName: "Read gives up waiting when the context is done",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, p *fspath.FileSystemPath) {
+				reader := openAsync(fs, p, file.OpenRead)
+				writer, err := fs.OpenFile(p, file.OpenWrite)
+				assert.Nil(t, err)
+				defer fs.Close(writer)
+				fd := <-reader
+				defer fs.Close(fd)
+
+				ctx, cancel := context.WithCancel(context.Background())
+				done := make(chan error, 1)
+				go func() {
+					_, err := fs.ReadContext(ctx, fd, make([]byte, 10))
+					done <- err
+				}()
+				select {
+				case <-done:
+					t.Fatal("read returned without data")
+				case <-time.After(50 * time.Millisecond):
+				}
+
+				cancel()
+				select {
+				case err := <-done:
+					assert.True(t, errors.Is(err, context.Canceled))
+				case <-time.After(time.Second):
+					t.Fatal("read did not return after the context was cancelled")
+				}
+
+				// the descriptor is still usable
+				_, err = fs.Write(writer, []byte("Hello"))
+				assert.Nil(t, err)
+				buff := make([]byte, 10)
+				n, err := fs.Read(fd, buff)
+				assert.Nil(t, err)
+				assert.Equal(t, []byte("Hello"), buff[:n])
+			},
+		},
+		{
+			CaseName: "Open gives up waiting when the context is done",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, p *fspath.FileSystemPath) {
+				ctx, cancel := context.WithCancel(context.Background())
+				done := make(chan error, 1)
+				go func() {
+					_, err := fs.OpenFileContext(ctx, p, file.OpenRead, "")
+					done <- err
+				}()
+				select {
+				case <-done:
+					t.Fatal("open returned without a writer")
+				case <-time.After(50 * time.Millisecond):
+				}
+
+				cancel()
+				select {
+				case err := <-done:
+					assert.True(t, errors.Is(err, context.Canceled))
+				case <-time.After(time.Second):
+					t.Fatal("open did not return after the context was cancelled")
+				}
+
+				// the cancelled reader is closed
+				assert.Equal(t, 0, fs.StatFS().OpenDescriptors)
+				_, err := fs.OpenFile(p, file.OpenWrite|file.OpenNonBlocking)
+				assert.True(t, errors.Is(err, fserrors.ErrWouldBlock))
+			},
+		},
+		{
+			CaseName: "Writes wait while the buffer is full",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, p *fspath.FileSystemPath) {
+				reader := openAsync(fs, p, file.OpenRead)
+				writer, err := fs.OpenFile(p, file.OpenWrite)
+				assert.Nil(t, err)
+				fd := <-reader
+				defer fs.Close(fd)
+
+				content := randomContent(1, 200<<10)
+				written := make(chan int, 1)
+				go func() {
+					n, _ := fs.Write(writer, content)
+					fs.Close(writer)
+					written <- n
+				}()
+
+				select {
+				case <-written:
+					t.Fatal("write returned without a reader")
+				case <-time.After(50 * time.Millisecond):
+				}
+
+				read := []byte{}
+				buff := make([]byte, 32<<10)
+				for {
+					n, err := fs.Read(fd, buff)
+					assert.Nil(t, err)
+					if n == 0 {
+						break
+					}
+					read = append(read, buff[:n]...)
+				}
+				assert.Equal(t, len(content), <-written)
+				assert.Equal(t, content, read)
+			},
+		},
+		{
+			CaseName: "Write fails without readers",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, p *fspath.FileSystemPath) {
+				reader := openAsync(fs, p, file.OpenRead)
+				writer, err := fs.OpenFile(p, file.OpenWrite)
+				assert.Nil(t, err)
+				defer fs.Close(writer)
+				fs.Close(<-reader)
+
+				_, err = fs.Write(writer, []byte("Hello"))
+				assert.True(t, errors.Is(err, fserrors.ErrBrokenPipe))
+			},
+		},
+		{
+			CaseName: "Positional operations are not supported",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, p *fspath.FileSystemPath) {
+				fd, err := fs.Open(p)
+				assert.Nil(t, err)
+				defer fs.Close(fd)
+
+				_, err = fs.ReadAt(fd, make([]byte, 10), 0)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalid))
+				_, err = fs.WriteAt(fd, []byte("Hello"), 0)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalid))
+				_, err = fs.SeekData(fd, 0)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalid))
+
+				_, err = fs.ReadAll(p)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalid))
+				err = fs.AppendAll(p, []byte("Hello"))
+				assert.True(t, errors.Is(err, fserrors.ErrInvalid))
+			},
+		},
+		{
+			CaseName: "Descriptors can only be used in their open mode",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, p *fspath.FileSystemPath) {
+				assert.Nil(t, createFileWithContent(fs, "/file", []byte("Hello")))
+				filePath, _ := fspath.NewFileSystemPath("/file", nil)
+
+				fd, err := fs.OpenFile(filePath, file.OpenRead)
+				assert.Nil(t, err)
+				_, err = fs.Write(fd, []byte("world"))
+				assert.True(t, errors.Is(err, fserrors.ErrBadDescriptor))
+				fs.Close(fd)
+
+				fd, err = fs.OpenFile(filePath, file.OpenWrite)
+				assert.Nil(t, err)
+				_, err = fs.Read(fd, make([]byte, 5))
+				assert.True(t, errors.Is(err, fserrors.ErrBadDescriptor))
+				fs.Close(fd)
+
+				_, err = fs.OpenFile(filePath, file.OpenNonBlocking)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalid))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.CaseName, func(t *testing.T) {
+			fs := newFileSystem()
+			p, _ := fspath.NewFileSystemPath("/fifo", nil)
+			if _, err := fs.Mkfifo(p); err != nil {
+				t.Fatal(err)
+			}
+			c.Assertions(t, fs, p)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package memoryfs
 import (
 	"material/filesystem/filesystem/file"
 	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsquery"
 	"regexp"
 	"sort"
 )
@@ -14,25 +15,183 @@ func (fs *MemoryFileSystem) FindFiles(nameRegex string, path *fspath.FileSystemP
 	fs.RLock()
 	defer fs.RUnlock()
 	// Get directory to start the search
+	if fs.caseInsensitive {
+		nameRegex = "(?i)" + nameRegex
+	}
 	exp, err := regexp.Compile(nameRegex)
 	if err != nil {
-		return matchingFiles, err
+		return matchingFiles, pathError("find", path, err)
+	}
+
+	pathRoot, err := fs.traverseToBase(path)
+	if err != nil {
+		return matchingFiles, pathError("find", path, err)
 	}
 
-	err = fs.Walk(path, func(f file.File) error {
+	err = fs.doWalk(pathRoot, func(f file.File) error {
 		if exp.MatchString(f.Info().Name()) {
 			matchingFiles = append(matchingFiles, f.Info())
 		}
 		return nil
 	}, func(f file.File) bool {
 		return true
-	}, true)
+	}, true, 0)
 
 	if err != nil {
-		return matchingFiles, err
+		return matchingFiles, pathError("find", path, err)
 	}
 
 	sort.Sort(ByAbsolutePath(matchingFiles))
 
 	return matchingFiles, nil
 }
+
+// Find walks the file tree rooted at path and returns the files matching the query
+// sorted by absolute path.
+// If the query action is ActionDelete, the matching files are removed from the
+// tree, children before their parents. With ActionTrash they are moved to the
+// trash of the query user instead. Directories that are not empty after their
+// matching children have been removed are kept and not included in the result.
+// When following symbolic links, every directory is visited at most once,
+// the links are matched with the attributes of their targets and reported at
+// their own path. Deleting never removes a link target or a file reached
+// through a link.
+// In a case-insensitive file system the name predicates ignore case.
+// The files are visited as in WalkDir.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the path does not exist
+func (fs *MemoryFileSystem) Find(path *fspath.FileSystemPath, query *fsquery.Query) ([]file.FileStat, error) {
+	if query.Action == fsquery.ActionDelete || query.Action == fsquery.ActionTrash {
+		fs.Lock()
+		defer fs.Unlock()
+	} else {
+		fs.RLock()
+		defer fs.RUnlock()
+	}
+
+	// Initialize result
+	matchingFiles := []file.FileStat{}
+
+	pathRoot, err := fs.traverseToBase(path)
+	if err != nil {
+		return matchingFiles, pathError("find", path, err)
+	}
+
+	matches := []*walkedFile{}
+	matchedPaths := map[string]bool{}
+	root := &walkedFile{path: path.AbsolutePath(), entry: pathRoot, target: pathRoot}
+	err = fs.walkTree(root, query.FollowLinks, map[*inMemoryFile]bool{}, func(w *walkedFile) error {
+		stat := newWalkedFileStat(w)
+		entry := &fsquery.Entry{
+			FileStat:   stat,
+			Depth:      w.depth,
+			IsEmpty:    isEmpty(w.target, stat),
+			IgnoreCase: fs.caseInsensitive,
+		}
+
+		if query.IsPruned(entry) {
+			return skipFile(w.target)
+		}
+
+		if query.Matches(entry) && !matchedPaths[w.path] {
+			matchedPaths[w.path] = true
+			matches = append(matches, w)
+		}
+
+		if !query.CanDescend(entry) {
+			return skipFile(w.target)
+		}
+		return nil
+	})
+	if err != nil {
+		return matchingFiles, pathError("find", path, err)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].path < matches[j].path
+	})
+
+	switch query.Action {
+	case fsquery.ActionDelete:
+		return fs.deleteMatches(matches, func(f *inMemoryFile) error {
+			_, err := fs.removeFile(f, true, newReporter(false))
+			return err
+		}), nil
+	case fsquery.ActionTrash:
+		return fs.deleteMatches(matches, func(f *inMemoryFile) error {
+			_, err := fs.trashFile(f, query.User, true)
+			return err
+		}), nil
+	}
+
+	for _, w := range matches {
+		matchingFiles = append(matchingFiles, newWalkedFileStat(w))
+	}
+	return matchingFiles, nil
+}
+
+// skipFile returns the value that makes walkTree skip
+// only the given file and its content
+func skipFile(f *inMemoryFile) error {
+	if f.data.fileType == file.Directory {
+		return file.SkipDir
+	}
+	return nil
+}
+
+// deleteMatches removes the matching files starting from the deepest ones
+// and returns the removed files.
+// Symbolic links are removed, not their targets, and the files reached
+// through a symbolic link are never removed.
+// The files that can't be removed, like the root and the virtual files, are kept.
+// The files are removed with the remove function.
+func (fs *MemoryFileSystem) deleteMatches(matches []*walkedFile, remove func(f *inMemoryFile) error) []file.FileStat {
+	deleted := []file.FileStat{}
+	for i := len(matches) - 1; i >= 0; i-- {
+		f := matches[i].entry
+		if matches[i].throughLink || f.isDeleted || (f.data.fileType == file.Directory && !isEmptyDirectory(f)) {
+			continue
+		}
+		stat := newInMemoryFileStat(f)
+		if err := remove(f); err != nil {
+			continue
+		}
+		deleted = append(deleted, stat)
+	}
+
+	sort.Slice(deleted, func(i, j int) bool {
+		return deleted[i].AbsolutePath() < deleted[j].AbsolutePath()
+	})
+	return deleted
+}
+
+// isEmpty returns true for empty regular files and directories without children
+func isEmpty(f *inMemoryFile, stat file.FileStat) bool {
+	switch f.data.fileType {
+	case file.Directory:
+		return isEmptyDirectory(f)
+	case file.RegularFile:
+		return stat.Size() == 0
+	default:
+		return false
+	}
+}
+
+// isEmptyDirectory returns true if the directory has no children
+func isEmptyDirectory(dir *inMemoryFile) bool {
+	dir.materialize()
+	return len(dir.fileMap) <= numberOfSpecialEntries(dir)
+}
+
+// numberOfSpecialEntries returns the number of ".", ".." and "/" entries in the directory
+func numberOfSpecialEntries(dir *inMemoryFile) int {
+	count := 0
+	for _, name := range []string{".", "..", "/"} {
+		if _, found := dir.fileMap[name]; found {
+			count++
+		}
+	}
+	return count
+}
@@ -5,8 +5,11 @@ import (
 	"material/filesystem/filesystem/file"
 	"material/filesystem/filesystem/fserrors"
 	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsquery"
+	"material/filesystem/filesystem/fsvirtual"
 	"material/filesystem/filesystem/memoryfs"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -24,7 +27,7 @@ func TestFindFiles(t *testing.T) {
 			Path:     "/",
 			FileName: "target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -57,7 +60,7 @@ func TestFindFiles(t *testing.T) {
 			Path:     "/target",
 			FileName: "target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -88,7 +91,7 @@ func TestFindFiles(t *testing.T) {
 			Path:     "/",
 			FileName: "invalid",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -115,7 +118,7 @@ func TestFindFiles(t *testing.T) {
 			Path:     "/invalid",
 			FileName: "target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -135,7 +138,7 @@ func TestFindFiles(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 				assert.NotNil(t, files)
 				assert.Len(t, files, 0)
 			},
@@ -145,7 +148,7 @@ func TestFindFiles(t *testing.T) {
 			Path:     "../../../.",
 			FileName: "target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				workDir, err := fs.MkdirAll(p)
 				if err != nil {
@@ -179,7 +182,7 @@ func TestFindFiles(t *testing.T) {
 			Path:     ".",
 			FileName: "target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -212,7 +215,7 @@ func TestFindFiles(t *testing.T) {
 			Path:     "..",
 			FileName: "dir2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -243,7 +246,7 @@ func TestFindFiles(t *testing.T) {
 			Path:     "../../dir/.",
 			FileName: "target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -267,7 +270,7 @@ func TestFindFiles(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 				assert.NotNil(t, files)
 				assert.Len(t, files, 0)
 			},
@@ -277,7 +280,7 @@ func TestFindFiles(t *testing.T) {
 			Path:     "../../dir/.",
 			FileName: "target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -306,7 +309,7 @@ func TestFindFiles(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrInvalidWorkingDirectory)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalidWorkingDirectory))
 				assert.NotNil(t, files)
 				assert.Len(t, files, 0)
 			},
@@ -316,7 +319,7 @@ func TestFindFiles(t *testing.T) {
 			Path:     "/target-link",
 			FileName: "target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p1); err != nil {
 					return nil, nil, err
@@ -358,3 +361,359 @@ func TestFindFiles(t *testing.T) {
 		testCase.Assertions(t, dir, err)
 	}
 }
+
+func initializeFindTree() (*memoryfs.MemoryFileSystem, file.File, error) {
+	fs := newFileSystem()
+	for _, dir := range []string{"/dir1/dir2/dir3", "/dir1/empty", "/skip/dir"} {
+		p, _ := fspath.NewFileSystemPath(dir, nil)
+		if _, err := fs.MkdirAll(p); err != nil {
+			return nil, nil, err
+		}
+	}
+	files := map[string]string{
+		"/a.txt":                "",
+		"/dir1/b.txt":           "some content",
+		"/dir1/dir2/c.log":      "more content in this file",
+		"/dir1/dir2/dir3/d.txt": "d",
+		"/skip/e.txt":           "e",
+	}
+	for name, content := range files {
+		p, _ := fspath.NewFileSystemPath(name, nil)
+		if err := fs.AppendAll(p, []byte(content)); err != nil {
+			return nil, nil, err
+		}
+	}
+	return fs, nil, nil
+}
+
+// initializeFindLinks adds to the find tree a link to a file
+// and a link to a directory
+func initializeFindLinks() (*memoryfs.MemoryFileSystem, file.File, error) {
+	fs, _, err := initializeFindTree()
+	if err != nil {
+		return nil, nil, err
+	}
+	links := map[string]string{
+		"/dir1/b.txt": "/skip/b-link.txt",
+		"/dir1/dir2":  "/skip/dir-link",
+	}
+	for target, link := range links {
+		p1, _ := fspath.NewFileSystemPath(target, nil)
+		p2, _ := fspath.NewFileSystemPath(link, nil)
+		if _, err := fs.CreateSymbolicLink(p1, p2); err != nil {
+			return nil, nil, err
+		}
+	}
+	return fs, nil, nil
+}
+
+func TestFind(t *testing.T) {
+	mustNameGlob := func(pattern string) fsquery.Predicate {
+		predicate, _ := fsquery.NameGlob(pattern)
+		return predicate
+	}
+
+	cases := []struct {
+		CaseName   string
+		Path       string
+		Query      func() *fsquery.Query
+		Initialize func() (*memoryfs.MemoryFileSystem, file.File, error)
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem, paths []string, err error)
+	}{
+		{
+			CaseName:   "Find regular files",
+			Path:       "/",
+			Initialize: initializeFindTree,
+			Query: func() *fsquery.Query {
+				query := fsquery.NewQuery()
+				query.Match = fsquery.Type(file.RegularFile)
+				return query
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/a.txt", "/dir1/b.txt", "/dir1/dir2/c.log", "/dir1/dir2/dir3/d.txt", "/skip/e.txt"}, paths)
+			},
+		},
+		{
+			CaseName:   "Find files by size range",
+			Path:       "/",
+			Initialize: initializeFindTree,
+			Query: func() *fsquery.Query {
+				query := fsquery.NewQuery()
+				query.Match = fsquery.And(fsquery.Type(file.RegularFile), fsquery.SizeRange(2, 20))
+				return query
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/dir1/b.txt"}, paths)
+			},
+		},
+		{
+			CaseName:   "Find files by depth",
+			Path:       "/dir1",
+			Initialize: initializeFindTree,
+			Query: func() *fsquery.Query {
+				query := fsquery.NewQuery()
+				query.MinDepth = 1
+				query.MaxDepth = 2
+				return query
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/dir1/b.txt", "/dir1/dir2", "/dir1/dir2/c.log", "/dir1/dir2/dir3", "/dir1/empty"}, paths)
+			},
+		},
+		{
+			CaseName:   "Find files combining predicates",
+			Path:       "/",
+			Initialize: initializeFindTree,
+			Query: func() *fsquery.Query {
+				query := fsquery.NewQuery()
+				query.Match = fsquery.Or(
+					fsquery.And(mustNameGlob("*.txt"), fsquery.Not(fsquery.Empty())),
+					fsquery.And(fsquery.Type(file.Directory), fsquery.Empty()),
+				)
+				return query
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/dir1/b.txt", "/dir1/dir2/dir3/d.txt", "/dir1/empty", "/skip/dir", "/skip/e.txt"}, paths)
+			},
+		},
+		{
+			CaseName:   "Find files by path regex with prune",
+			Path:       "/",
+			Initialize: initializeFindTree,
+			Query: func() *fsquery.Query {
+				query := fsquery.NewQuery()
+				query.Match, _ = fsquery.PathRegex(`\.txt$`)
+				query.Prune = mustNameGlob("skip")
+				return query
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/a.txt", "/dir1/b.txt", "/dir1/dir2/dir3/d.txt"}, paths)
+			},
+		},
+		{
+			CaseName:   "Find files by modification time",
+			Path:       "/",
+			Initialize: initializeFindTree,
+			Query: func() *fsquery.Query {
+				query := fsquery.NewQuery()
+				query.Match = fsquery.And(fsquery.Type(file.RegularFile), fsquery.ModTimeRange(time.Now().Add(time.Hour), time.Time{}))
+				return query
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Empty(t, paths)
+			},
+		},
+		{
+			CaseName: "Find files by link count",
+			Path:     "/",
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs, _, err := initializeFindTree()
+				if err != nil {
+					return nil, nil, err
+				}
+				p1, _ := fspath.NewFileSystemPath("/dir1/b.txt", nil)
+				p2, _ := fspath.NewFileSystemPath("/b-link", nil)
+				if _, err := fs.CreateHardLink(p1, p2); err != nil {
+					return nil, nil, err
+				}
+				return fs, nil, nil
+			},
+			Query: func() *fsquery.Query {
+				query := fsquery.NewQuery()
+				query.Match = fsquery.And(fsquery.Type(file.RegularFile), fsquery.LinkCount(2, -1))
+				return query
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/b-link", "/dir1/b.txt"}, paths)
+			},
+		},
+		{
+			CaseName: "Find files following symbolic link cycles",
+			Path:     "/dir1",
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs, _, err := initializeFindTree()
+				if err != nil {
+					return nil, nil, err
+				}
+				p1, _ := fspath.NewFileSystemPath("/dir1", nil)
+				p2, _ := fspath.NewFileSystemPath("/dir1/dir2/dir3/loop", nil)
+				if _, err := fs.CreateSymbolicLink(p1, p2); err != nil {
+					return nil, nil, err
+				}
+				return fs, nil, nil
+			},
+			Query: func() *fsquery.Query {
+				query := fsquery.NewQuery()
+				query.Match = mustNameGlob("*.txt")
+				query.FollowLinks = true
+				return query
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/dir1/b.txt", "/dir1/dir2/dir3/d.txt"}, paths)
+			},
+		},
+		{
+			CaseName:   "Find files following symbolic links reports the link paths",
+			Path:       "/skip",
+			Initialize: initializeFindLinks,
+			Query: func() *fsquery.Query {
+				query := fsquery.NewQuery()
+				query.Match = fsquery.And(fsquery.Type(file.RegularFile), fsquery.SizeRange(1, -1))
+				query.FollowLinks = true
+				return query
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/skip/b-link.txt", "/skip/dir-link/c.log", "/skip/dir-link/dir3/d.txt", "/skip/e.txt"}, paths)
+			},
+		},
+		{
+			CaseName:   "Find files following symbolic links matches a target once per path",
+			Path:       "/",
+			Initialize: initializeFindLinks,
+			Query: func() *fsquery.Query {
+				query := fsquery.NewQuery()
+				query.Match = mustNameGlob("b*")
+				query.FollowLinks = true
+				return query
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/dir1/b.txt", "/skip/b-link.txt"}, paths)
+			},
+		},
+		{
+			CaseName:   "Delete matching files following symbolic links",
+			Path:       "/skip",
+			Initialize: initializeFindLinks,
+			Query: func() *fsquery.Query {
+				query := fsquery.NewQuery()
+				query.Match = fsquery.Or(mustNameGlob("*.txt"), mustNameGlob("*.log"))
+				query.FollowLinks = true
+				query.Action = fsquery.ActionDelete
+				return query
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, paths []string, err error) {
+				assert.Nil(t, err)
+				// the files in the linked directory are not removed
+				assert.Equal(t, []string{"/skip/b-link.txt", "/skip/e.txt"}, paths)
+
+				p, _ := fspath.NewFileSystemPath("/skip", nil)
+				files, err := fs.ListFiles(p)
+				assert.Nil(t, err)
+				assert.Len(t, files, 2)
+				assert.Equal(t, "/skip/dir", files[0].AbsolutePath())
+				assert.Equal(t, "/skip/dir-link", files[1].AbsolutePath())
+
+				for _, name := range []string{"/dir1/b.txt", "/dir1/dir2/c.log", "/dir1/dir2/dir3/d.txt"} {
+					p, _ := fspath.NewFileSystemPath(name, nil)
+					_, err := fs.Stat(p)
+					assert.Nil(t, err, name)
+				}
+			},
+		},
+		{
+			CaseName: "Delete matching files keeps the virtual files",
+			Path:     "/",
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs, _, err := initializeFindTree()
+				if err != nil {
+					return nil, nil, err
+				}
+				p, _ := fspath.NewFileSystemPath("/dev", nil)
+				return fs, nil, fs.Mount(p, fsvirtual.Devices())
+			},
+			Query: func() *fsquery.Query {
+				query := fsquery.NewQuery()
+				query.Match = fsquery.Or(mustNameGlob("null"), mustNameGlob("a.txt"))
+				query.Action = fsquery.ActionDelete
+				return query
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/a.txt"}, paths)
+
+				p, _ := fspath.NewFileSystemPath("/dev/null", nil)
+				_, err = fs.Stat(p)
+				assert.Nil(t, err)
+			},
+		},
+		{
+			CaseName:   "Trash matching files",
+			Path:       "/dir1",
+			Initialize: initializeFindTree,
+			Query: func() *fsquery.Query {
+				query := fsquery.NewQuery()
+				query.Match = fsquery.Or(mustNameGlob("*.txt"), mustNameGlob("dir3"))
+				query.Action = fsquery.ActionTrash
+				query.User = "user"
+				return query
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/dir1/b.txt", "/dir1/dir2/dir3", "/dir1/dir2/dir3/d.txt"}, paths)
+
+				trashed := []string{}
+				for _, entry := range fs.ListTrash("user") {
+					trashed = append(trashed, entry.OriginalPath)
+				}
+				assert.ElementsMatch(t, paths, trashed)
+			},
+		},
+		{
+			CaseName:   "Delete matching files",
+			Path:       "/dir1",
+			Initialize: initializeFindTree,
+			Query: func() *fsquery.Query {
+				query := fsquery.NewQuery()
+				query.Match = fsquery.Or(mustNameGlob("*.txt"), mustNameGlob("dir*"))
+				query.Action = fsquery.ActionDelete
+				return query
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, paths []string, err error) {
+				assert.Nil(t, err)
+				// dir1 and dir2 are not empty
+				assert.Equal(t, []string{"/dir1/b.txt", "/dir1/dir2/dir3", "/dir1/dir2/dir3/d.txt"}, paths)
+
+				p, _ := fspath.NewFileSystemPath("/dir1", nil)
+				files, err := fs.ListFiles(p)
+				assert.Nil(t, err)
+				assert.Len(t, files, 2)
+				assert.Equal(t, "/dir1/dir2", files[0].AbsolutePath())
+				assert.Equal(t, "/dir1/empty", files[1].AbsolutePath())
+			},
+		},
+		{
+			CaseName:   "Invalid starting directory",
+			Path:       "/invalid",
+			Initialize: initializeFindTree,
+			Query:      fsquery.NewQuery,
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, paths []string, err error) {
+				assert.NotNil(t, err)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+				assert.Empty(t, paths)
+			},
+		},
+	}
+	for _, testCase := range cases {
+		fs, workingDir, err := testCase.Initialize()
+		if err != nil {
+			t.Fatal("error initializing file system")
+		}
+		p, _ := fspath.NewFileSystemPath(testCase.Path, workingDir)
+		files, err := fs.Find(p, testCase.Query())
+		paths := []string{}
+		for _, stat := range files {
+			paths = append(paths, stat.AbsolutePath())
+		}
+		testCase.Assertions(t, fs, paths, err)
+	}
+}
@@ -0,0 +1,209 @@
+package memoryfs
+
+import (
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"path"
+	"sort"
+	"strings"
+)
+
+// recursiveWildcard matches zero or more directories,
+// or every file and directory at any depth at the end of a pattern
+const recursiveWildcard = "**"
+
+// Glob returns the files matching pattern sorted by absolute path.
+// The pattern follows path.Match semantics for every path segment and also supports:
+// - "**" segments, matching zero or more directories, or any file at the end
+// - brace alternation, e.g. "/dir/{a,b}/*.txt"
+// Segments without wildcards are looked up directly, so only the
+// subtrees that can match are visited.
+// "**" does not follow symbolic links to avoid cycles.
+// In a case-insensitive file system names are matched ignoring case.
+// If no file matches, the result is empty.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the pattern is malformed
+// - the working directory is invalid
+func (fs *MemoryFileSystem) Glob(pattern *fspath.FileSystemPath) ([]file.FileInfo, error) {
+	fs.RLock()
+	defer fs.RUnlock()
+
+	// Initialize result
+	matchingFiles := []file.FileInfo{}
+
+	// Validate working directory
+	if _, err := fs.findPathRoot(pattern); err != nil {
+		return matchingFiles, pathError("glob", pattern, err)
+	}
+
+	// Expand braces and validate every alternative
+	patterns := expandBraces(pattern.AbsolutePath())
+	for _, p := range patterns {
+		if err := checkGlobPattern(p); err != nil {
+			return matchingFiles, pathError("glob", pattern, err)
+		}
+	}
+
+	found := map[string]file.FileInfo{}
+	for _, p := range patterns {
+		fs.doGlob(fs.root, globSegments(p), found)
+	}
+
+	for _, info := range found {
+		matchingFiles = append(matchingFiles, info)
+	}
+	sort.Sort(ByAbsolutePath(matchingFiles))
+	return matchingFiles, nil
+}
+
+// doGlob matches segments against the subtree rooted at dir and adds
+// every match to found.
+func (fs *MemoryFileSystem) doGlob(dir *inMemoryFile, segments []string, found map[string]file.FileInfo) {
+	if len(segments) == 0 {
+		found[dir.info.AbsolutePath()] = dir.info
+		return
+	}
+
+	segment, rest := segments[0], segments[1:]
+	if segment == recursiveWildcard {
+		// match zero directories
+		fs.doGlob(dir, rest, found)
+		// match one or more directories
+		fs.visitDir(dir, func(_ string, child *inMemoryFile) error {
+			if child.data.fileType == file.Directory {
+				fs.doGlob(child, segments, found)
+			} else if len(rest) == 0 {
+				found[child.info.AbsolutePath()] = child.info
+			}
+			return nil
+		})
+		return
+	}
+
+	// Literal segment: no need to scan the whole directory
+	if !hasGlobMeta(segment) {
+		if child, ok := fs.lookup(dir, segment); ok {
+			fs.globChild(child, rest, found)
+		}
+		return
+	}
+
+	pattern := fs.nameKey(segment)
+	fs.visitDir(dir, func(fileName string, child *inMemoryFile) error {
+		// the pattern has already been validated
+		if matched, _ := path.Match(pattern, fs.nameKey(fileName)); matched {
+			fs.globChild(child, rest, found)
+		}
+		return nil
+	})
+}
+
+// globChild adds child to found if there are no more segments to match,
+// otherwise continues matching inside child, resolving symbolic links if needed.
+func (fs *MemoryFileSystem) globChild(child *inMemoryFile, rest []string, found map[string]file.FileInfo) {
+	if len(rest) == 0 {
+		found[child.info.AbsolutePath()] = child.info
+		return
+	}
+
+	dir, err := fs.resolveSymlink(child, 0)
+	if err != nil || dir.data.fileType != file.Directory {
+		return
+	}
+	fs.doGlob(dir, rest, found)
+}
+
+// globSegments splits an absolute pattern into path segments
+func globSegments(pattern string) []string {
+	trimmed := strings.Trim(pattern, "/")
+	if trimmed == "" {
+		return []string{}
+	}
+	return strings.Split(trimmed, "/")
+}
+
+// checkGlobPattern checks that every segment of the pattern is well formed
+func checkGlobPattern(pattern string) error {
+	for _, segment := range globSegments(pattern) {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fserrors.ErrInvalid
+		}
+	}
+	return nil
+}
+
+// hasGlobMeta returns true if the segment contains any special character
+func hasGlobMeta(segment string) bool {
+	return strings.ContainsAny(segment, `*?[\`)
+}
+
+// expandBraces expands the first brace alternation in the pattern
+// and recursively any following one, e.g. "{a,b}/{c,d}" becomes
+// "a/c", "a/d", "b/c" and "b/d".
+// Unbalanced braces are treated as literals.
+func expandBraces(pattern string) []string {
+	start, end := findBraces(pattern)
+	if start < 0 {
+		return []string{path.Clean(pattern)}
+	}
+
+	prefix, suffix := pattern[:start], pattern[end+1:]
+	expanded := []string{}
+	for _, alternative := range splitAlternatives(pattern[start+1 : end]) {
+		expanded = append(expanded, expandBraces(prefix+alternative+suffix)...)
+	}
+	return expanded
+}
+
+// findBraces returns the position of the first balanced pair of braces
+// or -1 if there is none.
+func findBraces(pattern string) (int, int) {
+	start, depth := -1, 0
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			// skip escaped character
+			i++
+		case '{':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case '}':
+			if depth == 0 {
+				continue
+			}
+			depth--
+			if depth == 0 {
+				return start, i
+			}
+		}
+	}
+	return -1, -1
+}
+
+// splitAlternatives splits the content of a brace alternation on
+// the top level commas.
+func splitAlternatives(alternation string) []string {
+	alternatives := []string{}
+	last, depth := 0, 0
+	for i := 0; i < len(alternation); i++ {
+		switch alternation[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				alternatives = append(alternatives, alternation[last:i])
+				last = i + 1
+			}
+		}
+	}
+	return append(alternatives, alternation[last:])
+}
@@ -0,0 +1,242 @@
+package memoryfs_test
+
+import (
+	"errors"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/memoryfs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func initializeGlobTree() (*memoryfs.MemoryFileSystem, file.File, error) {
+	fs := newFileSystem()
+	for _, dir := range []string{"/dir1/dir2/dir3", "/dir1/other", "/docs"} {
+		p, _ := fspath.NewFileSystemPath(dir, nil)
+		if _, err := fs.MkdirAll(p); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, name := range []string{"/a.txt", "/b.txt", "/c.log", "/dir1/d.txt", "/dir1/dir2/e.txt", "/dir1/dir2/dir3/f.txt", "/dir1/other/g.log", "/docs/h.md"} {
+		p, _ := fspath.NewFileSystemPath(name, nil)
+		if _, err := fs.CreateRegularFile(p); err != nil {
+			return nil, nil, err
+		}
+	}
+	return fs, nil, nil
+}
+
+func TestGlob(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Pattern    string
+		Initialize func() (*memoryfs.MemoryFileSystem, file.File, error)
+		Assertions func(t *testing.T, paths []string, err error)
+	}{
+		{
+			CaseName:   "Glob files in root using absolute path",
+			Pattern:    "/*.txt",
+			Initialize: initializeGlobTree,
+			Assertions: func(t *testing.T, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/a.txt", "/b.txt"}, paths)
+			},
+		},
+		{
+			CaseName: "Glob files using relative path",
+			Pattern:  "*.txt",
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs, _, err := initializeGlobTree()
+				if err != nil {
+					return nil, nil, err
+				}
+				p, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
+				workingDir, err := fs.GetDirectory(p)
+				return fs, workingDir, err
+			},
+			Assertions: func(t *testing.T, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/dir1/dir2/e.txt"}, paths)
+			},
+		},
+		{
+			CaseName:   "Glob with wildcard directories",
+			Pattern:    "/*/*/*.txt",
+			Initialize: initializeGlobTree,
+			Assertions: func(t *testing.T, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/dir1/dir2/e.txt"}, paths)
+			},
+		},
+		{
+			CaseName:   "Glob with recursive wildcard",
+			Pattern:    "/**/*.txt",
+			Initialize: initializeGlobTree,
+			Assertions: func(t *testing.T, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/a.txt", "/b.txt", "/dir1/d.txt", "/dir1/dir2/dir3/f.txt", "/dir1/dir2/e.txt"}, paths)
+			},
+		},
+		{
+			CaseName:   "Glob with recursive wildcard in the middle of the pattern",
+			Pattern:    "/dir1/**/dir3",
+			Initialize: initializeGlobTree,
+			Assertions: func(t *testing.T, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/dir1/dir2/dir3"}, paths)
+			},
+		},
+		{
+			CaseName:   "Glob with trailing recursive wildcard",
+			Pattern:    "/dir1/**",
+			Initialize: initializeGlobTree,
+			Assertions: func(t *testing.T, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/dir1", "/dir1/d.txt", "/dir1/dir2", "/dir1/dir2/dir3", "/dir1/dir2/dir3/f.txt", "/dir1/dir2/e.txt", "/dir1/other", "/dir1/other/g.log"}, paths)
+			},
+		},
+		{
+			CaseName:   "Glob with brace alternation",
+			Pattern:    "/{dir1/other,docs}/*",
+			Initialize: initializeGlobTree,
+			Assertions: func(t *testing.T, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/dir1/other/g.log", "/docs/h.md"}, paths)
+			},
+		},
+		{
+			CaseName:   "Glob with nested brace alternation",
+			Pattern:    "/*.{txt,{log,md}}",
+			Initialize: initializeGlobTree,
+			Assertions: func(t *testing.T, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/a.txt", "/b.txt", "/c.log"}, paths)
+			},
+		},
+		{
+			CaseName:   "Glob with overlapping alternatives returns every file once",
+			Pattern:    "/{a,*}.txt",
+			Initialize: initializeGlobTree,
+			Assertions: func(t *testing.T, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/a.txt", "/b.txt"}, paths)
+			},
+		},
+		{
+			CaseName:   "Glob with character class",
+			Pattern:    "/[ab].*",
+			Initialize: initializeGlobTree,
+			Assertions: func(t *testing.T, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/a.txt", "/b.txt"}, paths)
+			},
+		},
+		{
+			CaseName:   "Glob with negated character class and single character wildcard",
+			Pattern:    "/?.[^t]*",
+			Initialize: initializeGlobTree,
+			Assertions: func(t *testing.T, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/c.log"}, paths)
+			},
+		},
+		{
+			CaseName: "Glob follows symbolic links to directories",
+			Pattern:  "/link/*.log",
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs, _, err := initializeGlobTree()
+				if err != nil {
+					return nil, nil, err
+				}
+				p1, _ := fspath.NewFileSystemPath("/dir1/other", nil)
+				p2, _ := fspath.NewFileSystemPath("/link", nil)
+				if _, err := fs.CreateSymbolicLink(p1, p2); err != nil {
+					return nil, nil, err
+				}
+				return fs, nil, nil
+			},
+			Assertions: func(t *testing.T, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/dir1/other/g.log"}, paths)
+			},
+		},
+		{
+			CaseName: "Recursive wildcard does not follow symbolic links",
+			Pattern:  "/**/g.log",
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs, _, err := initializeGlobTree()
+				if err != nil {
+					return nil, nil, err
+				}
+				p1, _ := fspath.NewFileSystemPath("/", nil)
+				p2, _ := fspath.NewFileSystemPath("/dir1/loop", nil)
+				if _, err := fs.CreateSymbolicLink(p1, p2); err != nil {
+					return nil, nil, err
+				}
+				return fs, nil, nil
+			},
+			Assertions: func(t *testing.T, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/dir1/other/g.log"}, paths)
+			},
+		},
+		{
+			CaseName:   "No matching files",
+			Pattern:    "/invalid/*.txt",
+			Initialize: initializeGlobTree,
+			Assertions: func(t *testing.T, paths []string, err error) {
+				assert.Nil(t, err)
+				assert.Empty(t, paths)
+			},
+		},
+		{
+			CaseName:   "Malformed pattern",
+			Pattern:    "/[a-",
+			Initialize: initializeGlobTree,
+			Assertions: func(t *testing.T, paths []string, err error) {
+				assert.NotNil(t, err)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalid))
+				assert.Empty(t, paths)
+			},
+		},
+		{
+			CaseName: "Invalid working directory",
+			Pattern:  "*.txt",
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs, _, err := initializeGlobTree()
+				if err != nil {
+					return nil, nil, err
+				}
+				p, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
+				workingDir, err := fs.GetDirectory(p)
+				if err != nil {
+					return nil, nil, err
+				}
+				if _, err := fs.RemoveAll(p); err != nil {
+					return nil, nil, err
+				}
+				return fs, workingDir, nil
+			},
+			Assertions: func(t *testing.T, paths []string, err error) {
+				assert.NotNil(t, err)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalidWorkingDirectory))
+				assert.Empty(t, paths)
+			},
+		},
+	}
+	for _, testCase := range cases {
+		fs, workingDir, err := testCase.Initialize()
+		if err != nil {
+			t.Fatal("error initializing file system")
+		}
+		p, _ := fspath.NewFileSystemPath(testCase.Pattern, workingDir)
+		files, err := fs.Glob(p)
+		paths := []string{}
+		for _, info := range files {
+			paths = append(paths, info.AbsolutePath())
+		}
+		testCase.Assertions(t, paths, err)
+	}
+}
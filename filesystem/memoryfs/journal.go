@@ -0,0 +1,206 @@
+package memoryfs
+
+import (
+	"material/filesystem/filesystem/file"
+	"time"
+)
+
+// journal records the state of the entries and inodes before they are
+// first modified, so that the changes of a failed commit can be undone.
+// The methods are noops on a nil journal, so that the file system
+// records the changes only while a commit is in progress.
+type journal struct {
+	entries map[*inMemoryFile]*entryState
+	inodes  map[*inMemoryFileData]*inodeState
+	// inodes created during the commit, released on undo
+	created map[*inMemoryFileData]bool
+	// orphans added during the commit
+	orphans    []*inMemoryFileData
+	nextOrphan int
+	trash      map[string][]*trashEntry
+	lastIno    uint64
+}
+
+// entryState is a directory entry as it was before the commit
+type entryState struct {
+	data      *inMemoryFileData
+	isDeleted bool
+	fileMap   map[string]*inMemoryFile
+	names     []string
+	location  *fileLocation
+}
+
+// inodeState is an inode as it was before the commit.
+// The content and the versions hold their own references to the blocks.
+type inodeState struct {
+	store                *blockStore
+	content              *blockList
+	encoding             blockEncoding
+	encrypted            bool
+	links                int
+	modTime              time.Time
+	changeTime           time.Time
+	checksums            map[file.ChecksumAlgorithm][]byte
+	versions             []*fileVersion
+	nextVersionID        int
+	modifiedSinceVersion bool
+}
+
+// beginJournal starts recording the changes.
+// The caller must hold the lock.
+func (fs *MemoryFileSystem) beginJournal() {
+	trash := make(map[string][]*trashEntry, len(fs.trash))
+	for user, entries := range fs.trash {
+		trash[user] = append([]*trashEntry{}, entries...)
+	}
+	fs.journal = &journal{
+		entries:    map[*inMemoryFile]*entryState{},
+		inodes:     map[*inMemoryFileData]*inodeState{},
+		created:    map[*inMemoryFileData]bool{},
+		nextOrphan: fs.openFiles.nextOrphan,
+		trash:      trash,
+		lastIno:    fs.lastIno,
+	}
+}
+
+// endJournal stops recording the changes and keeps them.
+// The caller must hold the lock.
+func (fs *MemoryFileSystem) endJournal() {
+	for _, state := range fs.journal.inodes {
+		state.release()
+	}
+	fs.journal = nil
+}
+
+// undoJournal stops recording the changes and restores the recorded state.
+// The caller must hold the lock.
+func (fs *MemoryFileSystem) undoJournal() {
+	j := fs.journal
+	fs.journal = nil
+
+	for f, state := range j.entries {
+		f.setInode(state.data)
+		f.isDeleted = state.isDeleted
+		f.fileMap = state.fileMap
+		f.names = state.names
+		f.info.location.Store(state.location)
+	}
+	for d, state := range j.inodes {
+		state.restore(d)
+	}
+	for d := range j.created {
+		d.Lock()
+		d.content.release(d.store)
+		d.releaseVersions()
+		d.Unlock()
+	}
+
+	fs.openFiles.Lock()
+	for _, d := range j.orphans {
+		delete(fs.openFiles.orphans, d)
+	}
+	fs.openFiles.nextOrphan = j.nextOrphan
+	fs.openFiles.Unlock()
+
+	fs.trash = j.trash
+	fs.lastIno = j.lastIno
+	fs.pathCache.invalidate()
+}
+
+// saveEntry records the entry before its first change
+func (j *journal) saveEntry(f *inMemoryFile) {
+	if j == nil {
+		return
+	}
+	if _, found := j.entries[f]; found {
+		return
+	}
+	fileMap := make(map[string]*inMemoryFile, len(f.fileMap))
+	for key, child := range f.fileMap {
+		fileMap[key] = child
+	}
+	j.entries[f] = &entryState{
+		data:      f.data,
+		isDeleted: f.isDeleted,
+		fileMap:   fileMap,
+		names:     append([]string(nil), f.names...),
+		location:  f.info.location.Load(),
+	}
+}
+
+// saveInode records the inode before its first change.
+// The inodes created during the commit are released on undo instead.
+// This implementation is thread safe.
+func (j *journal) saveInode(d *inMemoryFileData) {
+	if j == nil || j.created[d] {
+		return
+	}
+	if _, found := j.inodes[d]; found {
+		return
+	}
+
+	d.RLock()
+	defer d.RUnlock()
+	checksums := make(map[file.ChecksumAlgorithm][]byte, len(d.checksums))
+	for algorithm, sum := range d.checksums {
+		checksums[algorithm] = sum
+	}
+	versions := make([]*fileVersion, 0, len(d.versions))
+	for _, v := range d.versions {
+		versions = append(versions, &fileVersion{Version: v.Version, content: v.content.clone(d.store)})
+	}
+	j.inodes[d] = &inodeState{
+		store:                d.store,
+		content:              d.content.clone(d.store),
+		encoding:             d.encoding,
+		encrypted:            d.encrypted,
+		links:                d.links,
+		modTime:              d.modTime,
+		changeTime:           d.changeTime,
+		checksums:            checksums,
+		versions:             versions,
+		nextVersionID:        d.nextVersionID,
+		modifiedSinceVersion: d.modifiedSinceVersion,
+	}
+}
+
+// addCreated records an inode created during the commit
+func (j *journal) addCreated(d *inMemoryFileData) {
+	if j != nil {
+		j.created[d] = true
+	}
+}
+
+// addOrphan records an orphan added during the commit
+func (j *journal) addOrphan(d *inMemoryFileData) {
+	if j != nil {
+		j.orphans = append(j.orphans, d)
+	}
+}
+
+// restore replaces the inode state with the recorded one,
+// releasing the blocks referenced by the current content
+func (s *inodeState) restore(d *inMemoryFileData) {
+	d.Lock()
+	defer d.Unlock()
+	d.content.release(d.store)
+	d.releaseVersions()
+	d.content = s.content
+	d.encoding = s.encoding
+	d.encrypted = s.encrypted
+	d.links = s.links
+	d.modTime = s.modTime
+	d.changeTime = s.changeTime
+	d.checksums = s.checksums
+	d.versions = s.versions
+	d.nextVersionID = s.nextVersionID
+	d.modifiedSinceVersion = s.modifiedSinceVersion
+}
+
+// release releases the blocks referenced by the recorded state
+func (s *inodeState) release() {
+	for _, v := range s.versions {
+		v.content.release(s.store)
+	}
+	s.content.release(s.store)
+}
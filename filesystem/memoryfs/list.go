@@ -2,8 +2,8 @@ package memoryfs
 
 import (
 	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
 	"material/filesystem/filesystem/fspath"
-	"sort"
 )
 
 // ListFiles lists the files at the specified path
@@ -22,20 +22,51 @@ func (fs *MemoryFileSystem) ListFiles(path *fspath.FileSystemPath) ([]file.FileI
 	files := []file.FileInfo{}
 
 	// Get directory to list files
-	dir, err := fs.GetDirectory(path)
+	dir, err := fs.getDirectory(path)
 	if err != nil {
-		return files, err
+		return files, pathError("list", path, err)
 	}
 
-	// List all files
-	err = fs.visitDir(dir.(*inMemoryFile), func(_ string, curr *inMemoryFile) error {
-		files = append(files, curr.Info())
-		return nil
-	})
+	// The directory index is already sorted
+	for _, name := range dir.childNames() {
+		files = append(files, fs.entry(dir, name).Info())
+	}
+	return files, nil
+}
+
+// ListFilesPage lists at most limit files at the specified path, sorted alphabetically,
+// starting after cursor. An empty cursor starts from the first file.
+// Returns the cursor of the next page, empty if there are no more files.
+// The lock is held only while reading the page, and files created or removed
+// between two pages are listed consistently with their position.
+// This implementation is thead safe.
+//
+// Returns an error when:
+// - the limit is not positive
+// - the target path is not a directory
+// - the target path path does not exist
+func (fs *MemoryFileSystem) ListFilesPage(path *fspath.FileSystemPath, cursor string, limit int) ([]file.FileInfo, string, error) {
+	if limit <= 0 {
+		return nil, "", pathError("list", path, fserrors.ErrInvalid)
+	}
+
+	fs.RLock()
+	defer fs.RUnlock()
+
+	files := []file.FileInfo{}
+	dirFile, err := fs.getDirectory(path)
 	if err != nil {
-		return files, err
+		return files, "", pathError("list", path, err)
 	}
 
-	sort.Sort(ByAbsolutePath(files))
-	return files, nil
+	names := dirFile.namesAfter(cursor, limit)
+	for _, name := range names {
+		files = append(files, fs.entry(dirFile, name).Info())
+	}
+
+	nextCursor := ""
+	if len(names) == limit && names[len(names)-1] != dirFile.names[len(dirFile.names)-1] {
+		nextCursor = names[len(names)-1]
+	}
+	return files, nextCursor, nil
 }
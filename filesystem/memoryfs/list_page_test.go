@@ -0,0 +1,117 @@
+package memoryfs_test
+
+import (
+	"errors"
+	"fmt"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/memoryfs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fileNames returns the names of the files
+func fileNames(files []file.FileInfo) []string {
+	names := []string{}
+	for _, info := range files {
+		names = append(names, info.Name())
+	}
+	return names
+}
+
+func TestListFilesPage(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem, dir *fspath.FileSystemPath)
+	}{
+		{
+			CaseName: "Pages cover every file in order",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, dir *fspath.FileSystemPath) {
+				files, cursor, err := fs.ListFilesPage(dir, "", 2)
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"file0", "file1"}, fileNames(files))
+				assert.Equal(t, "file1", cursor)
+
+				files, cursor, err = fs.ListFilesPage(dir, cursor, 2)
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"file2", "file3"}, fileNames(files))
+				assert.Equal(t, "file3", cursor)
+
+				files, cursor, err = fs.ListFilesPage(dir, cursor, 2)
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"file4"}, fileNames(files))
+				assert.Equal(t, "", cursor)
+			},
+		},
+		{
+			CaseName: "The last full page has no next cursor",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, dir *fspath.FileSystemPath) {
+				files, cursor, err := fs.ListFilesPage(dir, "", 5)
+				assert.Nil(t, err)
+				assert.Len(t, files, 5)
+				assert.Equal(t, "", cursor)
+
+				files, cursor, err = fs.ListFilesPage(dir, "file4", 5)
+				assert.Nil(t, err)
+				assert.Empty(t, files)
+				assert.Equal(t, "", cursor)
+			},
+		},
+		{
+			CaseName: "The cursor survives changes between pages",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, dir *fspath.FileSystemPath) {
+				_, cursor, err := fs.ListFilesPage(dir, "", 2)
+				assert.Nil(t, err)
+
+				p, _ := fspath.NewFileSystemPath("/dir/file1", nil)
+				_, err = fs.Remove(p)
+				assert.Nil(t, err)
+				p, _ = fspath.NewFileSystemPath("/dir/file0a", nil)
+				_, err = fs.CreateRegularFile(p)
+				assert.Nil(t, err)
+				p, _ = fspath.NewFileSystemPath("/dir/file2a", nil)
+				_, err = fs.CreateRegularFile(p)
+				assert.Nil(t, err)
+
+				files, _, err := fs.ListFilesPage(dir, cursor, 10)
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"file2", "file2a", "file3", "file4"}, fileNames(files))
+			},
+		},
+		{
+			CaseName: "Invalid limit and path",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, dir *fspath.FileSystemPath) {
+				_, _, err := fs.ListFilesPage(dir, "", 0)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalid))
+
+				p, _ := fspath.NewFileSystemPath("/dir/file0", nil)
+				_, _, err = fs.ListFilesPage(p, "", 10)
+				assert.True(t, errors.Is(err, fserrors.ErrNotDir))
+
+				p, _ = fspath.NewFileSystemPath("/missing", nil)
+				_, _, err = fs.ListFilesPage(p, "", 10)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.CaseName, func(t *testing.T) {
+			fs := newFileSystem()
+			dir, _ := fspath.NewFileSystemPath("/dir", nil)
+			if _, err := fs.Mkdir(dir); err != nil {
+				t.Fatal(err)
+			}
+			// created out of order, to check the index
+			for _, i := range []int{3, 0, 4, 1, 2} {
+				p, _ := fspath.NewFileSystemPath(fmt.Sprintf("/dir/file%d", i), nil)
+				if _, err := fs.CreateRegularFile(p); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c.Assertions(t, fs, dir)
+		})
+	}
+}
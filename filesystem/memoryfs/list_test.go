@@ -23,7 +23,7 @@ func TestListFiles(t *testing.T) {
 			CaseName: "List files in / - absolute path",
 			Path:     "/",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir3/dir4", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -82,7 +82,7 @@ func TestListFiles(t *testing.T) {
 			CaseName: "List files in subdirectory - absolute path",
 			Path:     "/dir2/dir1/",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir3/dir4", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -142,7 +142,7 @@ func TestListFiles(t *testing.T) {
 			CaseName: "No such file or directory - absolute path",
 			Path:     "/dir2/dir10/",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir3/dir4", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -161,7 +161,7 @@ func TestListFiles(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 				assert.NotNil(t, files)
 				assert.Len(t, files, 0)
 			},
@@ -170,7 +170,7 @@ func TestListFiles(t *testing.T) {
 			CaseName: "Listing regular file - absolute path",
 			Path:     "/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -181,7 +181,7 @@ func TestListFiles(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrInvalidFileType)
+				assert.True(t, errors.Is(err, fserrors.ErrNotDir))
 				assert.NotNil(t, files)
 				assert.Len(t, files, 0)
 			},
@@ -190,7 +190,7 @@ func TestListFiles(t *testing.T) {
 			CaseName: "List files in / - relative path",
 			Path:     "../../../../../../..",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir3/dir4", nil)
 				workDir, err := fs.MkdirAll(p)
 				if err != nil {
@@ -250,7 +250,7 @@ func TestListFiles(t *testing.T) {
 			CaseName: "List files in subdirectory - relative path",
 			Path:     "../../../dir2/dir1/",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir3/dir4", nil)
 				workDir, err := fs.MkdirAll(p)
 				if err != nil {
@@ -311,7 +311,7 @@ func TestListFiles(t *testing.T) {
 			CaseName: "List files in symlink - relative path",
 			Path:     "../../../dir2/dir1/dir5/dir6",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir3/dir4", nil)
 				workDir, err := fs.MkdirAll(p)
 				if err != nil {
@@ -378,7 +378,7 @@ func TestListFiles(t *testing.T) {
 			CaseName: "No such file or directory - relative path",
 			Path:     "dir10/",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir3/dir4", nil)
 				workDir, err := fs.MkdirAll(p)
 				if err != nil {
@@ -398,7 +398,7 @@ func TestListFiles(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 				assert.NotNil(t, files)
 				assert.Len(t, files, 0)
 			},
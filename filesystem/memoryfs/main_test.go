@@ -0,0 +1,35 @@
+package memoryfs_test
+
+import (
+	"fmt"
+	"material/filesystem/filesystem/memoryfs"
+	"os"
+	"testing"
+)
+
+// caseInsensitive selects the mode of the file systems created by newFileSystem
+var caseInsensitive bool
+
+// newFileSystem creates an empty file system in the mode under test
+func newFileSystem(opts ...memoryfs.Option) *memoryfs.MemoryFileSystem {
+	if caseInsensitive {
+		opts = append(opts, memoryfs.WithCaseInsensitiveNames())
+	}
+	fs, err := memoryfs.NewMemoryFileSystem(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return fs
+}
+
+// TestMain runs every test twice, with a case-sensitive
+// and with a case-insensitive file system
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if code == 0 {
+		fmt.Println("running the tests with a case-insensitive file system")
+		caseInsensitive = true
+		code = m.Run()
+	}
+	os.Exit(code)
+}
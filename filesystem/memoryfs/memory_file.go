@@ -4,21 +4,45 @@ import (
 	"material/filesystem/filesystem/file"
 	"material/filesystem/filesystem/fspath"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
-// inMemoryFile implements the FileInfo interface
+// inMemoryFile implements the FileInfo interface.
+// It's the directory entry: the type and the metadata belong to the inode.
+// The absolute path is derived from the parent directories, so moving
+// a directory doesn't update its content.
 type inMemoryFileInfo struct {
-	absolutePath string
-	fileType     file.FileType
+	inode *inMemoryFileData
+	// position in the tree, replaced as a whole when the file is moved,
+	// so that the path can be read without holding the fs lock
+	location atomic.Pointer[fileLocation]
+}
+
+// fileLocation is the name of a file in its parent directory
+type fileLocation struct {
+	name string
+	// parent directory, nil for the root and for the detached files
+	parent *inMemoryFileInfo
+	// absolute path of the root, or of a detached file when it was detached
+	path string
 }
 
-// inMemoryFile implements the File interface
+// inMemoryFile implements the File interface.
+// It's a directory entry, hard links are entries sharing the same inode.
 type inMemoryFile struct {
 	info      *inMemoryFileInfo
 	data      *inMemoryFileData
 	isDeleted bool
+	// true if the file is mounted from a virtual provider
+	isVirtual bool
 	fileMap   map[string]*inMemoryFile
-	link      *fspath.FileSystemPath
+	// names of the directory entries in lexical order, without the special ones
+	names []string
+	link  *fspath.FileSystemPath
+	// entries copied on first access, nil for the directories outside of the snapshots
+	lazy *lazyDir
 }
 
 // Implement sort interface
@@ -44,33 +68,155 @@ func (f inMemoryFile) Data() file.FileData {
 }
 
 func (info *inMemoryFileInfo) FileType() file.FileType {
-	return info.fileType
+	return info.inode.fileType
 }
 
 func (info *inMemoryFileInfo) Name() string {
-	return filepath.Base(info.absolutePath)
+	return info.location.Load().name
 }
 
+// AbsolutePath joins the names from the root, or from the detached
+// directory containing the file, in O(depth).
 func (info *inMemoryFileInfo) AbsolutePath() string {
-	return info.absolutePath
+	loc := info.location.Load()
+	if loc.parent == nil {
+		return loc.path
+	}
+
+	names := []string{}
+	for ; loc.parent != nil; loc = loc.parent.location.Load() {
+		names = append(names, loc.name)
+	}
+	var b strings.Builder
+	b.WriteString(loc.path)
+	for i := len(names) - 1; i >= 0; i-- {
+		if !strings.HasSuffix(loc.path, "/") || i < len(names)-1 {
+			b.WriteByte('/')
+		}
+		b.WriteString(names[i])
+	}
+	return b.String()
+}
+
+// setParent places the file in the parent directory, keeping its name
+func (info *inMemoryFileInfo) setParent(parent *inMemoryFileInfo) {
+	info.location.Store(&fileLocation{name: info.Name(), parent: parent})
 }
 
-func newInMemoryFile(absolutePath string, fileType file.FileType) *inMemoryFile {
+// setDetached places the file outside of the tree at the given absolute path.
+// The file takes the name from the path, and its content is located under it.
+func (info *inMemoryFileInfo) setDetached(absolutePath string) {
+	info.location.Store(&fileLocation{name: filepath.Base(absolutePath), path: absolutePath})
+}
+
+func newInMemoryFile(absolutePath string, fileType file.FileType, store *blockStore, clock func() time.Time) *inMemoryFile {
+	data := newInMemoryFileData(store, clock)
+	data.fileType = fileType
+	if fileType == file.Fifo {
+		data.pipe = newPipe()
+	}
+	return newEntry(absolutePath, data)
+}
+
+// newEntry creates a detached entry of an existing inode
+func newEntry(absolutePath string, inode *inMemoryFileData) *inMemoryFile {
 	info := &inMemoryFileInfo{
-		absolutePath: absolutePath,
-		fileType:     fileType,
+		inode: inode,
 	}
+	info.setDetached(absolutePath)
 
 	newFile := &inMemoryFile{
 		info:    info,
-		data:    &inMemoryFileData{},
+		data:    inode,
 		fileMap: map[string]*inMemoryFile{},
 	}
-
-	if fileType == file.RegularFile {
-		newFile.data = &inMemoryFileData{}
-	}
-
 	newFile.fileMap["."] = newFile
 	return newFile
 }
+
+// newFile creates a detached file with a new inode.
+// This method should be called only if the caller has already acquired a lock
+func (fs *MemoryFileSystem) newFile(absolutePath string, fileType file.FileType) *inMemoryFile {
+	newFile := newInMemoryFile(absolutePath, fileType, fs.blocks, fs.clock)
+	fs.lastIno++
+	newFile.data.ino = fs.lastIno
+	fs.journal.addCreated(newFile.data)
+	fs.snapshotState.addInode(newFile.data)
+	return newFile
+}
+
+// setInode makes the entry a hard link to the inode
+func (f *inMemoryFile) setInode(inode *inMemoryFileData) {
+	f.data = inode
+	f.info.inode = inode
+}
+
+// inMemoryFileStat implements the FileStat interface.
+// It's a snapshot of the file attributes.
+type inMemoryFileStat struct {
+	name         string
+	fileType     file.FileType
+	absolutePath string
+	size         int
+	modTime      time.Time
+	changeTime   time.Time
+	links        int
+	ino          uint64
+	// memory used by the content after compression
+	physicalSize int
+}
+
+func (stat *inMemoryFileStat) Name() string {
+	return stat.name
+}
+
+func (stat *inMemoryFileStat) FileType() file.FileType {
+	return stat.fileType
+}
+
+func (stat *inMemoryFileStat) AbsolutePath() string {
+	return stat.absolutePath
+}
+
+func (stat *inMemoryFileStat) Size() int {
+	return stat.size
+}
+
+func (stat *inMemoryFileStat) ModTime() time.Time {
+	return stat.modTime
+}
+
+func (stat *inMemoryFileStat) ChangeTime() time.Time {
+	return stat.changeTime
+}
+
+func (stat *inMemoryFileStat) Links() int {
+	return stat.links
+}
+
+func (stat *inMemoryFileStat) Inode() uint64 {
+	return stat.ino
+}
+
+func (stat *inMemoryFileStat) PhysicalSize() int {
+	return stat.physicalSize
+}
+
+// newInMemoryFileStat creates a snapshot of the file attributes.
+// This implementation is thread safe.
+func newInMemoryFileStat(f *inMemoryFile) *inMemoryFileStat {
+	f.data.RLock()
+	defer f.data.RUnlock()
+
+	return &inMemoryFileStat{
+		name:         f.info.Name(),
+		fileType:     f.data.fileType,
+		absolutePath: f.info.AbsolutePath(),
+		size:         f.data.Size(),
+		modTime:      f.data.modTime,
+		changeTime:   f.data.changeTime,
+		links:        f.data.links,
+		ino:          f.data.ino,
+		physicalSize: f.data.content.physicalSize(),
+	}
+}
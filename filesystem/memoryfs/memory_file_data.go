@@ -1,71 +1,283 @@
 package memoryfs
 
-import "sync"
+import (
+	"hash"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fscrypt"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fsversion"
+	"material/filesystem/filesystem/fsvirtual"
+	"sync"
+	"time"
+)
 
-// inMemoryFile implements the FileData interface
+// inMemoryFileData is the inode of a file, shared by its hard links:
+// type, metadata and content. It implements the FileData interface.
 type inMemoryFileData struct {
-	// TODO: Optimization. make this a slice of slice of fixed size so that
-	// expanding size/inserting is more efficient because you just need to copy
-	// the pointers to the slices
-	data []byte
+	// inode number, unique in the file system
+	ino      uint64
+	fileType file.FileType
+	// store holding the content blocks, shared by the whole file system
+	store *blockStore
+	// content split in deduplicated blocks
+	content *blockList
+	// compression and encryption of the blocks
+	encoding blockEncoding
+	// true if the content is encrypted or, for directories,
+	// if new files are encrypted
+	encrypted bool
+	// number of directory entries pointing to the inode
+	links int
+	// number of open file descriptors
+	openDescriptors int
+	// last time the content was modified
+	modTime time.Time
+	// last time the content or the attributes were modified
+	changeTime time.Time
+	// cached digests of the content, cleared on every write
+	checksums map[file.ChecksumAlgorithm][]byte
+	// snapshots of the content, oldest first
+	versions []*fileVersion
+	// id of the next version
+	nextVersionID int
+	// true if the content was modified after the last version
+	modifiedSinceVersion bool
+	// buffer shared by the readers and writers of a fifo, nil for the other file types
+	pipe *pipe
+	// provider of the content of a virtual file, nil for the other files
+	virtual fsvirtual.File
+	// time source of the timestamps, shared by the whole file system
+	clock func() time.Time
 	sync.RWMutex
 }
 
+func newInMemoryFileData(store *blockStore, clock func() time.Time) *inMemoryFileData {
+	now := clock()
+	return &inMemoryFileData{
+		store:         store,
+		content:       &blockList{},
+		links:         1,
+		modTime:       now,
+		changeTime:    now,
+		nextVersionID: 1,
+		clock:         clock,
+	}
+}
+
 func (data *inMemoryFileData) Data() []byte {
-	return data.data
+	content, _ := data.content.bytes()
+	return content
 }
 
 func (data *inMemoryFileData) Size() int {
-	return len(data.data)
+	return data.content.size
 }
 
-// write writes the content at the given offset.
-// If the offset > len(data) fill the gap with 0s.
-func (d *inMemoryFileData) write(content []byte, offset int) int {
+// touch updates the modification and change time.
+// This implementation is thread safe.
+func (d *inMemoryFileData) touch() {
+	d.Lock()
+	defer d.Unlock()
+	d.modTime = d.clock()
+	d.changeTime = d.modTime
+}
 
-	if offset == len(d.data) {
-		return d.append(content)
+// updateLinks adds delta to the number of links and updates the change time.
+// This implementation is thread safe.
+func (d *inMemoryFileData) updateLinks(delta int) {
+	d.Lock()
+	defer d.Unlock()
+	d.links += delta
+	d.changeTime = d.clock()
+	d.releaseIfUnreachable()
+}
+
+// open registers a new open file descriptor.
+// This implementation is thread safe.
+func (d *inMemoryFileData) open() {
+	d.Lock()
+	defer d.Unlock()
+	d.openDescriptors++
+}
+
+// close unregisters an open file descriptor and, if the content was modified,
+// captures a new version according to the policy.
+// Returns true if the content has been released.
+// This implementation is thread safe.
+func (d *inMemoryFileData) close(policy fsversion.Policy) bool {
+	d.Lock()
+	defer d.Unlock()
+	d.openDescriptors--
+	if d.links > 0 && d.modifiedSinceVersion {
+		d.captureVersion(policy)
 	}
+	return d.releaseIfUnreachable()
+}
 
-	if offset > len(d.data) {
-		// fill with 0s
-		size := offset - len(d.data)
-		empty := make([]byte, size-1)
-		d.append(empty)
-		return d.append(content)
+// releaseIfUnreachable releases the content and versions blocks once every link
+// has been removed and every descriptor has been closed.
+// Returns true if the content has been released.
+func (d *inMemoryFileData) releaseIfUnreachable() bool {
+	if d.links <= 0 && d.openDescriptors <= 0 {
+		d.content.release(d.store)
+		d.releaseVersions()
+		return true
 	}
+	return false
+}
+
+// copyFrom makes the content a copy of src content.
+// Blocks are shared and copied only when written.
+// An encrypted content is encrypted again with a new data key,
+// so that every file has its own key.
+// This implementation is thread safe.
+func (d *inMemoryFileData) copyFrom(src *inMemoryFileData, keys fscrypt.KeyProvider) error {
+	src.RLock()
+	content := src.content.clone(d.store)
+	encoding, encrypted := src.encoding, src.encrypted
+	src.RUnlock()
+
+	if encoding.dataKey != nil {
+		key, err := newDataKey(keys)
+		if err != nil {
+			content.release(d.store)
+			return err
+		}
+		encoding.dataKey = key
+		if err := content.reencode(d.store, encoding); err != nil {
+			content.release(d.store)
+			return err
+		}
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	d.content.release(d.store)
+	d.content = content
+	d.encoding, d.encrypted = encoding, encrypted
+	return nil
+}
+
+// markChanged updates the change time.
+// This implementation is thread safe.
+func (d *inMemoryFileData) markChanged() {
+	d.Lock()
+	defer d.Unlock()
+	d.changeTime = d.clock()
+}
 
-	return d.insert(content, offset)
+// setCompression changes the compression of the new blocks and
+// recompresses the existing ones.
+// This implementation is thread safe.
+func (d *inMemoryFileData) setCompression(compression file.Compression) error {
+	d.Lock()
+	defer d.Unlock()
+	return d.setEncoding(blockEncoding{compression: compression, dataKey: d.encoding.dataKey})
 }
 
-func (d *inMemoryFileData) read(start int, buff []byte) int {
-	if start >= len(d.data) {
-		return 0
+// setEncoding changes the encoding of the new blocks and reencodes the existing ones
+func (d *inMemoryFileData) setEncoding(encoding blockEncoding) error {
+	if d.encoding == encoding {
+		return nil
+	}
+	if err := d.content.reencode(d.store, encoding); err != nil {
+		return err
+	}
+	// old versions must not stay readable with a previous key
+	for _, v := range d.versions {
+		if err := v.content.reencode(d.store, encoding); err != nil {
+			return err
+		}
 	}
+	d.encoding = encoding
+	d.changeTime = d.clock()
+	return nil
+}
+
+// write writes the content at the given offset.
+// If the offset > len(data) fill the gap with 0s.
+// Fails with ErrFileTooLarge if the new size exceeds the maximum file size.
+func (d *inMemoryFileData) write(content []byte, offset int) (int, error) {
+	newSize := d.content.size + len(content)
+	if offset > d.content.size {
+		// the gap is filled below
+		newSize += offset - d.content.size - 1
+	}
+	if d.store.maxFileSize > 0 && newSize > d.store.maxFileSize {
+		return 0, fserrors.ErrFileTooLarge
+	}
+
+	d.modTime = d.clock()
+	d.changeTime = d.modTime
+	d.checksums = nil
+	d.modifiedSinceVersion = true
 
-	end := start + len(buff)
-	if end >= len(d.data) {
-		end = len(d.data)
+	size := d.content.size
+	if offset > size {
+		// fill with a hole
+		d.content.appendHole(offset - size - 1)
+		offset = d.content.size
 	}
 
-	copy(buff, d.data[start:end])
-	return end - start
+	if err := d.content.insert(d.store, content, offset, d.encoding); err != nil {
+		return 0, err
+	}
+	return len(content), nil
+}
+
+func (d *inMemoryFileData) read(start int, buff []byte) (int, error) {
+	return d.content.read(start, buff)
 }
 
-// TODO: improve, see optimization above
-func (d *inMemoryFileData) insert(content []byte, pos int) int {
-	// reslice
-	newData := make([]byte, 0, len(d.data)+len(content))
-	// insert data
-	newData = append(newData, d.data[:pos]...)
-	newData = append(newData, content...)
-	newData = append(newData, d.data[pos:]...)
+// punchHole replaces the content in [offset, offset+length) with a hole
+func (d *inMemoryFileData) punchHole(offset int, length int) error {
+	if err := d.content.punchHole(d.store, offset, length, d.encoding); err != nil {
+		return err
+	}
+	d.modTime = d.clock()
+	d.changeTime = d.modTime
+	d.checksums = nil
+	d.modifiedSinceVersion = true
+	return nil
+}
 
-	d.data = newData
-	return len(content)
+// usage returns the size and the allocated bytes.
+// This implementation is thread safe.
+func (d *inMemoryFileData) usage() (int, int) {
+	d.RLock()
+	defer d.RUnlock()
+	return d.content.size, d.content.physicalSize()
 }
 
-func (d *inMemoryFileData) append(content []byte) int {
-	d.data = append(d.data, content...)
-	return len(content)
+// isOrphan returns true if every link to the data has been removed
+// while it is still open.
+// This implementation is thread safe.
+func (d *inMemoryFileData) isOrphan() bool {
+	d.RLock()
+	defer d.RUnlock()
+	return d.links <= 0 && d.openDescriptors > 0
+}
+
+// checksum returns the digest of the content, computing it only if
+// it's not cached.
+// This implementation is thread safe.
+func (d *inMemoryFileData) checksum(algorithm file.ChecksumAlgorithm, newHash func() hash.Hash) ([]byte, error) {
+	d.Lock()
+	defer d.Unlock()
+
+	if digest, found := d.checksums[algorithm]; found {
+		return append([]byte{}, digest...), nil
+	}
+
+	h := newHash()
+	if err := d.content.writeTo(h); err != nil {
+		return nil, err
+	}
+	digest := h.Sum(nil)
+
+	if d.checksums == nil {
+		d.checksums = map[file.ChecksumAlgorithm][]byte{}
+	}
+	d.checksums[algorithm] = digest
+	return append([]byte{}, digest...), nil
 }
@@ -1,36 +1,143 @@
 package memoryfs
 
+import (
+	"context"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fsvirtual"
+)
+
 type fileDescriptor struct {
 	data   *inMemoryFileData
 	offset int
+	flags  file.OpenFlag
+	// handle of a virtual file, nil for the other files
+	handle fsvirtual.Handle
+	// owner of the descriptor, e.g. a session, empty if unknown
+	owner string
+}
+
+// size returns the size of the content
+func (fd *fileDescriptor) size() int {
+	if fd.handle != nil {
+		return fd.handle.Size()
+	}
+	return fd.data.Size()
 }
 
 // Read reads at most len(buff) bytes from the file
 // starting at the current offset
 func (fd *fileDescriptor) Read(buff []byte) (int, error) {
-	nRead := fd.data.read(fd.offset, buff)
+	return fd.ReadContext(context.Background(), buff)
+}
+
+// ReadContext reads like Read, giving up waiting for the data of a fifo when ctx is done
+func (fd *fileDescriptor) ReadContext(ctx context.Context, buff []byte) (int, error) {
+	if fd.flags&file.OpenRead == 0 {
+		return 0, fserrors.ErrBadDescriptor
+	}
+	if fd.data.pipe != nil {
+		return fd.data.pipe.read(ctx, buff, fd.flags&file.OpenNonBlocking != 0)
+	}
+
+	var nRead int
+	var err error
+	if fd.handle != nil {
+		nRead, err = fd.handle.ReadAt(buff, fd.offset)
+	} else {
+		nRead, err = fd.data.read(fd.offset, buff)
+	}
 	fd.offset += nRead
-	return nRead, nil
+	return nRead, err
 }
 
 // ReadAt reads at most len(buff) bytes from the file
 // starting at the given offset.
 func (fd *fileDescriptor) ReadAt(buff []byte, offset int) (int, error) {
-	nRead := fd.data.read(offset, buff)
-	return nRead, nil
+	if fd.flags&file.OpenRead == 0 {
+		return 0, fserrors.ErrBadDescriptor
+	}
+	if fd.data.pipe != nil {
+		return 0, fserrors.ErrInvalid
+	}
+	if fd.handle != nil {
+		return fd.handle.ReadAt(buff, offset)
+	}
+	return fd.data.read(offset, buff)
 }
 
 // Write writes len(buff) bytes to the file
 // starting at the current offset
 func (fd *fileDescriptor) Write(buff []byte) (int, error) {
-	nWrite := fd.data.write(buff, fd.offset)
+	if fd.flags&file.OpenWrite == 0 {
+		return 0, fserrors.ErrBadDescriptor
+	}
+	if fd.data.pipe != nil {
+		return fd.data.pipe.write(buff, fd.flags&file.OpenNonBlocking != 0)
+	}
+
+	var nWrite int
+	var err error
+	if fd.handle != nil {
+		nWrite, err = fd.handle.WriteAt(buff, fd.offset)
+	} else {
+		nWrite, err = fd.data.write(buff, fd.offset)
+	}
 	fd.offset += nWrite
-	return nWrite, nil
+	return nWrite, err
 }
 
 // WriteAt writes len(buff) bytes to the file
 // starting at the given offset
 func (fd *fileDescriptor) WriteAt(buff []byte, offset int) (int, error) {
-	nWrite := fd.data.write(buff, offset)
-	return nWrite, nil
+	if fd.flags&file.OpenWrite == 0 {
+		return 0, fserrors.ErrBadDescriptor
+	}
+	if fd.data.pipe != nil {
+		return 0, fserrors.ErrInvalid
+	}
+	if fd.handle != nil {
+		return fd.handle.WriteAt(buff, offset)
+	}
+	return fd.data.write(buff, offset)
+}
+
+// SeekData moves the offset to the first position >= offset
+// which is not in a hole and returns it
+func (fd *fileDescriptor) SeekData(offset int) (int, error) {
+	if fd.data.pipe != nil || fd.handle != nil {
+		return 0, fserrors.ErrInvalid
+	}
+	pos, found := fd.data.content.seekData(offset)
+	if !found {
+		return 0, fserrors.ErrOffsetOutOfRange
+	}
+	fd.offset = pos
+	return pos, nil
+}
+
+// SeekHole moves the offset to the first position >= offset
+// in a hole and returns it. The end of the file is a hole.
+func (fd *fileDescriptor) SeekHole(offset int) (int, error) {
+	if fd.data.pipe != nil || fd.handle != nil {
+		return 0, fserrors.ErrInvalid
+	}
+	pos, found := fd.data.content.seekHole(offset)
+	if !found {
+		return 0, fserrors.ErrOffsetOutOfRange
+	}
+	fd.offset = pos
+	return pos, nil
+}
+
+// PunchHole deallocates length bytes starting at offset.
+// The range reads as zeros and the size doesn't change.
+func (fd *fileDescriptor) PunchHole(offset int, length int) error {
+	if fd.flags&file.OpenWrite == 0 {
+		return fserrors.ErrBadDescriptor
+	}
+	if fd.data.pipe != nil || fd.handle != nil {
+		return fserrors.ErrInvalid
+	}
+	return fd.data.punchHole(offset, length)
 }
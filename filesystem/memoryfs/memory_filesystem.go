@@ -2,17 +2,29 @@ package memoryfs
 
 import (
 	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fscrypt"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fsname"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fstrash"
+	"material/filesystem/filesystem/fsversion"
 	"sync"
+	"time"
 )
 
 type fileTable struct {
 	table map[string]*fileDescriptor
+	// deleted files that are still open
+	orphans map[*inMemoryFileData]*orphan
+	// sequence number of the next orphan
+	nextOrphan int
 	sync.RWMutex
 }
 
 func newFileTable() *fileTable {
 	return &fileTable{
-		table: map[string]*fileDescriptor{},
+		table:   map[string]*fileDescriptor{},
+		orphans: map[*inMemoryFileData]*orphan{},
 	}
 }
 
@@ -24,17 +36,79 @@ type MemoryFileSystem struct {
 	root *inMemoryFile
 	// table of open files
 	openFiles *fileTable
+	// deduplicated store of the file contents
+	blocks *blockStore
+	// provider of the master keys, nil if encryption is not available
+	keys fscrypt.KeyProvider
+	// trashed files by user
+	trash map[string][]*trashEntry
+	// limits applied to every trash
+	trashRetention fstrash.Retention
+	// limits of the regular file versions
+	versionPolicy fsversion.Policy
+	// true if names that differ only in case refer to the same file
+	caseInsensitive bool
+	// restrictions on the names and paths of the new files
+	namePolicy fsname.Policy
+	// time source of the timestamps
+	clock func() time.Time
+	// maximum number of symbolic links followed to resolve a path
+	maxLinkDepth int
+	// resolved directories by absolute path
+	pathCache *pathCache
+	// inode number of the last created file
+	lastIno uint64
+	// changes of the commit in progress, nil outside of commits
+	journal *journal
+	// state shared by the files of a transaction snapshot, nil for the other file systems
+	snapshotState *snapshotState
 }
 
-func NewMemoryFileSystem() *MemoryFileSystem {
-	// TODO: make root configurable
-	root := newInMemoryFile("/", file.Directory)
+// NewMemoryFileSystem creates an empty file system configured with the given options.
+//
+// Returns an error when:
+// - an option has an invalid value
+// - a directory of the layout can't be created
+func NewMemoryFileSystem(opts ...Option) (*MemoryFileSystem, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	blocks := newBlockStore(o.capacity, o.maxFileSize)
+	root := newInMemoryFile("/", file.Directory, blocks, o.clock)
+	root.data.ino = 1
+	if o.rootName != "/" {
+		root.info.location.Store(&fileLocation{name: o.rootName, path: "/"})
+	}
 	root.fileMap[".."] = root
 	root.fileMap["."] = root
 	root.fileMap["/"] = root
 
-	return &MemoryFileSystem{
-		root:      root,
-		openFiles: newFileTable(),
+	fs := &MemoryFileSystem{
+		root:            root,
+		openFiles:       newFileTable(),
+		blocks:          blocks,
+		trash:           map[string][]*trashEntry{},
+		caseInsensitive: o.caseInsensitive,
+		namePolicy:      o.namePolicy,
+		clock:           o.clock,
+		maxLinkDepth:    o.maxLinkDepth,
+		pathCache:       newPathCache(o.pathCacheSize),
+		lastIno:         root.data.ino,
+	}
+
+	for _, dir := range o.layout {
+		path, err := fspath.NewFileSystemPath(dir, nil)
+		if err != nil {
+			return nil, fserrors.ErrInvalid
+		}
+		if _, err := fs.MkdirAll(path); err != nil {
+			return nil, err
+		}
 	}
+	return fs, nil
 }
@@ -4,11 +4,29 @@ import (
 	"material/filesystem/filesystem/file"
 	"material/filesystem/filesystem/fserrors"
 	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsreport"
 	"path/filepath"
 )
 
-type onMoveOrCopyDestFound func(fileToMove *inMemoryFile, dest *inMemoryFile, isCopy bool) (*inMemoryFile, error)
-type onMoveOrCopyDestNotFound func(fileToMove *inMemoryFile, dest *inMemoryFile, newName string, isCopy bool) (*inMemoryFile, error)
+type onMoveOrCopyDestFound func(fileToMove *inMemoryFile, dest *inMemoryFile, isCopy bool, state *copyState) (*inMemoryFile, error)
+type onMoveOrCopyDestNotFound func(fileToMove *inMemoryFile, dest *inMemoryFile, newName string, isCopy bool, state *copyState) (*inMemoryFile, error)
+
+// copyState is the state of a recursive move or copy
+type copyState struct {
+	r *reporter
+	// copies of the inodes already copied, nil if the copy doesn't preserve the hard links
+	links map[*inMemoryFileData]*inMemoryFileData
+}
+
+// newCopyState returns the state of a move or copy reporting to r, which links
+// the copies of the files hard linked to each other in the copied tree if preserveLinks is true
+func newCopyState(r *reporter, preserveLinks bool) *copyState {
+	state := &copyState{r: r}
+	if preserveLinks {
+		state.links = map[*inMemoryFileData]*inMemoryFileData{}
+	}
+	return state
+}
 
 // Move moves (renames) srcPath to destPath and creates
 // any parent directories.
@@ -50,53 +68,117 @@ func (fs *MemoryFileSystem) Copy(srcPath *fspath.FileSystemPath, destPath *fspat
 	return fs.moveOrCopy(srcPath, destPath, true)
 }
 
+// MoveWithReport moves (renames) srcPath to destPath like Move,
+// and reports the moved files and the failures.
+// If continueOnError is true, it moves every file it can: the files that
+// can't be moved are kept in the source directory.
+// The file info is returned when srcPath has been moved, even if some
+// of the files in it failed. The returned error is the first failure.
+// This implementation is thread safe
+func (fs *MemoryFileSystem) MoveWithReport(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath, continueOnError bool) (file.FileInfo, *fsreport.Report, error) {
+	return fs.moveOrCopyWithReport(srcPath, destPath, false, false, newReporter(continueOnError))
+}
+
+// CopyWithReport copies srcPath to destPath like Copy,
+// and reports the copied files and the failures.
+// If continueOnError is true, it copies every file it can skipping
+// the files that can't be copied.
+// The file info is returned when srcPath has been copied, even if some
+// of the files in it failed. The returned error is the first failure.
+// This implementation is thread safe
+func (fs *MemoryFileSystem) CopyWithReport(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath, continueOnError bool) (file.FileInfo, *fsreport.Report, error) {
+	return fs.moveOrCopyWithReport(srcPath, destPath, true, false, newReporter(continueOnError))
+}
+
+// CopyPreservingLinks copies srcPath to destPath like CopyWithReport,
+// preserving the hard links: the files of srcPath that share an inode
+// share a new inode in the copy. The links to files outside of srcPath
+// are copied as separate files.
+// This implementation is thread safe
+func (fs *MemoryFileSystem) CopyPreservingLinks(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath, continueOnError bool) (file.FileInfo, *fsreport.Report, error) {
+	return fs.moveOrCopyWithReport(srcPath, destPath, true, true, newReporter(continueOnError))
+}
+
 func (fs *MemoryFileSystem) moveOrCopy(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath, isCopy bool) (file.FileInfo, error) {
 	fs.Lock()
 	defer fs.Unlock()
+	return fs.moveOrCopyPathLockFree(srcPath, destPath, isCopy, false, newReporter(false))
+}
+
+func (fs *MemoryFileSystem) moveOrCopyWithReport(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath, isCopy bool, preserveLinks bool, r *reporter) (file.FileInfo, *fsreport.Report, error) {
+	fs.Lock()
+	defer fs.Unlock()
+	info, err := fs.moveOrCopyPathLockFree(srcPath, destPath, isCopy, preserveLinks, r)
+	if err == nil && r.err != nil {
+		err = linkError(moveOrCopyOp(isCopy), srcPath, destPath, r.err)
+	}
+	return info, r.report, err
+}
+
+// moveOrCopyOp returns the operation name recorded in the errors
+func moveOrCopyOp(isCopy bool) string {
+	if isCopy {
+		return "copy"
+	}
+	return "move"
+}
+
+// This method should be called only if the caller has already acquired a lock
+func (fs *MemoryFileSystem) moveOrCopyPathLockFree(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath, isCopy bool, preserveLinks bool, r *reporter) (file.FileInfo, error) {
+	op := moveOrCopyOp(isCopy)
 
 	// find the file/directory that needs to be moved/copied
 	fileToMove, err := fs.traverseToBaseWithSkipLastLink(srcPath, !isCopy)
 	if err != nil {
-		return nil, err
+		return nil, pathError(op, srcPath, err)
 	}
 
-	if fileToMove == fs.root {
-		return nil, fserrors.ErrOperationNotSupported
+	if fileToMove == fs.root || fileToMove.isVirtual {
+		return nil, pathError(op, srcPath, fserrors.ErrOperationNotSupported)
 	}
 
 	// find last directory in the destination path
 	dest, err := fs.traverseDirsAndCreateParentDirs(destPath)
 	if err != nil {
-		return nil, err
+		return nil, pathError(op, destPath, err)
 	}
 
-	newFile, err := fs.moveOrCopyLockFree(fileToMove, dest, destPath.Base(), isCopy)
-	if err != nil {
-		return nil, err
+	// virtual directories can't be changed
+	if finalDest, found := fs.lookup(dest, destPath.Base()); dest.isVirtual || (found && finalDest.isVirtual) {
+		return nil, pathError(op, destPath, fserrors.ErrOperationNotSupported)
+	}
+
+	newFile, err := fs.moveOrCopyLockFree(fileToMove, dest, destPath.Base(), isCopy, newCopyState(r, preserveLinks))
+	if err := r.fail(fileToMove.info.AbsolutePath(), err); err != nil {
+		return nil, linkError(op, srcPath, destPath, err)
+	}
+	// the failure has been reported and the operation continued
+	if newFile == nil {
+		return nil, nil
 	}
 	return newFile.info, nil
 }
 
 // This method should be called only if the caller has already acquired a lock
-func (fs *MemoryFileSystem) moveOrCopyLockFree(fileToMove *inMemoryFile, dest *inMemoryFile, finalDestName string, isCopy bool) (*inMemoryFile, error) {
-	if fileToMove.info.fileType == file.Directory {
-		return fs.moveOrCopyDirectory(fileToMove, dest, finalDestName, isCopy)
+func (fs *MemoryFileSystem) moveOrCopyLockFree(fileToMove *inMemoryFile, dest *inMemoryFile, finalDestName string, isCopy bool, state *copyState) (*inMemoryFile, error) {
+	if fileToMove.data.fileType == file.Directory {
+		return fs.moveOrCopyDirectory(fileToMove, dest, finalDestName, isCopy, state)
 	}
-	return fs.moveOrCopyRegularFile(fileToMove, dest, finalDestName, isCopy)
+	return fs.moveOrCopyRegularFile(fileToMove, dest, finalDestName, isCopy, state)
 }
 
-func (fs *MemoryFileSystem) moveOrCopyDirectory(fileToMove *inMemoryFile, dest *inMemoryFile, finalDestName string, isCopy bool) (*inMemoryFile, error) {
-	return fs.doMoveOrCopy(fileToMove, dest, finalDestName, fs.moveOrCopyDirectoryToExistingDestination, fs.renameAndMoveOrCopyDirectory, isCopy)
+func (fs *MemoryFileSystem) moveOrCopyDirectory(fileToMove *inMemoryFile, dest *inMemoryFile, finalDestName string, isCopy bool, state *copyState) (*inMemoryFile, error) {
+	return fs.doMoveOrCopy(fileToMove, dest, finalDestName, fs.moveOrCopyDirectoryToExistingDestination, fs.renameAndMoveOrCopyDirectory, isCopy, state)
 }
 
-func (fs *MemoryFileSystem) renameAndMoveOrCopyDirectory(fileToMove *inMemoryFile, dest *inMemoryFile, newName string, isCopy bool) (*inMemoryFile, error) {
-	return fs.renameAndMoveOrCopy(fileToMove, dest, newName, isCopy)
+func (fs *MemoryFileSystem) renameAndMoveOrCopyDirectory(fileToMove *inMemoryFile, dest *inMemoryFile, newName string, isCopy bool, state *copyState) (*inMemoryFile, error) {
+	return fs.renameAndMoveOrCopy(fileToMove, dest, newName, isCopy, state)
 }
 
 // moveOrCopyDirectoryToExistingDestination moves/copies the source directory to an existing destination.
 // If destination is a directory, the source directory and destination directory are merged.
 // if destination is a regular file, the source directory is moved/copied to the destination's parent and renamed.
-func (fs *MemoryFileSystem) moveOrCopyDirectoryToExistingDestination(fileToMove *inMemoryFile, dest *inMemoryFile, isCopy bool) (*inMemoryFile, error) {
+func (fs *MemoryFileSystem) moveOrCopyDirectoryToExistingDestination(fileToMove *inMemoryFile, dest *inMemoryFile, isCopy bool, state *copyState) (*inMemoryFile, error) {
 	// validate if not moving to subdir
 	if !isCopy {
 		err := fs.doWalk(fileToMove, func(f file.File) error {
@@ -111,12 +193,12 @@ func (fs *MemoryFileSystem) moveOrCopyDirectoryToExistingDestination(fileToMove
 		}
 	}
 
-	if dest.info.fileType == file.Directory {
-		return fs.mergeDirectories(fileToMove, dest, isCopy)
+	if dest.data.fileType == file.Directory {
+		return fs.mergeDirectories(fileToMove, dest, isCopy, state)
 	}
 
 	// move/copy to dest parent dir, and rename
-	return fs.renameAndMoveOrCopy(fileToMove, dest.fileMap[".."], fileToMove.info.Name(), isCopy)
+	return fs.renameAndMoveOrCopy(fileToMove, dest.fileMap[".."], fileToMove.info.Name(), isCopy, state)
 
 }
 
@@ -125,63 +207,65 @@ func (fs *MemoryFileSystem) moveOrCopyDirectoryToExistingDestination(fileToMove
 // the source directory is simply moved/copied to the new location.
 // If in the destination directory there is a directory with the same name as the source directory,
 // all the files in the source directory are moved/copied to the destination directory and, in case of a move,
-// the source directory is removed.
-func (fs *MemoryFileSystem) mergeDirectories(dirToMove *inMemoryFile, dest *inMemoryFile, isCopy bool) (*inMemoryFile, error) {
-	finalDest, found := dest.fileMap[dirToMove.info.Name()]
+// the source directory is removed once empty.
+func (fs *MemoryFileSystem) mergeDirectories(dirToMove *inMemoryFile, dest *inMemoryFile, isCopy bool, state *copyState) (*inMemoryFile, error) {
+	finalDest, found := fs.lookup(dest, dirToMove.info.Name())
 	if !found {
-		return fs.renameAndMoveOrCopyDirectory(dirToMove, dest, dirToMove.info.Name(), isCopy)
+		return fs.renameAndMoveOrCopyDirectory(dirToMove, dest, dirToMove.info.Name(), isCopy, state)
 	}
 
 	// This is the more complex case: recursively move/copy every file to destination directory
-	err := fs.visitDir(dirToMove, func(fileName string, fileToMove *inMemoryFile) error {
+	err := fs.visitDirInOrder(dirToMove, func(fileName string, fileToMove *inMemoryFile) error {
 		var err error
-		if shouldMergeSubDirectories(fileToMove, finalDest) {
-			_, err = fs.mergeDirectories(fileToMove, finalDest, isCopy)
+		if fs.shouldMergeSubDirectories(fileToMove, finalDest) {
+			_, err = fs.mergeDirectories(fileToMove, finalDest, isCopy, state)
 		} else {
-			_, err = fs.moveOrCopyLockFree(fileToMove, finalDest, fileName, isCopy)
+			_, err = fs.moveOrCopyLockFree(fileToMove, finalDest, fileName, isCopy, state)
 		}
-		return err
+		return state.r.fail(fileToMove.info.AbsolutePath(), err)
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	if !isCopy {
-		fs.removeDirectory(dirToMove, true)
+	// the files that failed to move are kept in the source directory
+	if !isCopy && len(dirToMove.childNames()) == 0 {
+		fs.detachFromParent(dirToMove)
+		fs.markDeleted(dirToMove)
 	}
 	return finalDest, nil
 }
 
 // shouldMergeSubDirectories returns true if source is a directory and destination
 // contains a directory with the same name.
-func shouldMergeSubDirectories(fileToMove *inMemoryFile, dest *inMemoryFile) bool {
-	if fileToMove.info.fileType != file.Directory {
+func (fs *MemoryFileSystem) shouldMergeSubDirectories(fileToMove *inMemoryFile, dest *inMemoryFile) bool {
+	if fileToMove.data.fileType != file.Directory {
 		return false
 	}
 
-	_, found := dest.fileMap[fileToMove.info.Name()]
+	_, found := fs.lookup(dest, fileToMove.info.Name())
 	return found
 }
 
-func (fs *MemoryFileSystem) moveOrCopyRegularFile(fileToMove *inMemoryFile, dest *inMemoryFile, finalDestName string, isCopy bool) (*inMemoryFile, error) {
-	return fs.doMoveOrCopy(fileToMove, dest, finalDestName, fs.moveOrCopyRegularFileToExistingDestination, fs.renameAndMoveOrCopy, isCopy)
+func (fs *MemoryFileSystem) moveOrCopyRegularFile(fileToMove *inMemoryFile, dest *inMemoryFile, finalDestName string, isCopy bool, state *copyState) (*inMemoryFile, error) {
+	return fs.doMoveOrCopy(fileToMove, dest, finalDestName, fs.moveOrCopyRegularFileToExistingDestination, fs.renameAndMoveOrCopy, isCopy, state)
 }
 
 // moveOrCopyRegularFileToExistingDestination moves/copies a file to an existing destination.
 // If the destination is a directory, the file is moved/copied to the directory.
 // If the destination is a file, the source file is move/copied to the destination's parent and renamed
-func (fs *MemoryFileSystem) moveOrCopyRegularFileToExistingDestination(fileToMove *inMemoryFile, dest *inMemoryFile, isCopy bool) (*inMemoryFile, error) {
+func (fs *MemoryFileSystem) moveOrCopyRegularFileToExistingDestination(fileToMove *inMemoryFile, dest *inMemoryFile, isCopy bool, state *copyState) (*inMemoryFile, error) {
 	finalDir := dest
 	newName := fileToMove.info.Name()
 
 	// Moving to a file, i.e. need to rename
-	if dest.info.fileType != file.Directory {
+	if dest.data.fileType != file.Directory {
 		finalDir = dest.fileMap[".."]
 		newName = dest.Info().Name()
 	}
 
-	return fs.renameAndMoveOrCopy(fileToMove, finalDir, newName, isCopy)
+	return fs.renameAndMoveOrCopy(fileToMove, finalDir, newName, isCopy, state)
 }
 
 // renameAndMoveOrCopy In case of "Move", removes the source file from the original location
@@ -189,26 +273,35 @@ func (fs *MemoryFileSystem) moveOrCopyRegularFileToExistingDestination(fileToMov
 // In case of "Copy", copies the source file from the original location
 // and attaches it to the new location and renames it to the given name.
 // If there's a name the conflict the source file is automatically renamed.
-func (fs *MemoryFileSystem) renameAndMoveOrCopy(fileToMove *inMemoryFile, dest *inMemoryFile, newName string, isCopy bool) (*inMemoryFile, error) {
+// The moved or copied tree is encoded like the files created in the new location,
+// so it fails with ErrNoKey when the new location is encrypted and the key provider is not set.
+func (fs *MemoryFileSystem) renameAndMoveOrCopy(fileToMove *inMemoryFile, dest *inMemoryFile, newName string, isCopy bool, state *copyState) (*inMemoryFile, error) {
+	srcAbsPath := fileToMove.info.AbsolutePath()
+
 	// check for name conflicts
 	finalName := newName
-	if _, found := dest.fileMap[finalName]; found {
+	// a file moved to a name differing only in case keeps its entry
+	if existing, found := fs.lookup(dest, finalName); found && (isCopy || existing != fileToMove) {
 		finalName = generateRandomNameFromBaseName(finalName)
 	}
 
 	// check if new name is valid
-	if err := checkFileName(finalName); err != nil {
+	finalName, err := fs.checkNewName(dest, finalName)
+	if err != nil {
 		return nil, err
 	}
 
+	if dest.data.encrypted && fs.keys == nil {
+		return nil, fserrors.ErrNoKey
+	}
+
 	newAbsPath := filepath.Join(dest.info.AbsolutePath(), finalName)
 
 	var result *inMemoryFile
-	var err error
 	if isCopy {
-		result, err = fs.copyFile(fileToMove, newAbsPath)
+		result, err = fs.copyFile(fileToMove, dest, newAbsPath, state)
 	} else {
-		result, err = fs.moveFile(fileToMove, newAbsPath)
+		result, err = fs.moveFile(fileToMove, dest, newAbsPath)
 	}
 	if err != nil {
 		return nil, err
@@ -216,74 +309,100 @@ func (fs *MemoryFileSystem) renameAndMoveOrCopy(fileToMove *inMemoryFile, dest *
 
 	// attach to new dir
 	fs.attachToParent(result, dest)
+	state.r.succeeded(srcAbsPath)
 
 	return result, nil
 }
 
-// moveFile detaches the file from the original parent and uptades the absolute path
-func (fs *MemoryFileSystem) moveFile(fileToMove *inMemoryFile, newAbsPath string) (*inMemoryFile, error) {
+// moveFile detaches the file from the original parent and renames it.
+// The paths of the directory content are derived from the directory,
+// only the content encoded differently from dest is encoded again.
+func (fs *MemoryFileSystem) moveFile(fileToMove *inMemoryFile, dest *inMemoryFile, newAbsPath string) (*inMemoryFile, error) {
+	err := fs.doWalkDir(fileToMove, fileToMove.info.AbsolutePath(), 0, map[*inMemoryFile]bool{}, func(_ string, f *inMemoryFile, _ int) error {
+		fs.journal.saveInode(f.data)
+		return fs.encodeLike(f, dest)
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	// detach from parent dir
 	fs.detachFromParent(fileToMove)
-	// Update absolute path
-	return fs.updatePaths(fileToMove, newAbsPath)
+	fs.journal.saveInode(fileToMove.data)
+	fileToMove.data.markChanged()
+	// Update name
+	fileToMove.info.setDetached(newAbsPath)
+	return fileToMove, nil
 }
 
-// updatePaths - updates the path of the given file. If it's a directory
-// recursively update every children
-func (fs *MemoryFileSystem) updatePaths(fileToUpdate *inMemoryFile, newAbsPath string) (*inMemoryFile, error) {
-	fileToUpdate.info.absolutePath = newAbsPath
+// copyFile creates a copy of the original file encoded like the files created in dest.
+// If the file is a directory recursively copies every file in it.
+// A file whose inode has already been copied is linked to the copy if the copy preserves the links.
+func (fs *MemoryFileSystem) copyFile(fileToMove *inMemoryFile, dest *inMemoryFile, newAbsPath string, state *copyState) (*inMemoryFile, error) {
+	newFile := fs.newFile(newAbsPath, fileToMove.data.fileType)
+	newFile.isVirtual = fileToMove.isVirtual
+	newFile.data.virtual = fileToMove.data.virtual
 
-	if fileToUpdate.info.fileType == file.Directory {
-		err := fs.visitDir(fileToUpdate, func(fileName string, child *inMemoryFile) error {
-			fs.updatePaths(child, filepath.Join(newAbsPath, fileName))
-			return nil
-		})
-		if err != nil {
+	if fileToMove.data.fileType == file.Directory {
+		if err := fs.inheritEncoding(newFile, dest); err != nil {
 			return nil, err
 		}
-	}
-	return fileToUpdate, nil
-}
-
-// copyFile creates a copy of the original file.
-// If the file is a directory recursively copies every file in it
-func (fs *MemoryFileSystem) copyFile(fileToMove *inMemoryFile, newAbsPath string) (*inMemoryFile, error) {
-	newFile := newInMemoryFile(newAbsPath, fileToMove.info.fileType)
-
-	if fileToMove.info.fileType == file.Directory {
-		err := fs.visitDir(fileToMove, func(fileName string, child *inMemoryFile) error {
-			fs.renameAndMoveOrCopy(child, newFile, fileName, true)
-			return nil
+		err := fs.visitDirInOrder(fileToMove, func(fileName string, child *inMemoryFile) error {
+			_, err := fs.renameAndMoveOrCopy(child, newFile, fileName, true, state)
+			return state.r.fail(child.info.AbsolutePath(), err)
 		})
 		if err != nil {
 			return nil, err
 		}
-	} else if fileToMove.info.fileType == file.RegularFile {
-		newDataArr := make([]byte, fileToMove.data.Size())
-		copy(newDataArr, fileToMove.data.data)
-		newData := &inMemoryFileData{
-			data: newDataArr,
+	} else if copied, found := state.links[fileToMove.data]; found {
+		newFile.setInode(copied)
+		copied.updateLinks(1)
+	} else if fileToMove.data.fileType == file.RegularFile {
+		if err := newFile.data.copyFrom(fileToMove.data, fs.keys); err != nil {
+			return nil, err
+		}
+		if err := fs.encodeLike(newFile, dest); err != nil {
+			newFile.data.Lock()
+			newFile.data.content.release(newFile.data.store)
+			newFile.data.Unlock()
+			return nil, err
+		}
+		if state.links != nil {
+			state.links[fileToMove.data] = newFile.data
 		}
-		newFile.data = newData
 	} else {
 		newFile.link = fileToMove.link
+		if err := fs.inheritEncoding(newFile, dest); err != nil {
+			return nil, err
+		}
 	}
 
 	return newFile, nil
 }
 
-func (fs *MemoryFileSystem) doMoveOrCopy(fileToMove *inMemoryFile, dest *inMemoryFile, finalDestName string, onFound onMoveOrCopyDestFound, onNotFound onMoveOrCopyDestNotFound, isCopy bool) (*inMemoryFile, error) {
+// encodeLike compresses and encrypts the file content like the files created in dir
+func (fs *MemoryFileSystem) encodeLike(f *inMemoryFile, dir *inMemoryFile) error {
+	if err := f.data.setCompression(dir.data.encoding.compression); err != nil {
+		return err
+	}
+	return f.data.setEncryption(fs.keys, dir.data.encrypted, f.data.fileType == file.RegularFile)
+}
+
+func (fs *MemoryFileSystem) doMoveOrCopy(fileToMove *inMemoryFile, dest *inMemoryFile, finalDestName string, onFound onMoveOrCopyDestFound, onNotFound onMoveOrCopyDestNotFound, isCopy bool, state *copyState) (*inMemoryFile, error) {
 	// check if dest file exists already
-	finalDest, found := dest.fileMap[finalDestName]
+	finalDest, found := fs.lookup(dest, finalDestName)
 	if found {
 		// check if same filex
 		if finalDest == fileToMove {
+			// in a case-insensitive file system a move can change the case of the name
+			if !isCopy && finalDestName != fileToMove.info.Name() && fileToMove.fileMap[".."] == dest {
+				return fs.renameAndMoveOrCopy(fileToMove, dest, finalDestName, isCopy, state)
+			}
 			return nil, fserrors.ErrSameFile
 		}
-		return onFound(fileToMove, finalDest, isCopy)
+		return onFound(fileToMove, finalDest, isCopy, state)
 	} else {
 		// rename file
-		return onNotFound(fileToMove, dest, finalDestName, isCopy)
+		return onNotFound(fileToMove, dest, finalDestName, isCopy, state)
 	}
 }
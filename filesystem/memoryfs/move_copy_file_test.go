@@ -2,6 +2,7 @@ package memoryfs_test
 
 import (
 	"errors"
+	"fmt"
 	"material/filesystem/filesystem/file"
 	"material/filesystem/filesystem/fserrors"
 	"material/filesystem/filesystem/fspath"
@@ -25,7 +26,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "/",
 			DestPath: "/dir1/file3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -44,7 +45,7 @@ func TestMove(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrOperationNotSupported)
+				assert.True(t, errors.Is(err, fserrors.ErrOperationNotSupported))
 				assert.Nil(t, info)
 			},
 		},
@@ -53,7 +54,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "/dir10",
 			DestPath: "/dir1/file3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -72,7 +73,7 @@ func TestMove(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 				assert.Nil(t, info)
 			},
 		},
@@ -81,7 +82,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "/dir1",
 			DestPath: "/dir1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -100,7 +101,7 @@ func TestMove(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrSameFile)
+				assert.True(t, errors.Is(err, fserrors.ErrSameFile))
 				assert.Nil(t, info)
 			},
 		},
@@ -109,7 +110,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "/dir1",
 			DestPath: "/dir1/dir2/dir3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/dir3/dir4", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -118,7 +119,7 @@ func TestMove(t *testing.T) {
 			},
 			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, info file.FileInfo, err error) {
 				assert.NotNil(t, err)
-				assert.Equal(t, err, fserrors.ErrInvalid)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalid))
 				assert.Nil(t, info)
 			},
 		},
@@ -127,7 +128,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "/dir1",
 			DestPath: "/dir1/dir2/dir3/file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/dir3/dir4", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -140,7 +141,7 @@ func TestMove(t *testing.T) {
 			},
 			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, info file.FileInfo, err error) {
 				assert.NotNil(t, err)
-				assert.Equal(t, err, fserrors.ErrInvalid)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalid))
 				assert.Nil(t, info)
 			},
 		},
@@ -149,7 +150,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "/dir1/file1",
 			DestPath: "/dir1/file3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -180,7 +181,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "/dir1/file1",
 			DestPath: "/dir1/file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -216,7 +217,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "/dir1/file1",
 			DestPath: "/dir2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -251,7 +252,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "/dir1/file1",
 			DestPath: "/dir2/dir4/dir5/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -286,7 +287,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "/dir1/file1",
 			DestPath: "/dir2/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -327,7 +328,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "/dir1/file1",
 			DestPath: "/file3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -364,7 +365,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "/dir1/file1",
 			DestPath: "/dir2/file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -405,7 +406,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "/dir1/",
 			DestPath: "/dir2/",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -483,7 +484,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "/dir1/",
 			DestPath: "/dir2/",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir3/dir4", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -553,7 +554,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "/dir2/",
 			DestPath: "/dir3/dir4/",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p1)
 				if err != nil {
@@ -581,7 +582,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "..",
 			DestPath: "/dir1/file3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -601,7 +602,7 @@ func TestMove(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrOperationNotSupported)
+				assert.True(t, errors.Is(err, fserrors.ErrOperationNotSupported))
 				assert.Nil(t, info)
 			},
 		},
@@ -610,7 +611,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "dir5",
 			DestPath: "/dir1/file3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -631,7 +632,7 @@ func TestMove(t *testing.T) {
 				assert.Nil(t, info)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 			},
 		},
 		{
@@ -639,7 +640,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "../dir1/file1",
 			DestPath: "./file3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -671,7 +672,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "file1",
 			DestPath: "file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -708,7 +709,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "file1",
 			DestPath: "../dir2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -744,7 +745,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "file1",
 			DestPath: "/dir2/dir4/dir5/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -780,7 +781,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "./file1",
 			DestPath: "../dir2/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -822,7 +823,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "file1",
 			DestPath: "../file3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -861,7 +862,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  "/dir1/file1",
 			DestPath: "./../dir2/file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -903,7 +904,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  ".",
 			DestPath: "../dir2/",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -982,7 +983,7 @@ func TestMove(t *testing.T) {
 			SrcPath:  ".",
 			DestPath: "../dir2/",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -1077,7 +1078,7 @@ func TestCopy(t *testing.T) {
 			SrcPath:  "/",
 			DestPath: "/dir1/",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -1096,7 +1097,7 @@ func TestCopy(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrOperationNotSupported)
+				assert.True(t, errors.Is(err, fserrors.ErrOperationNotSupported))
 				assert.Nil(t, info)
 			},
 		},
@@ -1105,7 +1106,7 @@ func TestCopy(t *testing.T) {
 			SrcPath:  "/dir10",
 			DestPath: "/dir1/file3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -1124,7 +1125,7 @@ func TestCopy(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 				assert.Nil(t, info)
 			},
 		},
@@ -1133,7 +1134,7 @@ func TestCopy(t *testing.T) {
 			SrcPath:  "/dir1",
 			DestPath: "/dir1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -1152,7 +1153,7 @@ func TestCopy(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrSameFile)
+				assert.True(t, errors.Is(err, fserrors.ErrSameFile))
 				assert.Nil(t, info)
 			},
 		},
@@ -1161,7 +1162,7 @@ func TestCopy(t *testing.T) {
 			SrcPath:  "/dir1/file1",
 			DestPath: "/dir1/file3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -1200,7 +1201,7 @@ func TestCopy(t *testing.T) {
 			SrcPath:  "/dir1/file1",
 			DestPath: "/dir1/file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -1236,7 +1237,7 @@ func TestCopy(t *testing.T) {
 			SrcPath:  "/dir1/file1",
 			DestPath: "/dir2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -1280,7 +1281,7 @@ func TestCopy(t *testing.T) {
 			SrcPath:  "/dir1/file1",
 			DestPath: "/dir2/dir4/dir5/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -1325,7 +1326,7 @@ func TestCopy(t *testing.T) {
 			SrcPath:  "/dir1/",
 			DestPath: "/dir2/",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -1417,7 +1418,7 @@ func TestCopy(t *testing.T) {
 			SrcPath:  "/dir1/",
 			DestPath: "/dir2/",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir3/dir4", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -1484,7 +1485,7 @@ func TestCopy(t *testing.T) {
 			SrcPath:  "..",
 			DestPath: "/dir1/file3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -1504,7 +1505,7 @@ func TestCopy(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrOperationNotSupported)
+				assert.True(t, errors.Is(err, fserrors.ErrOperationNotSupported))
 				assert.Nil(t, info)
 			},
 		},
@@ -1513,7 +1514,7 @@ func TestCopy(t *testing.T) {
 			SrcPath:  "dir5",
 			DestPath: "/dir1/file3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -1534,7 +1535,7 @@ func TestCopy(t *testing.T) {
 				assert.Nil(t, info)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 			},
 		},
 		{
@@ -1542,7 +1543,7 @@ func TestCopy(t *testing.T) {
 			SrcPath:  "../dir1/file1",
 			DestPath: "./file3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -1576,7 +1577,7 @@ func TestCopy(t *testing.T) {
 			SrcPath:  "file1",
 			DestPath: "file2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -1613,7 +1614,7 @@ func TestCopy(t *testing.T) {
 			SrcPath:  "file1",
 			DestPath: "../dir2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -1658,7 +1659,7 @@ func TestCopy(t *testing.T) {
 			SrcPath:  "./../dir1/file1",
 			DestPath: "../dir2/dir4/dir5/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -1705,7 +1706,7 @@ func TestCopy(t *testing.T) {
 			SrcPath:  ".",
 			DestPath: "../dir2",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -1799,7 +1800,7 @@ func TestCopy(t *testing.T) {
 			SrcPath:  ".",
 			DestPath: "/dir2/",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				workDir, err := fs.Mkdir(p)
 				if err != nil {
@@ -1871,7 +1872,7 @@ func TestCopy(t *testing.T) {
 			SrcPath:  "/dir2/",
 			DestPath: "/dir3/dir4/",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/dir1/dir3/", nil)
 				if _, err := fs.MkdirAll(p1); err != nil {
 					return nil, nil, err
@@ -1926,3 +1927,259 @@ func checkCopy(t *testing.T, fs *memoryfs.MemoryFileSystem, srcPath string, dest
 		checkCopy(t, fs, originalFiles[i].AbsolutePath(), newFiles[i].AbsolutePath())
 	}
 }
+
+func TestMovedDirectoryPaths(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem)
+	}{
+		{
+			CaseName: "Content paths follow the moved directory",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/a/b/c", nil)
+				files, err := fs.ListFiles(p)
+				assert.Nil(t, err)
+
+				src, _ := fspath.NewFileSystemPath("/a", nil)
+				dest, _ := fspath.NewFileSystemPath("/z/y", nil)
+				_, err = fs.Move(src, dest)
+				assert.Nil(t, err)
+
+				assert.Equal(t, "/z/y/b/c/file", files[0].AbsolutePath())
+				assert.Equal(t, "file", files[0].Name())
+				p, _ = fspath.NewFileSystemPath("/z/y/b/c/file", nil)
+				stat, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.Equal(t, "/z/y/b/c/file", stat.AbsolutePath())
+			},
+		},
+		{
+			CaseName: "Working directory follows the moved directory",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/a/b/c", nil)
+				workDir, err := fs.GetDirectory(p)
+				assert.Nil(t, err)
+
+				src, _ := fspath.NewFileSystemPath("/a/b", nil)
+				dest, _ := fspath.NewFileSystemPath("/a/d", nil)
+				_, err = fs.Move(src, dest)
+				assert.Nil(t, err)
+
+				assert.Equal(t, "/a/d/c", workDir.Info().AbsolutePath())
+				p, _ = fspath.NewFileSystemPath("../c/file", workDir)
+				assert.Equal(t, "/a/d/c/file", p.AbsolutePath())
+				info, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.Equal(t, "/a/d/c/file", info.AbsolutePath())
+			},
+		},
+		{
+			CaseName: "Removed files keep their last path",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/a/b/c", nil)
+				files, err := fs.ListFiles(p)
+				assert.Nil(t, err)
+
+				p, _ = fspath.NewFileSystemPath("/a/b", nil)
+				_, err = fs.RemoveAll(p)
+				assert.Nil(t, err)
+				src, _ := fspath.NewFileSystemPath("/a", nil)
+				dest, _ := fspath.NewFileSystemPath("/z", nil)
+				_, err = fs.Move(src, dest)
+				assert.Nil(t, err)
+
+				assert.Equal(t, "/a/b/c/file", files[0].AbsolutePath())
+			},
+		},
+		{
+			CaseName: "Paths can be read while directories are moved",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/a/b/c", nil)
+				workDir, err := fs.GetDirectory(p)
+				assert.Nil(t, err)
+
+				done := make(chan bool)
+				go func() {
+					for i := 0; i < 100; i++ {
+						path := workDir.Info().AbsolutePath()
+						assert.True(t, path == "/a/b/c" || path == "/x/b/c", path)
+					}
+					done <- true
+				}()
+				src, _ := fspath.NewFileSystemPath("/a", nil)
+				dest, _ := fspath.NewFileSystemPath("/x", nil)
+				for i := 0; i < 50; i++ {
+					_, err := fs.Move(src, dest)
+					assert.Nil(t, err)
+					_, err = fs.Move(dest, src)
+					assert.Nil(t, err)
+				}
+				<-done
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.CaseName, func(t *testing.T) {
+			fs := newFileSystem()
+			p, _ := fspath.NewFileSystemPath("/a/b/c/file", nil)
+			if err := fs.AppendAll(p, []byte("content")); err != nil {
+				t.Fatal(err)
+			}
+			c.Assertions(t, fs)
+		})
+	}
+}
+
+func TestCopyPreservingLinks(t *testing.T) {
+	stat := func(t *testing.T, fs *memoryfs.MemoryFileSystem, path string) file.FileStat {
+		p, _ := fspath.NewFileSystemPath(path, nil)
+		stat, err := fs.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stat
+	}
+	cases := []struct {
+		CaseName   string
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem)
+	}{
+		{
+			CaseName: "Files linked in the source are linked in the copy",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				src, _ := fspath.NewFileSystemPath("/src", nil)
+				dest, _ := fspath.NewFileSystemPath("/dest", nil)
+				_, report, err := fs.CopyPreservingLinks(src, dest, false)
+				assert.Nil(t, err)
+				assert.Len(t, report.Failed, 0)
+
+				fileStat, linkStat := stat(t, fs, "/dest/file"), stat(t, fs, "/dest/dir/link")
+				assert.Equal(t, fileStat.Inode(), linkStat.Inode())
+				assert.NotEqual(t, stat(t, fs, "/src/file").Inode(), fileStat.Inode())
+				assert.Equal(t, 2, fileStat.Links())
+				assert.Equal(t, 2, stat(t, fs, "/src/file").Links())
+				assertContent(t, fs, "/dest/dir/link", []byte("content"))
+
+				p, _ := fspath.NewFileSystemPath("/dest/file", nil)
+				assert.Nil(t, fs.AppendAll(p, []byte(" appended")))
+				assertContent(t, fs, "/dest/dir/link", []byte("content appended"))
+				assertContent(t, fs, "/src/dir/link", []byte("content"))
+			},
+		},
+		{
+			CaseName: "Links to files outside of the source are copied",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				src, _ := fspath.NewFileSystemPath("/src", nil)
+				dest, _ := fspath.NewFileSystemPath("/dest", nil)
+				_, _, err := fs.CopyPreservingLinks(src, dest, false)
+				assert.Nil(t, err)
+
+				ext := stat(t, fs, "/dest/ext")
+				assert.NotEqual(t, stat(t, fs, "/outside").Inode(), ext.Inode())
+				assert.Equal(t, 1, ext.Links())
+				assert.Equal(t, 2, stat(t, fs, "/outside").Links())
+				assertContent(t, fs, "/dest/ext", []byte("outside"))
+			},
+		},
+		{
+			CaseName: "Copy without preserving links creates separate files",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				src, _ := fspath.NewFileSystemPath("/src", nil)
+				dest, _ := fspath.NewFileSystemPath("/dest", nil)
+				_, _, err := fs.CopyWithReport(src, dest, false)
+				assert.Nil(t, err)
+
+				fileStat, linkStat := stat(t, fs, "/dest/file"), stat(t, fs, "/dest/dir/link")
+				assert.NotEqual(t, fileStat.Inode(), linkStat.Inode())
+				assert.Equal(t, 1, fileStat.Links())
+				assert.Equal(t, 1, linkStat.Links())
+			},
+		},
+		{
+			CaseName: "Removing a copied link decrements the links",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				src, _ := fspath.NewFileSystemPath("/src", nil)
+				dest, _ := fspath.NewFileSystemPath("/dest", nil)
+				_, _, err := fs.CopyPreservingLinks(src, dest, false)
+				assert.Nil(t, err)
+
+				p, _ := fspath.NewFileSystemPath("/dest/dir", nil)
+				_, err = fs.RemoveAll(p)
+				assert.Nil(t, err)
+				assert.Equal(t, 1, stat(t, fs, "/dest/file").Links())
+				assertContent(t, fs, "/dest/file", []byte("content"))
+			},
+		},
+		{
+			CaseName: "Commit preserves the links copied in a transaction",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				tx := fs.Begin()
+				src, _ := fspath.NewFileSystemPath("/src", nil)
+				dest, _ := fspath.NewFileSystemPath("/dest", nil)
+				_, _, err := tx.CopyPreservingLinks(src, dest, false)
+				assert.Nil(t, err)
+				assert.Nil(t, tx.Commit())
+
+				fileStat, linkStat := stat(t, fs, "/dest/file"), stat(t, fs, "/dest/dir/link")
+				assert.Equal(t, fileStat.Inode(), linkStat.Inode())
+				assert.Equal(t, 2, linkStat.Links())
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.CaseName, func(t *testing.T) {
+			fs := newFileSystem()
+			p1, _ := fspath.NewFileSystemPath("/src/file", nil)
+			if err := fs.AppendAll(p1, []byte("content")); err != nil {
+				t.Fatal(err)
+			}
+			dir, _ := fspath.NewFileSystemPath("/src/dir", nil)
+			if _, err := fs.MkdirAll(dir); err != nil {
+				t.Fatal(err)
+			}
+			p2, _ := fspath.NewFileSystemPath("/src/dir/link", nil)
+			if _, err := fs.CreateHardLink(p1, p2); err != nil {
+				t.Fatal(err)
+			}
+			p3, _ := fspath.NewFileSystemPath("/outside", nil)
+			if err := fs.AppendAll(p3, []byte("outside")); err != nil {
+				t.Fatal(err)
+			}
+			p4, _ := fspath.NewFileSystemPath("/src/ext", nil)
+			if _, err := fs.CreateHardLink(p3, p4); err != nil {
+				t.Fatal(err)
+			}
+			c.Assertions(t, fs)
+		})
+	}
+}
+
+// BenchmarkMoveDirectory renames a directory back and forth,
+// which doesn't depend on the number of files it contains
+func BenchmarkMoveDirectory(b *testing.B) {
+	for _, entries := range []int{10, 10000} {
+		b.Run(fmt.Sprintf("entries=%d", entries), func(b *testing.B) {
+			fs, err := memoryfs.NewMemoryFileSystem()
+			if err != nil {
+				b.Fatal(err)
+			}
+			for i := 0; i < entries; i++ {
+				p, _ := fspath.NewFileSystemPath(fmt.Sprintf("/src/dir%d/file%d", i%100, i), nil)
+				if err := fs.AppendAll(p, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+			src, _ := fspath.NewFileSystemPath("/src", nil)
+			dest, _ := fspath.NewFileSystemPath("/dest", nil)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := fs.Move(src, dest); err != nil {
+					b.Fatal(err)
+				}
+				src, dest = dest, src
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package memoryfs
+
+import (
+	"path/filepath"
+)
+
+// checkNewName checks the name of a file added to parent
+// and returns it in the normalization form of the policy.
+// Only the path of the file is checked, not the paths of its children.
+//
+// Returns an error when:
+// - the name is a special name (ErrInvalid)
+// - the name or the new path violates the name policy (ErrNameTooLong, ErrInvalidName)
+func (fs *MemoryFileSystem) checkNewName(parent *inMemoryFile, name string) (string, error) {
+	if err := checkFileName(name); err != nil {
+		return "", err
+	}
+
+	name = fs.namePolicy.Normalize(name)
+	if err := fs.namePolicy.CheckName(name); err != nil {
+		return "", err
+	}
+	if err := fs.namePolicy.CheckPath(filepath.Join(parent.info.AbsolutePath(), name)); err != nil {
+		return "", err
+	}
+	return name, nil
+}
@@ -0,0 +1,124 @@
+package memoryfs_test
+
+import (
+	"errors"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fsname"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/memoryfs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamePolicy(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Policy     fsname.Policy
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem)
+	}{
+		{
+			CaseName: "The default policy limits the name length and forbids NUL bytes",
+			Policy:   fsname.DefaultPolicy(),
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/"+strings.Repeat("a", 255), nil)
+				_, err := fs.CreateRegularFile(p)
+				assert.Nil(t, err)
+
+				p, _ = fspath.NewFileSystemPath("/"+strings.Repeat("a", 256), nil)
+				_, err = fs.CreateRegularFile(p)
+				assert.True(t, errors.Is(err, fserrors.ErrNameTooLong))
+
+				p, _ = fspath.NewFileSystemPath("/file\x00name", nil)
+				_, err = fs.CreateRegularFile(p)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalidName))
+
+				p, _ = fspath.NewFileSystemPath("/dir/..", nil)
+				_, err = fs.CreateRegularFile(p)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalid))
+			},
+		},
+		{
+			CaseName: "Forbidden characters and reserved names",
+			Policy:   fsname.Policy{ForbiddenChars: `:*?`, ReservedNames: []string{"CON", "NUL"}},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				for _, name := range []string{"/a:b", "/what?", "/CON", "/con", "/dir/Nul"} {
+					p, _ := fspath.NewFileSystemPath(name, nil)
+					_, err := fs.MkdirAll(p)
+					assert.True(t, errors.Is(err, fserrors.ErrInvalidName), name)
+				}
+
+				p, _ := fspath.NewFileSystemPath("/console", nil)
+				_, err := fs.CreateRegularFile(p)
+				assert.Nil(t, err)
+			},
+		},
+		{
+			CaseName: "Path length and depth",
+			Policy:   fsname.Policy{MaxPathLength: 12, MaxDepth: 3},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/a/b/c", nil)
+				_, err := fs.MkdirAll(p)
+				assert.Nil(t, err)
+
+				p, _ = fspath.NewFileSystemPath("/a/b/c/d", nil)
+				_, err = fs.MkdirAll(p)
+				assert.True(t, errors.Is(err, fserrors.ErrNameTooLong))
+
+				p, _ = fspath.NewFileSystemPath("/a/long-names", nil)
+				_, err = fs.CreateRegularFile(p)
+				assert.True(t, errors.Is(err, fserrors.ErrNameTooLong))
+			},
+		},
+		{
+			CaseName: "Names are normalized when created and looked up",
+			Policy:   fsname.Policy{Normalization: fsname.NFC},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				decomposed, _ := fspath.NewFileSystemPath("/cafe\u0301", nil)
+				info, err := fs.CreateRegularFile(decomposed)
+				assert.Nil(t, err)
+				assert.Equal(t, "/caf\u00e9", info.Info().AbsolutePath())
+
+				composed, _ := fspath.NewFileSystemPath("/caf\u00e9", nil)
+				_, err = fs.CreateRegularFile(composed)
+				assert.True(t, errors.Is(err, fserrors.ErrExist))
+				_, err = fs.Stat(decomposed)
+				assert.Nil(t, err)
+
+				p, _ := fspath.NewFileSystemPath("/invalid\xff", nil)
+				_, err = fs.CreateRegularFile(p)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalidName))
+			},
+		},
+		{
+			CaseName: "Move, copy and links apply the policy",
+			Policy:   fsname.Policy{ReservedNames: []string{"CON"}, Normalization: fsname.NFC},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				src, _ := fspath.NewFileSystemPath("/file", nil)
+				_, err := fs.CreateRegularFile(src)
+				assert.Nil(t, err)
+
+				dest, _ := fspath.NewFileSystemPath("/con", nil)
+				_, err = fs.Move(src, dest)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalidName))
+				_, err = fs.Copy(src, dest)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalidName))
+				_, err = fs.CreateHardLink(src, dest)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalidName))
+
+				dest, _ = fspath.NewFileSystemPath("/cafe\u0301", nil)
+				info, err := fs.Move(src, dest)
+				assert.Nil(t, err)
+				assert.Equal(t, "/caf\u00e9", info.AbsolutePath())
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.CaseName, func(t *testing.T) {
+			fs := newFileSystem(memoryfs.WithNamePolicy(c.Policy))
+			c.Assertions(t, fs)
+		})
+	}
+}
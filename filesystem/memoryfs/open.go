@@ -1,51 +1,152 @@
 package memoryfs
 
 import (
+	"context"
 	"material/filesystem/filesystem/file"
 	"material/filesystem/filesystem/fserrors"
 	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsversion"
 
 	"github.com/google/uuid"
 )
 
-// Open opens the named file for reading or writing.
-// Returns an error if the file was not found or it's not a "regular" file.
+// Open opens the named file for reading and writing.
+// Opening a fifo for reading and writing never waits.
 // This implementation is thread safe
 //
 // Returns an error when:
 // - path does not exist
-// - the file is not a RegularFile
+// - the file is not a RegularFile or a Fifo
 func (fs *MemoryFileSystem) Open(path *fspath.FileSystemPath) (string, error) {
+	return fs.OpenFile(path, file.OpenReadWrite)
+}
+
+// OpenFile opens the named file for reading and/or writing, according to flags.
+// Opening only one end of a fifo waits until the other end is open,
+// unless file.OpenNonBlocking is set.
+// The content of a virtual file is generated when it's opened.
+// This implementation is thread safe
+//
+// Returns an error when:
+// - path does not exist
+// - the file is not a RegularFile or a Fifo
+// - flags has neither file.OpenRead nor file.OpenWrite
+// - the fifo other end is not open in non-blocking mode
+func (fs *MemoryFileSystem) OpenFile(path *fspath.FileSystemPath, flags file.OpenFlag) (string, error) {
+	return fs.OpenFileWithOwner(path, flags, "")
+}
+
+// OpenFileWithOwner opens the named file like OpenFile, recording the owner
+// of the descriptor, e.g. a session. The owners of the descriptors of the
+// deleted files are listed by ListOrphans.
+// This implementation is thread safe
+func (fs *MemoryFileSystem) OpenFileWithOwner(path *fspath.FileSystemPath, flags file.OpenFlag, owner string) (string, error) {
+	return fs.OpenFileContext(context.Background(), path, flags, owner)
+}
+
+// OpenFileContext opens the named file like OpenFileWithOwner.
+// Opening a fifo stops waiting for the other end when ctx is done.
+// This implementation is thread safe
+//
+// Returns an error when:
+// - ctx is done while waiting for the other end of a fifo
+func (fs *MemoryFileSystem) OpenFileContext(ctx context.Context, path *fspath.FileSystemPath, flags file.OpenFlag, owner string) (string, error) {
 	fs.Lock()
-	defer fs.Unlock()
 
 	fileToOpen, err := fs.traverseToBase(path)
 	if err != nil {
-		return "", err
+		fs.Unlock()
+		return "", pathError("open", path, err)
+	}
+
+	descriptor, err := fs.openAndUnlock(ctx, fileToOpen, flags, owner)
+	return descriptor, pathError("open", path, err)
+}
+
+// openAndUnlock opens the file and releases the fs lock.
+// The lock is released before waiting for the other end of a fifo
+// or generating the content of a virtual file.
+// This method should be called only if the caller has already acquired a lock
+func (fs *MemoryFileSystem) openAndUnlock(ctx context.Context, fileToOpen *inMemoryFile, flags file.OpenFlag, owner string) (string, error) {
+	data := fileToOpen.data
+	if fileToOpen.data.fileType != file.Fifo && data.virtual == nil {
+		defer fs.Unlock()
+		return fs.doOpen(fileToOpen, flags, owner)
 	}
 
-	return fs.doOpen(fileToOpen)
+	if flags&file.OpenReadWrite == 0 {
+		fs.Unlock()
+		return "", fserrors.ErrInvalid
+	}
+
+	// Keep the data alive without holding the lock
+	data.open()
+	fs.Unlock()
+	if data.virtual != nil {
+		return fs.openVirtual(data, flags, owner)
+	}
+	return fs.openFifo(ctx, data, flags, owner)
 }
 
-func (fs *MemoryFileSystem) doOpen(fileToOpen *inMemoryFile) (string, error) {
-	if fileToOpen.info.fileType != file.RegularFile {
-		return "", fserrors.ErrInvalidFileType
+func (fs *MemoryFileSystem) doOpen(fileToOpen *inMemoryFile, flags file.OpenFlag, owner string) (string, error) {
+	if err := checkRegularFile(fileToOpen); err != nil {
+		return "", err
+	}
+	if flags&file.OpenReadWrite == 0 {
+		return "", fserrors.ErrInvalid
 	}
 
+	fileToOpen.data.open()
+	return fs.addDescriptor(&fileDescriptor{data: fileToOpen.data, offset: 0, flags: flags, owner: owner}), nil
+}
+
+// addDescriptor adds the descriptor to the open files table and returns its id.
+// The caller must have registered an open descriptor on the data.
+func (fs *MemoryFileSystem) addDescriptor(fd *fileDescriptor) string {
 	fs.openFiles.Lock()
 	defer fs.openFiles.Unlock()
 
 	// TODO: fd could just be an int
-	fd := uuid.NewString()
-	fs.openFiles.table[fd] = &fileDescriptor{data: fileToOpen.data, offset: 0}
-	return fd, nil
+	id := uuid.NewString()
+	fs.openFiles.table[id] = fd
+	return id
 }
 
 // Close closes the file associated to the given descriptor
 // If the file is not open, this is a noop.
+// If the file was written, a new version is captured according to the version policy.
+// Closing the last writer of a fifo ends the stream for the readers.
 // This implementation is thread safe.
 func (fs *MemoryFileSystem) Close(descriptor string) {
+	fs.RLock()
+	policy := fs.versionPolicy
+	fs.RUnlock()
+
 	fs.openFiles.Lock()
 	defer fs.openFiles.Unlock()
+	fs.closeDescriptor(descriptor, policy)
+}
+
+// closeDescriptor removes the descriptor from the open files table,
+// and forgets the orphan once its last descriptor is closed.
+// This method should be called only if the caller has already acquired the open files lock
+func (fs *MemoryFileSystem) closeDescriptor(descriptor string, policy fsversion.Policy) {
+	fd, found := fs.openFiles.table[descriptor]
+	if !found {
+		return
+	}
 	delete(fs.openFiles.table, descriptor)
+	if fd.data.pipe != nil {
+		fd.data.pipe.close(fd.flags)
+	}
+	fs.closeData(fd.data, policy)
+}
+
+// closeData unregisters an open descriptor of data,
+// and forgets the orphan once its last descriptor is closed.
+// This method should be called only if the caller has already acquired the open files lock
+func (fs *MemoryFileSystem) closeData(data *inMemoryFileData, policy fsversion.Policy) {
+	if data.close(policy) {
+		delete(fs.openFiles.orphans, data)
+	}
 }
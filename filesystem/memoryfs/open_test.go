@@ -1,6 +1,7 @@
 package memoryfs_test
 
 import (
+	"errors"
 	"material/filesystem/filesystem/file"
 	"material/filesystem/filesystem/fserrors"
 	"material/filesystem/filesystem/fspath"
@@ -21,7 +22,7 @@ func TestOpen(t *testing.T) {
 			CaseName: "Open a file - absolute path",
 			Path:     "/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -37,7 +38,7 @@ func TestOpen(t *testing.T) {
 			CaseName: "Open a directory should give an error - relative path",
 			Path:     "dir1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -46,7 +47,7 @@ func TestOpen(t *testing.T) {
 			},
 			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, fd string, err error) {
 				assert.NotNil(t, err)
-				assert.Equal(t, fserrors.ErrInvalidFileType, err)
+				assert.True(t, errors.Is(err, fserrors.ErrIsDir))
 				assert.Empty(t, fd)
 			},
 		},
@@ -54,7 +55,7 @@ func TestOpen(t *testing.T) {
 			CaseName: "Open a symilink should open original file - relative path",
 			Path:     "file1-link",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -74,7 +75,7 @@ func TestOpen(t *testing.T) {
 			CaseName: "Open a file that does not exist should give an error - absolute path",
 			Path:     "/fileeeee",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -83,7 +84,7 @@ func TestOpen(t *testing.T) {
 			},
 			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, fd string, err error) {
 				assert.NotNil(t, err)
-				assert.Equal(t, fserrors.ErrNotExist, err)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 				assert.Empty(t, fd)
 			},
 		},
@@ -91,7 +92,7 @@ func TestOpen(t *testing.T) {
 			CaseName: "Opening the same file multiple time should return different fd - absolute path",
 			Path:     "/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
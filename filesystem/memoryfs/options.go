@@ -0,0 +1,118 @@
+package memoryfs
+
+import (
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fsname"
+	"strings"
+	"time"
+)
+
+// Option configures a file system created by NewMemoryFileSystem
+type Option func(*options)
+
+// options are the settings of a new file system
+type options struct {
+	rootName        string
+	layout          []string
+	clock           func() time.Time
+	maxLinkDepth    int
+	namePolicy      fsname.Policy
+	caseInsensitive bool
+	maxFileSize     int
+	capacity        int
+	pathCacheSize   int
+}
+
+func defaultOptions() *options {
+	return &options{
+		rootName:      "/",
+		clock:         time.Now,
+		maxLinkDepth:  MAX_LINK_DEPTH,
+		namePolicy:    fsname.DefaultPolicy(),
+		pathCacheSize: DEFAULT_PATH_CACHE_SIZE,
+	}
+}
+
+// WithRootName sets the name of the root directory, "/" by default.
+// The paths don't change: the absolute path of the root is always "/".
+func WithRootName(name string) Option {
+	return func(o *options) {
+		o.rootName = name
+	}
+}
+
+// WithLayout creates the given directories, and any missing parent,
+// when the file system is created. The paths must be absolute.
+func WithLayout(dirs ...string) Option {
+	return func(o *options) {
+		o.layout = append(o.layout, dirs...)
+	}
+}
+
+// WithClock sets the time source of the timestamps, time.Now by default
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.clock = now
+	}
+}
+
+// WithMaxLinkDepth sets the maximum number of symbolic links
+// followed to resolve a path, MAX_LINK_DEPTH by default
+func WithMaxLinkDepth(depth int) Option {
+	return func(o *options) {
+		o.maxLinkDepth = depth
+	}
+}
+
+// WithNamePolicy sets the restrictions on the names and paths of the files,
+// fsname.DefaultPolicy() by default
+func WithNamePolicy(policy fsname.Policy) Option {
+	return func(o *options) {
+		o.namePolicy = policy
+	}
+}
+
+// WithCaseInsensitiveNames makes the name lookups ignore case, using Unicode
+// case folding, while the original spelling of the names is preserved.
+// Creating "README.md" when "Readme.md" exists fails with ErrExist, and moving
+// a file to a name differing only in case renames it.
+func WithCaseInsensitiveNames() Option {
+	return func(o *options) {
+		o.caseInsensitive = true
+	}
+}
+
+// WithMaxFileSize limits the size of every regular file in bytes, zero for no limit.
+// Writes beyond the limit fail with ErrFileTooLarge.
+func WithMaxFileSize(size int) Option {
+	return func(o *options) {
+		o.maxFileSize = size
+	}
+}
+
+// WithCapacity limits the bytes stored by the file system, after deduplication
+// and compression, zero for no limit. Writes beyond the limit fail with ErrNoSpace.
+func WithCapacity(size int) Option {
+	return func(o *options) {
+		o.capacity = size
+	}
+}
+
+// WithPathCacheSize sets the maximum number of directories whose resolution is cached,
+// DEFAULT_PATH_CACHE_SIZE by default, zero to disable the cache
+func WithPathCacheSize(size int) Option {
+	return func(o *options) {
+		o.pathCacheSize = size
+	}
+}
+
+// validate checks the values of the settings
+func (o *options) validate() error {
+	if o.rootName != "/" && (o.rootName == "" || strings.Contains(o.rootName, "/") || checkFileName(o.rootName) != nil) {
+		return fserrors.ErrInvalid
+	}
+	if o.clock == nil || o.maxLinkDepth < 0 || o.maxFileSize < 0 || o.capacity < 0 || o.pathCacheSize < 0 {
+		return fserrors.ErrInvalid
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package memoryfs_test
+
+import (
+	"bytes"
+	"errors"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/memoryfs"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptions(t *testing.T) {
+	now := time.Date(2022, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		CaseName   string
+		Options    []memoryfs.Option
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem)
+	}{
+		{
+			CaseName: "Root name and layout",
+			Options:  []memoryfs.Option{memoryfs.WithRootName("volume"), memoryfs.WithLayout("/home/alice", "/tmp")},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				root, _ := fspath.NewFileSystemPath("/", nil)
+				stat, err := fs.Stat(root)
+				assert.Nil(t, err)
+				assert.Equal(t, "volume", stat.Name())
+				assert.Equal(t, "/", stat.AbsolutePath())
+
+				files, err := fs.ListFiles(root)
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"home", "tmp"}, fileNames(files))
+
+				p, _ := fspath.NewFileSystemPath("/home/alice", nil)
+				_, err = fs.Stat(p)
+				assert.Nil(t, err)
+			},
+		},
+		{
+			CaseName: "The clock sets the timestamps",
+			Options:  []memoryfs.Option{memoryfs.WithClock(func() time.Time { return now })},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/file", nil)
+				err := fs.AppendAll(p, []byte("Hello world!"))
+				assert.Nil(t, err)
+
+				stat, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.True(t, now.Equal(stat.ModTime()))
+				assert.True(t, now.Equal(stat.ChangeTime()))
+
+				entry, err := fs.Trash(p, "alice", false)
+				assert.Nil(t, err)
+				assert.True(t, now.Equal(entry.DeletedAt))
+			},
+		},
+		{
+			CaseName: "Max link depth",
+			Options:  []memoryfs.Option{memoryfs.WithMaxLinkDepth(0)},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				dir, _ := fspath.NewFileSystemPath("/dir", nil)
+				_, err := fs.Mkdir(dir)
+				assert.Nil(t, err)
+				link, _ := fspath.NewFileSystemPath("/link", nil)
+				_, err = fs.CreateSymbolicLink(dir, link)
+				assert.Nil(t, err)
+
+				p, _ := fspath.NewFileSystemPath("/link/file", nil)
+				_, err = fs.CreateRegularFile(p)
+				assert.True(t, errors.Is(err, fserrors.ErrTooManyLinks))
+			},
+		},
+		{
+			CaseName: "Max file size",
+			Options:  []memoryfs.Option{memoryfs.WithMaxFileSize(16)},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/file", nil)
+				err := fs.AppendAll(p, []byte("Hello world!"))
+				assert.Nil(t, err)
+
+				err = fs.AppendAll(p, []byte(" Ciao mondo!"))
+				assert.True(t, errors.Is(err, fserrors.ErrFileTooLarge))
+
+				data, err := fs.ReadAll(p)
+				assert.Nil(t, err)
+				assert.Equal(t, []byte("Hello world!"), data)
+			},
+		},
+		{
+			CaseName: "Capacity counts the stored blocks once",
+			Options:  []memoryfs.Option{memoryfs.WithCapacity(100)},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p1, _ := fspath.NewFileSystemPath("/file1", nil)
+				err := fs.AppendAll(p1, bytes.Repeat([]byte("a"), 60))
+				assert.Nil(t, err)
+
+				p2, _ := fspath.NewFileSystemPath("/file2", nil)
+				err = fs.AppendAll(p2, bytes.Repeat([]byte("b"), 60))
+				assert.True(t, errors.Is(err, fserrors.ErrNoSpace))
+
+				// the same content is deduplicated
+				err = fs.AppendAll(p2, bytes.Repeat([]byte("a"), 60))
+				assert.Nil(t, err)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.CaseName, func(t *testing.T) {
+			fs := newFileSystem(c.Options...)
+			c.Assertions(t, fs)
+		})
+	}
+}
+
+func TestInvalidOptions(t *testing.T) {
+	cases := []struct {
+		CaseName string
+		Option   memoryfs.Option
+		Err      error
+	}{
+		{CaseName: "Empty root name", Option: memoryfs.WithRootName(""), Err: fserrors.ErrInvalid},
+		{CaseName: "Root name with a slash", Option: memoryfs.WithRootName("a/b"), Err: fserrors.ErrInvalid},
+		{CaseName: "Nil clock", Option: memoryfs.WithClock(nil), Err: fserrors.ErrInvalid},
+		{CaseName: "Negative link depth", Option: memoryfs.WithMaxLinkDepth(-1), Err: fserrors.ErrInvalid},
+		{CaseName: "Negative max file size", Option: memoryfs.WithMaxFileSize(-1), Err: fserrors.ErrInvalid},
+		{CaseName: "Negative capacity", Option: memoryfs.WithCapacity(-1), Err: fserrors.ErrInvalid},
+		{CaseName: "Negative path cache size", Option: memoryfs.WithPathCacheSize(-1), Err: fserrors.ErrInvalid},
+		{CaseName: "Relative layout path", Option: memoryfs.WithLayout("home"), Err: fserrors.ErrInvalid},
+	}
+
+	for _, c := range cases {
+		t.Run(c.CaseName, func(t *testing.T) {
+			fs, err := memoryfs.NewMemoryFileSystem(c.Option)
+			assert.Nil(t, fs)
+			assert.True(t, errors.Is(err, c.Err), err)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package memoryfs
+
+import (
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fsstats"
+	"material/filesystem/filesystem/fsversion"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// orphan is a deleted file that is still open
+type orphan struct {
+	id string
+	// seq orders the orphans by deletion
+	seq       int
+	path      string
+	fileType  file.FileType
+	deletedAt time.Time
+}
+
+// addOrphan registers the file whose last link has just been removed.
+// This method should be called only if the caller has already acquired the open files lock
+func (fs *MemoryFileSystem) addOrphan(f *inMemoryFile) {
+	if _, found := fs.openFiles.orphans[f.data]; found {
+		return
+	}
+	fs.journal.addOrphan(f.data)
+	fs.openFiles.nextOrphan++
+	fs.openFiles.orphans[f.data] = &orphan{
+		id:        strconv.Itoa(fs.openFiles.nextOrphan),
+		seq:       fs.openFiles.nextOrphan,
+		path:      f.info.AbsolutePath(),
+		fileType:  f.data.fileType,
+		deletedAt: fs.clock(),
+	}
+}
+
+// ListOrphans returns the deleted files that are still open, in deletion order,
+// with the owners of their descriptors.
+// This implementation is thread safe.
+func (fs *MemoryFileSystem) ListOrphans() []*fsstats.Orphan {
+	fs.openFiles.RLock()
+	defer fs.openFiles.RUnlock()
+
+	descriptors := map[*inMemoryFileData][]*fileDescriptor{}
+	for _, fd := range fs.openFiles.table {
+		if _, found := fs.openFiles.orphans[fd.data]; found {
+			descriptors[fd.data] = append(descriptors[fd.data], fd)
+		}
+	}
+
+	orphans := make([]*fsstats.Orphan, 0, len(fs.openFiles.orphans))
+	seqs := map[*fsstats.Orphan]int{}
+	for data, o := range fs.openFiles.orphans {
+		info := o.info(data, descriptors[data])
+		seqs[info] = o.seq
+		orphans = append(orphans, info)
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		return seqs[orphans[i]] < seqs[orphans[j]]
+	})
+	return orphans
+}
+
+// ReclaimOrphan closes every descriptor of the orphan identified by id,
+// releasing its content, and returns the reclaimed orphan.
+// Changes are not versioned since the file is deleted.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - id does not identify an orphan
+func (fs *MemoryFileSystem) ReclaimOrphan(id string) (*fsstats.Orphan, error) {
+	fs.openFiles.Lock()
+	defer fs.openFiles.Unlock()
+
+	for data, o := range fs.openFiles.orphans {
+		if o.id != id {
+			continue
+		}
+		ids := []string{}
+		fds := []*fileDescriptor{}
+		for descriptor, fd := range fs.openFiles.table {
+			if fd.data == data {
+				ids = append(ids, descriptor)
+				fds = append(fds, fd)
+			}
+		}
+		info := o.info(data, fds)
+		for _, descriptor := range ids {
+			fs.closeDescriptor(descriptor, fsversion.Policy{})
+		}
+		return info, nil
+	}
+	return nil, nameError("reclaim", id, fserrors.ErrNotExist)
+}
+
+// info describes the orphan with its open descriptors
+func (o *orphan) info(data *inMemoryFileData, fds []*fileDescriptor) *fsstats.Orphan {
+	size, allocated := data.usage()
+	owners := []string{}
+	seen := map[string]bool{}
+	for _, fd := range fds {
+		if fd.owner == "" || seen[fd.owner] {
+			continue
+		}
+		seen[fd.owner] = true
+		owners = append(owners, fd.owner)
+	}
+	sort.Strings(owners)
+	return &fsstats.Orphan{
+		ID:             o.id,
+		OriginalPath:   o.path,
+		FileType:       o.fileType,
+		DeletedAt:      o.deletedAt,
+		Size:           size,
+		AllocatedBytes: allocated,
+		Descriptors:    len(fds),
+		Owners:         owners,
+	}
+}
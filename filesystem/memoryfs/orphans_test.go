@@ -0,0 +1,145 @@
+package memoryfs_test
+
+import (
+	"errors"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/memoryfs"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrphans(t *testing.T) {
+	now := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	cases := []struct {
+		CaseName   string
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem, path *fspath.FileSystemPath)
+	}{
+		{
+			CaseName: "Removed open file is listed with its owners",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, path *fspath.FileSystemPath) {
+				_, err := fs.OpenFileWithOwner(path, file.OpenReadWrite, "s2")
+				assert.Nil(t, err)
+				_, err = fs.OpenFileWithOwner(path, file.OpenRead, "s1")
+				assert.Nil(t, err)
+				_, err = fs.OpenFile(path, file.OpenRead)
+				assert.Nil(t, err)
+				assert.Empty(t, fs.ListOrphans())
+
+				_, err = fs.Remove(path)
+				assert.Nil(t, err)
+				orphans := fs.ListOrphans()
+				assert.Len(t, orphans, 1)
+				assert.Equal(t, "/file", orphans[0].OriginalPath)
+				assert.Equal(t, file.RegularFile, orphans[0].FileType)
+				assert.Equal(t, now, orphans[0].DeletedAt)
+				assert.Equal(t, 5, orphans[0].Size)
+				assert.Equal(t, 3, orphans[0].Descriptors)
+				assert.Equal(t, []string{"s1", "s2"}, orphans[0].Owners)
+
+				stats := fs.StatFS()
+				assert.Equal(t, 1, stats.OrphanedFiles)
+				assert.Equal(t, orphans[0].AllocatedBytes, stats.OrphanedBytes)
+			},
+		},
+		{
+			CaseName: "Removed file stays writable until closed",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, path *fspath.FileSystemPath) {
+				fd, err := fs.OpenFileWithOwner(path, file.OpenReadWrite, "s1")
+				assert.Nil(t, err)
+				_, err = fs.Remove(path)
+				assert.Nil(t, err)
+
+				_, err = fs.WriteAt(fd, []byte("world"), 5)
+				assert.Nil(t, err)
+				buff := make([]byte, 10)
+				n, err := fs.ReadAt(fd, buff, 0)
+				assert.Nil(t, err)
+				assert.Equal(t, "helloworld", string(buff[:n]))
+				assert.Equal(t, 10, fs.ListOrphans()[0].Size)
+
+				fs.Close(fd)
+				assert.Empty(t, fs.ListOrphans())
+				assert.Equal(t, 0, fs.StatFS().OrphanedBytes)
+			},
+		},
+		{
+			CaseName: "Reclaim closes every descriptor",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, path *fspath.FileSystemPath) {
+				fd1, err := fs.OpenFileWithOwner(path, file.OpenReadWrite, "s1")
+				assert.Nil(t, err)
+				fd2, err := fs.OpenFileWithOwner(path, file.OpenRead, "s2")
+				assert.Nil(t, err)
+				_, err = fs.Remove(path)
+				assert.Nil(t, err)
+
+				orphan, err := fs.ReclaimOrphan(fs.ListOrphans()[0].ID)
+				assert.Nil(t, err)
+				assert.Equal(t, "/file", orphan.OriginalPath)
+				assert.Equal(t, 2, orphan.Descriptors)
+				assert.Empty(t, fs.ListOrphans())
+
+				stats := fs.StatFS()
+				assert.Equal(t, 0, stats.OpenDescriptors)
+				assert.Equal(t, 0, stats.OrphanedFiles)
+				assert.Equal(t, 0, stats.OrphanedBytes)
+				_, err = fs.ReadAt(fd1, make([]byte, 5), 0)
+				assert.True(t, errors.Is(err, fserrors.ErrNotOpen))
+				fs.Close(fd2)
+			},
+		},
+		{
+			CaseName: "Reclaim unknown orphan",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, path *fspath.FileSystemPath) {
+				_, err := fs.ReclaimOrphan("1")
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+			},
+		},
+		{
+			CaseName: "Hard link keeps the file reachable",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, path *fspath.FileSystemPath) {
+				link, _ := fspath.NewFileSystemPath("/link", nil)
+				_, err := fs.CreateHardLink(path, link)
+				assert.Nil(t, err)
+				_, err = fs.OpenFile(path, file.OpenRead)
+				assert.Nil(t, err)
+
+				_, err = fs.Remove(path)
+				assert.Nil(t, err)
+				assert.Empty(t, fs.ListOrphans())
+
+				_, err = fs.Remove(link)
+				assert.Nil(t, err)
+				orphans := fs.ListOrphans()
+				assert.Len(t, orphans, 1)
+				assert.Equal(t, "/link", orphans[0].OriginalPath)
+				assert.Empty(t, orphans[0].Owners)
+			},
+		},
+		{
+			CaseName: "Closed file is not an orphan",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, path *fspath.FileSystemPath) {
+				fd, err := fs.OpenFile(path, file.OpenRead)
+				assert.Nil(t, err)
+				fs.Close(fd)
+				_, err = fs.Remove(path)
+				assert.Nil(t, err)
+				assert.Empty(t, fs.ListOrphans())
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.CaseName, func(t *testing.T) {
+			fs := newFileSystem(memoryfs.WithClock(func() time.Time { return now }))
+			path, _ := fspath.NewFileSystemPath("/file", nil)
+			if err := fs.AppendAll(path, []byte("hello")); err != nil {
+				t.Fatal(err)
+			}
+			c.Assertions(t, fs, path)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package memoryfs
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DEFAULT_PATH_CACHE_SIZE is the default maximum number of directories in the path cache
+const DEFAULT_PATH_CACHE_SIZE = 4096
+
+// pathCache maps the absolute paths of directories to the resolved directories,
+// so that deep paths don't walk every parent directory and symbolic link on each lookup.
+// Only the directories of path.Dir() are cached, the base is always looked up,
+// so creating or removing regular files doesn't change the cached entries.
+// Removing, moving or trashing a directory or a symbolic link may change the resolution
+// of any cached path, even through other symbolic links, so every entry is dropped.
+// When full, the least recently used entry is evicted.
+// The cache has its own lock, since lookups run with the fs read lock.
+type pathCache struct {
+	sync.Mutex
+	// maximum number of entries, zero disables the cache
+	capacity int
+	entries  map[string]*list.Element
+	// entries from the most to the least recently used
+	lru *list.List
+}
+
+type pathCacheEntry struct {
+	key string
+	dir *inMemoryFile
+}
+
+func newPathCache(capacity int) *pathCache {
+	return &pathCache{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		lru:      list.New(),
+	}
+}
+
+// get returns the directory cached for the key.
+// This implementation is thread safe.
+func (c *pathCache) get(key string) (*inMemoryFile, bool) {
+	c.Lock()
+	defer c.Unlock()
+	elem, found := c.entries[key]
+	if !found {
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*pathCacheEntry).dir, true
+}
+
+// put caches the directory for the key, evicting the least recently used entry if full.
+// This implementation is thread safe.
+func (c *pathCache) put(key string, dir *inMemoryFile) {
+	if c.capacity == 0 {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	if elem, found := c.entries[key]; found {
+		elem.Value.(*pathCacheEntry).dir = dir
+		c.lru.MoveToFront(elem)
+		return
+	}
+	if c.lru.Len() >= c.capacity {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*pathCacheEntry).key)
+	}
+	c.entries[key] = c.lru.PushFront(&pathCacheEntry{key: key, dir: dir})
+}
+
+// invalidate drops every entry.
+// This implementation is thread safe.
+func (c *pathCache) invalidate() {
+	c.Lock()
+	defer c.Unlock()
+	if c.lru.Len() == 0 {
+		return
+	}
+	c.entries = map[string]*list.Element{}
+	c.lru.Init()
+}
+
+// len returns the number of cached directories.
+// This implementation is thread safe.
+func (c *pathCache) len() int {
+	c.Lock()
+	defer c.Unlock()
+	return c.lru.Len()
+}
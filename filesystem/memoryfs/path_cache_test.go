@@ -0,0 +1,203 @@
+package memoryfs_test
+
+import (
+	"fmt"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsvirtual"
+	"material/filesystem/filesystem/memoryfs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// initializePathCacheTree creates the tree:
+// /a/b/c/file, /x/c/file, /link -> /a/b
+// and resolves the paths once, so that their directories are cached
+func initializePathCacheTree(opts ...memoryfs.Option) (*memoryfs.MemoryFileSystem, error) {
+	fs := newFileSystem(opts...)
+	for _, path := range []string{"/a/b/c/file", "/x/c/file"} {
+		p, _ := fspath.NewFileSystemPath(path, nil)
+		if err := fs.AppendAll(p, []byte(path)); err != nil {
+			return nil, err
+		}
+	}
+	link, _ := fspath.NewFileSystemPath("/link", nil)
+	target, _ := fspath.NewFileSystemPath("/a/b", nil)
+	if _, err := fs.CreateSymbolicLink(target, link); err != nil {
+		return nil, err
+	}
+	for _, path := range []string{"/a/b/c/file", "/x/c/file", "/link/c/file"} {
+		p, _ := fspath.NewFileSystemPath(path, nil)
+		if _, err := fs.ReadAll(p); err != nil {
+			return nil, err
+		}
+	}
+	return fs, nil
+}
+
+func TestPathCache(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Options    []memoryfs.Option
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem)
+	}{
+		{
+			CaseName: "Moved directory",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				src, _ := fspath.NewFileSystemPath("/a/b", nil)
+				dest, _ := fspath.NewFileSystemPath("/y", nil)
+				_, err := fs.Move(src, dest)
+				assert.Nil(t, err)
+
+				assertExists(t, fs, "/a/b/c/file", false)
+				assertExists(t, fs, "/link/c/file", false)
+				assertContent(t, fs, "/y/c/file", []byte("/a/b/c/file"))
+			},
+		},
+		{
+			CaseName: "Directory replaced by another one",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/a/b/c", nil)
+				_, err := fs.RemoveAll(p)
+				assert.Nil(t, err)
+				src, _ := fspath.NewFileSystemPath("/x/c", nil)
+				_, err = fs.Move(src, p)
+				assert.Nil(t, err)
+
+				assertContent(t, fs, "/a/b/c/file", []byte("/x/c/file"))
+				assertContent(t, fs, "/link/c/file", []byte("/x/c/file"))
+				assertExists(t, fs, "/x/c/file", false)
+			},
+		},
+		{
+			CaseName: "Symbolic link replaced",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				link, _ := fspath.NewFileSystemPath("/link", nil)
+				_, err := fs.Remove(link)
+				assert.Nil(t, err)
+				assertExists(t, fs, "/link/c/file", false)
+
+				target, _ := fspath.NewFileSystemPath("/x", nil)
+				_, err = fs.CreateSymbolicLink(target, link)
+				assert.Nil(t, err)
+				assertContent(t, fs, "/link/c/file", []byte("/x/c/file"))
+			},
+		},
+		{
+			CaseName: "Symbolic link target moved",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				src, _ := fspath.NewFileSystemPath("/a", nil)
+				dest, _ := fspath.NewFileSystemPath("/x/a", nil)
+				_, err := fs.Move(src, dest)
+				assert.Nil(t, err)
+				assertExists(t, fs, "/link/c/file", false)
+			},
+		},
+		{
+			CaseName: "Trashed and restored directory",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/a/b", nil)
+				entry, err := fs.Trash(p, "user", true)
+				assert.Nil(t, err)
+				assertExists(t, fs, "/a/b/c/file", false)
+
+				_, err = fs.Restore("user", entry.ID, nil)
+				assert.Nil(t, err)
+				assertContent(t, fs, "/link/c/file", []byte("/a/b/c/file"))
+			},
+		},
+		{
+			CaseName: "Mounted directory",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/a/b/mnt", nil)
+				err := fs.Mount(p, fsvirtual.Dir{"sub": fsvirtual.Dir{"file": fsvirtual.Null}})
+				assert.Nil(t, err)
+				assertExists(t, fs, "/link/mnt/sub/file", true)
+
+				_, err = fs.RemoveAll(p)
+				assert.NotNil(t, err)
+				assertExists(t, fs, "/link/mnt/sub/file", true)
+			},
+		},
+		{
+			CaseName: "Relative paths",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				workDir, _ := fspath.NewFileSystemPath("/a/b/c", nil)
+				dir, err := fs.GetDirectory(workDir)
+				assert.Nil(t, err)
+
+				for _, path := range []string{".", "..", "file", "../c/file", "../../b"} {
+					p, _ := fspath.NewFileSystemPath(path, dir)
+					info, err := fs.Stat(p)
+					assert.Nil(t, err, path)
+					assert.Equal(t, p.AbsolutePath(), info.AbsolutePath(), path)
+				}
+			},
+		},
+		{
+			CaseName: "Bounded size",
+			Options:  []memoryfs.Option{memoryfs.WithPathCacheSize(2)},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				assert.Equal(t, 2, fs.StatFS().CachedPaths)
+				assertContent(t, fs, "/a/b/c/file", []byte("/a/b/c/file"))
+				assertContent(t, fs, "/x/c/file", []byte("/x/c/file"))
+				assertContent(t, fs, "/link/c/file", []byte("/a/b/c/file"))
+				assert.Equal(t, 2, fs.StatFS().CachedPaths)
+
+				p, _ := fspath.NewFileSystemPath("/x", nil)
+				_, err := fs.RemoveAll(p)
+				assert.Nil(t, err)
+				assert.Equal(t, 0, fs.StatFS().CachedPaths)
+			},
+		},
+		{
+			CaseName: "Disabled cache",
+			Options:  []memoryfs.Option{memoryfs.WithPathCacheSize(0)},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				assert.Equal(t, 0, fs.StatFS().CachedPaths)
+				assertContent(t, fs, "/link/c/file", []byte("/a/b/c/file"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.CaseName, func(t *testing.T) {
+			fs, err := initializePathCacheTree(c.Options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.Assertions(t, fs)
+		})
+	}
+}
+
+// BenchmarkTraverseToBase resolves a file at the bottom of a deep tree,
+// which is what every path based operation does before running
+func BenchmarkTraverseToBase(b *testing.B) {
+	for _, depth := range []int{5, 20, 50} {
+		for _, cacheSize := range []int{memoryfs.DEFAULT_PATH_CACHE_SIZE, 0} {
+			name := fmt.Sprintf("depth=%d/cached", depth)
+			if cacheSize == 0 {
+				name = fmt.Sprintf("depth=%d/uncached", depth)
+			}
+			b.Run(name, func(b *testing.B) {
+				fs, err := memoryfs.NewMemoryFileSystem(memoryfs.WithPathCacheSize(cacheSize))
+				if err != nil {
+					b.Fatal(err)
+				}
+				p, _ := fspath.NewFileSystemPath(strings.Repeat("/directory", depth)+"/file", nil)
+				if err := fs.AppendAll(p, []byte("content")); err != nil {
+					b.Fatal(err)
+				}
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if _, err := fs.Stat(p); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
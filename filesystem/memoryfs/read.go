@@ -1,9 +1,11 @@
 package memoryfs
 
 import (
+	"context"
 	"material/filesystem/filesystem/file"
 	"material/filesystem/filesystem/fserrors"
 	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsvirtual"
 )
 
 // ReadAll reads the named file and returns the contents.
@@ -12,45 +14,72 @@ import (
 // Returns an error when:
 // - the file does not exist
 // - the file is not a regular file
+// - the file is virtual and has no end
 func (fs *MemoryFileSystem) ReadAll(path *fspath.FileSystemPath) ([]byte, error) {
 	fs.Lock()
 
 	fileToRead, err := fs.traverseToBase(path)
 	if err != nil {
 		fs.Unlock()
-		return nil, err
+		return nil, pathError("read", path, err)
 	}
 
-	if fileToRead.info.fileType != file.RegularFile {
+	if err := checkRegularFile(fileToRead); err != nil {
 		fs.Unlock()
-		return nil, fserrors.ErrInvalidFileType
+		return nil, pathError("read", path, err)
 	}
 
-	descriptor, err := fs.doOpen(fileToRead)
-	fs.Unlock()
+	descriptor, err := fs.openAndUnlock(context.Background(), fileToRead, file.OpenRead, "")
 	if err != nil {
-		return nil, err
+		return nil, pathError("read", path, err)
 	}
 	defer fs.Close(descriptor)
 
 	var buff []byte
-	fs.doRead(descriptor, func(fd *fileDescriptor) (int, error) {
-		buff = make([]byte, fd.data.Size())
+	_, err = fs.doRead(descriptor, func(fd *fileDescriptor) (int, error) {
+		size := fd.size()
+		if size == fsvirtual.Unbounded {
+			return 0, fserrors.ErrOperationNotSupported
+		}
+		buff = make([]byte, size)
 		return fd.Read(buff)
 	})
+	if err != nil {
+		return nil, pathError("read", path, err)
+	}
 
 	return buff, nil
 }
 
 // Read reads up to len(buff) bytes into buff.
+// Reading a fifo waits for data and returns 0 bytes once every writer is closed.
 // This implementation is thread safe.
 //
 // Returns an error when:
 // - the file is not open
+// - the file is not open for reading
+// - the fifo is empty in non-blocking mode
 func (fs *MemoryFileSystem) Read(descriptor string, buff []byte) (int, error) {
-	return fs.doRead(descriptor, func(fd *fileDescriptor) (int, error) {
+	nRead, err := fs.doRead(descriptor, func(fd *fileDescriptor) (int, error) {
 		return fd.Read(buff)
 	})
+	return nRead, nameError("read", descriptor, err)
+}
+
+// ReadContext reads up to len(buff) bytes into buff like Read.
+// Reading a fifo stops waiting for data when ctx is done.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the file is not open
+// - the file is not open for reading
+// - the fifo is empty in non-blocking mode
+// - ctx is done while waiting for data
+func (fs *MemoryFileSystem) ReadContext(ctx context.Context, descriptor string, buff []byte) (int, error) {
+	nRead, err := fs.doRead(descriptor, func(fd *fileDescriptor) (int, error) {
+		return fd.ReadContext(ctx, buff)
+	})
+	return nRead, nameError("read", descriptor, err)
 }
 
 // ReadAt reads up to len(buff) bytes starting at offset into buff.
@@ -58,10 +87,13 @@ func (fs *MemoryFileSystem) Read(descriptor string, buff []byte) (int, error) {
 //
 // Returns an error when:
 // - the file is not open
+// - the file is not open for reading
+// - the file is a fifo
 func (fs *MemoryFileSystem) ReadAt(descriptor string, buff []byte, offset int) (int, error) {
-	return fs.doRead(descriptor, func(fd *fileDescriptor) (int, error) {
+	nRead, err := fs.doRead(descriptor, func(fd *fileDescriptor) (int, error) {
 		return fd.ReadAt(buff, offset)
 	})
+	return nRead, nameError("read", descriptor, err)
 }
 
 func (fs *MemoryFileSystem) doRead(fileDescriptor string, readFn func(fd *fileDescriptor) (int, error)) (int, error) {
@@ -75,6 +107,12 @@ func (fs *MemoryFileSystem) doRead(fileDescriptor string, readFn func(fd *fileDe
 		return 0, fserrors.ErrNotOpen
 	}
 
+	// Fifos and virtual files have their own locks, a fifo may wait for the writers
+	if fd.data.pipe != nil || fd.handle != nil {
+		fs.openFiles.RUnlock()
+		return readFn(fd)
+	}
+
 	// Read lock file
 	fd.data.RLock()
 	defer fd.data.RUnlock()
@@ -1,6 +1,7 @@
 package memoryfs_test
 
 import (
+	"errors"
 	"material/filesystem/filesystem/file"
 	"material/filesystem/filesystem/fserrors"
 	"material/filesystem/filesystem/fspath"
@@ -21,7 +22,7 @@ func TestReadAll(t *testing.T) {
 			CaseName: "Read from existing file - absolute path",
 			Path:     "/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -41,19 +42,19 @@ func TestReadAll(t *testing.T) {
 			CaseName: "Read from missing file - absolute path",
 			Path:     "/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				return fs, nil, nil
 			},
 			Assertions: func(t *testing.T, data []byte, err error) {
 				assert.NotNil(t, err)
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 			},
 		},
 		{
 			CaseName: "Read directory - absolute path",
 			Path:     "/dir1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -62,14 +63,14 @@ func TestReadAll(t *testing.T) {
 			},
 			Assertions: func(t *testing.T, data []byte, err error) {
 				assert.NotNil(t, err)
-				assert.Equal(t, err, fserrors.ErrInvalidFileType)
+				assert.True(t, errors.Is(err, fserrors.ErrIsDir))
 			},
 		},
 		{
 			CaseName: "Read from existing file - relative path",
 			Path:     "file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -89,19 +90,19 @@ func TestReadAll(t *testing.T) {
 			CaseName: "Read from missing file - relative path",
 			Path:     "/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				return fs, fs.DefaultWorkingDirectory(), nil
 			},
 			Assertions: func(t *testing.T, data []byte, err error) {
 				assert.NotNil(t, err)
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 			},
 		},
 		{
 			CaseName: "Read directory - relative path",
 			Path:     "./dir1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -110,14 +111,14 @@ func TestReadAll(t *testing.T) {
 			},
 			Assertions: func(t *testing.T, data []byte, err error) {
 				assert.NotNil(t, err)
-				assert.Equal(t, err, fserrors.ErrInvalidFileType)
+				assert.True(t, errors.Is(err, fserrors.ErrIsDir))
 			},
 		},
 		{
 			CaseName: "Read from symbolic link - relative path",
 			Path:     "file1-link",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p1); err != nil {
 					return nil, nil, err
@@ -165,7 +166,7 @@ func TestReadAt(t *testing.T) {
 			Offset:   3,
 			BuffSize: 5,
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if err := fs.AppendAll(p, []byte("Hello world!")); err != nil {
 					return nil, nil, err
@@ -183,7 +184,7 @@ func TestReadAt(t *testing.T) {
 			Offset:   3,
 			BuffSize: 5,
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -202,7 +203,7 @@ func TestReadAt(t *testing.T) {
 			Offset:   15,
 			BuffSize: 5,
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if err := fs.AppendAll(p, []byte("Hello world!")); err != nil {
 					return nil, nil, err
@@ -221,7 +222,7 @@ func TestReadAt(t *testing.T) {
 			Offset:   9,
 			BuffSize: 20,
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if err := fs.AppendAll(p, []byte("Hello world!")); err != nil {
 					return nil, nil, err
@@ -267,7 +268,7 @@ func TestReadAtClosedFile(t *testing.T) {
 			CaseName: "Read closed file - absolute path",
 			Path:     "/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if err := fs.AppendAll(p, []byte("Hello world!")); err != nil {
 					return nil, nil, err
@@ -276,7 +277,7 @@ func TestReadAtClosedFile(t *testing.T) {
 			},
 			Assertions: func(t *testing.T, nBytes int, err error) {
 				assert.NotNil(t, err)
-				assert.Equal(t, fserrors.ErrNotOpen, err)
+				assert.True(t, errors.Is(err, fserrors.ErrNotOpen))
 				assert.Equal(t, 0, nBytes)
 			},
 		},
@@ -316,7 +317,7 @@ func TestReadAtMovedOrRemovedFile(t *testing.T) {
 				return err
 			},
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if err := fs.AppendAll(p, []byte("Hello world!")); err != nil {
 					return nil, nil, err
@@ -339,7 +340,7 @@ func TestReadAtMovedOrRemovedFile(t *testing.T) {
 				return err
 			},
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -369,7 +370,7 @@ func TestReadAtMovedOrRemovedFile(t *testing.T) {
 				return err
 			},
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if err := fs.AppendAll(p, []byte("Hello world!")); err != nil {
 					return nil, nil, err
@@ -416,7 +417,7 @@ func TestRead(t *testing.T) {
 			Path:     "/file1",
 			BuffSize: 5,
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if err := fs.AppendAll(p, []byte("Hello world!")); err != nil {
 					return nil, nil, err
@@ -433,7 +434,7 @@ func TestRead(t *testing.T) {
 			Path:     "/file1",
 			BuffSize: 5,
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -451,7 +452,7 @@ func TestRead(t *testing.T) {
 			Path:     "/file1",
 			BuffSize: 20,
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if err := fs.AppendAll(p, []byte("Hello world!")); err != nil {
 					return nil, nil, err
@@ -485,7 +486,7 @@ func TestRead(t *testing.T) {
 }
 
 func TestReadInChuncks(t *testing.T) {
-	fs := memoryfs.NewMemoryFileSystem()
+	fs := newFileSystem()
 	p, _ := fspath.NewFileSystemPath("/file1", nil)
 	if err := fs.AppendAll(p, []byte("Hello world!")); err != nil {
 		t.Fatal("error initializing file system")
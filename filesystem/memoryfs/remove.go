@@ -4,6 +4,7 @@ import (
 	"material/filesystem/filesystem/file"
 	"material/filesystem/filesystem/fserrors"
 	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsreport"
 )
 
 // Remove removes the file located at the specified path.
@@ -11,11 +12,13 @@ import (
 // closed it, i.e. has been removed from the open files table.
 // This means that is possible to write to a deleted file, but the file will be
 // discarded as soon as it is closed.
+// Use Rmdir to remove empty directories.
 // This implementation is thread safe.
 //
 // Returns an error when:
 // - The file is a directory
 // - The file does not exist
+// - The file is virtual
 func (fs *MemoryFileSystem) Remove(path *fspath.FileSystemPath) (file.FileInfo, error) {
 	return fs.removeFileWithLock(path, false)
 }
@@ -25,19 +28,55 @@ func (fs *MemoryFileSystem) Remove(path *fspath.FileSystemPath) (file.FileInfo,
 // closed it, i.e. has been removed from the open files table.
 // This means that is possible to write to a deleted file, but the file will be
 // discarded as soon as it is closed.
+// RemoveAll stops at the first error encountered.
 // Removing "/" is not supported.
 // This implementation is thread safe.
 //
 // Returns an error when:
 // - The file does not exist
+// - The file or any file in the directory is virtual
 func (fs *MemoryFileSystem) RemoveAll(path *fspath.FileSystemPath) (file.FileInfo, error) {
 	return fs.removeFileWithLock(path, true)
 }
 
-func (fs *MemoryFileSystem) removeFileWithLock(path *fspath.FileSystemPath, isRecursive bool) (file.FileInfo, error) {
-	// RW lock the fs
+// RemoveAllWithReport removes the file or directory located at the specified path
+// like RemoveAll, and reports the removed files and the failures.
+// If continueOnError is true, it removes every file it can: the files that
+// can't be removed are kept together with their parent directories.
+// The returned error is the first failure.
+// This implementation is thread safe.
+func (fs *MemoryFileSystem) RemoveAllWithReport(path *fspath.FileSystemPath, continueOnError bool) (*fsreport.Report, error) {
 	fs.Lock()
 	defer fs.Unlock()
+	r := newReporter(continueOnError)
+	_, err := fs.removeFileLockFree(path, true, r)
+	if err == nil {
+		err = r.err
+	}
+	return r.report, pathError("remove", path, err)
+}
+
+// Rmdir removes the empty directory located at the specified path.
+// Removing "/" is not supported.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - The directory does not exist
+// - The file is not a directory
+// - The directory is not empty
+// - The directory is virtual
+func (fs *MemoryFileSystem) Rmdir(path *fspath.FileSystemPath) (file.FileInfo, error) {
+	fs.Lock()
+	defer fs.Unlock()
+	info, err := fs.rmdirLockFree(path)
+	return info, pathError("rmdir", path, err)
+}
+
+// This method should be called only if the caller has already acquired a lock
+func (fs *MemoryFileSystem) rmdirLockFree(path *fspath.FileSystemPath) (file.FileInfo, error) {
+	if err := checkFilePath(path); err != nil {
+		return nil, err
+	}
 
 	// find where to remove directory
 	pathEnd, err := fs.traverseDirs(path)
@@ -45,33 +84,78 @@ func (fs *MemoryFileSystem) removeFileWithLock(path *fspath.FileSystemPath, isRe
 		return nil, err
 	}
 
-	return fs.removeFile(path.Base(), pathEnd, isRecursive)
+	dirToRemove, found := fs.lookup(pathEnd, path.Base())
+	if !found {
+		return nil, fserrors.ErrNotExist
+	}
+	if dirToRemove.isVirtual {
+		return nil, fserrors.ErrOperationNotSupported
+	}
+	if dirToRemove.data.fileType != file.Directory {
+		return nil, fserrors.ErrNotDir
+	}
+	if len(dirToRemove.childNames()) > 0 {
+		return nil, fserrors.ErrNotEmpty
+	}
+
+	fs.detachFromParent(dirToRemove)
+	fs.markDeleted(dirToRemove)
+	return dirToRemove.info, nil
 }
 
-// removeFile removes the file from the fs tree
-func (fs *MemoryFileSystem) removeFile(fileName string, pathEnd *inMemoryFile, isRecursive bool) (file.FileInfo, error) {
+func (fs *MemoryFileSystem) removeFileWithLock(path *fspath.FileSystemPath, isRecursive bool) (file.FileInfo, error) {
+	// RW lock the fs
+	fs.Lock()
+	defer fs.Unlock()
+	info, err := fs.removeFileLockFree(path, isRecursive, newReporter(false))
+	return info, pathError("remove", path, err)
+}
+
+// This method should be called only if the caller has already acquired a lock
+func (fs *MemoryFileSystem) removeFileLockFree(path *fspath.FileSystemPath, isRecursive bool, r *reporter) (file.FileInfo, error) {
+	// find where to remove directory
+	pathEnd, err := fs.traverseDirs(path)
+	if err != nil {
+		return nil, err
+	}
+
 	// check if file exists
-	fileToRemove, found := pathEnd.fileMap[fileName]
+	fileToRemove, found := fs.lookup(pathEnd, path.Base())
 	if !found {
 		return nil, fserrors.ErrNotExist
 	}
 
+	info, err := fs.removeFile(fileToRemove, isRecursive, r)
+	if err := r.fail(fileToRemove.info.AbsolutePath(), err); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// removeFile removes the file from the fs tree
+func (fs *MemoryFileSystem) removeFile(fileToRemove *inMemoryFile, isRecursive bool, r *reporter) (file.FileInfo, error) {
+	if fileToRemove.isVirtual {
+		return nil, fserrors.ErrOperationNotSupported
+	}
+
 	// handle directories
-	if fileToRemove.info.fileType == file.Directory {
-		return fs.removeDirectory(fileToRemove, isRecursive)
+	if fileToRemove.data.fileType == file.Directory {
+		return fs.removeDirectory(fileToRemove, isRecursive, r)
 	}
 
 	// unlink regular file (or symlink)
 	fs.detachFromParent(fileToRemove)
 	// mark file for deletion
-	fileToRemove.isDeleted = true
+	fs.markDeleted(fileToRemove)
+	r.succeeded(fileToRemove.info.AbsolutePath())
 	return fileToRemove.Info(), nil
 }
 
-// removeDirectory recursively removes any children file and directories
-func (fs *MemoryFileSystem) removeDirectory(fileToRemove *inMemoryFile, isRecursive bool) (file.FileInfo, error) {
+// removeDirectory recursively removes any children file and directories, then the directory.
+// The directory is kept if any of its children can't be removed.
+func (fs *MemoryFileSystem) removeDirectory(fileToRemove *inMemoryFile, isRecursive bool, r *reporter) (file.FileInfo, error) {
 	if !isRecursive {
-		return nil, fserrors.ErrInvalidFileType
+		return nil, fserrors.ErrIsDir
 	}
 
 	// deleting filesystem root is not supported at the moment
@@ -79,25 +163,50 @@ func (fs *MemoryFileSystem) removeDirectory(fileToRemove *inMemoryFile, isRecurs
 		return nil, fserrors.ErrOperationNotSupported
 	}
 
-	// remove current file
-	fs.detachFromParent(fileToRemove)
-	fileToRemove.isDeleted = true
-
 	// remove all children
-	err := fs.visitDir(fileToRemove, func(_ string, file *inMemoryFile) error {
-		_, err := fs.removeDirectory(file, true)
-		return err
+	err := fs.visitDirInOrder(fileToRemove, func(_ string, child *inMemoryFile) error {
+		_, err := fs.removeFile(child, true, r)
+		return r.fail(child.info.AbsolutePath(), err)
 	})
 	if err != nil {
 		return nil, err
 	}
+	if len(fileToRemove.childNames()) > 0 {
+		return nil, fserrors.ErrNotEmpty
+	}
 
+	// remove current file
+	fs.detachFromParent(fileToRemove)
+	fs.markDeleted(fileToRemove)
+	r.succeeded(fileToRemove.info.AbsolutePath())
 	return fileToRemove.info, nil
 }
 
 func (fs *MemoryFileSystem) detachFromParent(fileToRemove *inMemoryFile) {
 	parent := fileToRemove.fileMap[".."]
+	fs.journal.saveEntry(parent)
+	fs.journal.saveEntry(fileToRemove)
+	fs.journal.saveInode(parent.data)
 
-	delete(parent.fileMap, fileToRemove.info.Name())
+	parent.removeChild(fs.nameKey(fileToRemove.info.Name()))
 	delete(fileToRemove.fileMap, "..")
+	fileToRemove.info.setDetached(fileToRemove.info.AbsolutePath())
+	parent.data.touch()
+	if fileToRemove.data.fileType == file.Directory || fileToRemove.data.fileType == file.SymbolicLink {
+		fs.pathCache.invalidate()
+	}
+}
+
+// markDeleted marks the file for deletion and removes one link to its data.
+// If the last link is removed while the file is open, the file becomes an orphan.
+func (fs *MemoryFileSystem) markDeleted(fileToRemove *inMemoryFile) {
+	fs.journal.saveEntry(fileToRemove)
+	fs.journal.saveInode(fileToRemove.data)
+	fileToRemove.isDeleted = true
+	fs.openFiles.Lock()
+	defer fs.openFiles.Unlock()
+	fileToRemove.data.updateLinks(-1)
+	if fileToRemove.data.isOrphan() {
+		fs.addOrphan(fileToRemove)
+	}
 }
@@ -22,7 +22,7 @@ func TestRemove(t *testing.T) {
 			CaseName: "Remove file in root using absolute path",
 			Path:     "/target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -48,7 +48,7 @@ func TestRemove(t *testing.T) {
 			CaseName: "Remove file in subdir using absolute path",
 			Path:     "/dir1/target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -81,7 +81,7 @@ func TestRemove(t *testing.T) {
 			CaseName: "Remove directory using absolute path",
 			Path:     "/target/target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -101,7 +101,7 @@ func TestRemove(t *testing.T) {
 				assert.Nil(t, info)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrInvalidFileType)
+				assert.True(t, errors.Is(err, fserrors.ErrIsDir))
 
 				p, _ := fspath.NewFileSystemPath("/", nil)
 				files, _ := fs.FindFiles("target", p)
@@ -117,7 +117,7 @@ func TestRemove(t *testing.T) {
 			CaseName: "Remove missing file using absolute path",
 			Path:     "/dir1/dir3/target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -137,7 +137,7 @@ func TestRemove(t *testing.T) {
 				assert.Nil(t, info)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 
 				p, _ := fspath.NewFileSystemPath("/", nil)
 				files, _ := fs.FindFiles("target", p)
@@ -153,7 +153,7 @@ func TestRemove(t *testing.T) {
 			CaseName: "Remove symlink using absolute path",
 			Path:     "/target-link",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -189,7 +189,7 @@ func TestRemove(t *testing.T) {
 			CaseName: "Remove file in root using relative path",
 			Path:     "../../../target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -220,7 +220,7 @@ func TestRemove(t *testing.T) {
 			CaseName: "Remove file in subdir using relative path",
 			Path:     "./target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -258,7 +258,7 @@ func TestRemove(t *testing.T) {
 			CaseName: "Remove directory using relative path",
 			Path:     ".",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -283,7 +283,7 @@ func TestRemove(t *testing.T) {
 				assert.Nil(t, info)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrInvalidFileType)
+				assert.True(t, errors.Is(err, fserrors.ErrIsDir))
 
 				p, _ := fspath.NewFileSystemPath("/", nil)
 				files, _ := fs.FindFiles("target", p)
@@ -299,7 +299,7 @@ func TestRemove(t *testing.T) {
 			CaseName: "Remove missing file using releative path",
 			Path:     "../target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -324,7 +324,7 @@ func TestRemove(t *testing.T) {
 				assert.Nil(t, info)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 
 				p, _ := fspath.NewFileSystemPath("/", nil)
 				files, _ := fs.FindFiles("target", p)
@@ -340,7 +340,7 @@ func TestRemove(t *testing.T) {
 			CaseName: "Working directory previously deleted",
 			Path:     "../target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -369,7 +369,7 @@ func TestRemove(t *testing.T) {
 				assert.Nil(t, info)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrInvalidWorkingDirectory)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalidWorkingDirectory))
 
 				p, _ := fspath.NewFileSystemPath("/", nil)
 				files, _ := fs.FindFiles("target", p)
@@ -403,7 +403,7 @@ func TestRemoveAll(t *testing.T) {
 			CaseName: "Remove file in root using absolute path",
 			Path:     "/target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -429,7 +429,7 @@ func TestRemoveAll(t *testing.T) {
 			CaseName: "Remove direcoty in subdir using absolute path",
 			Path:     "/target/target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -461,7 +461,7 @@ func TestRemoveAll(t *testing.T) {
 			CaseName: "Remove missing directory using absolute path",
 			Path:     "/dir1/dir3/target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -481,7 +481,7 @@ func TestRemoveAll(t *testing.T) {
 				assert.Nil(t, info)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 
 				p, _ := fspath.NewFileSystemPath("/", nil)
 				files, _ := fs.FindFiles("target", p)
@@ -497,7 +497,7 @@ func TestRemoveAll(t *testing.T) {
 			CaseName: "Removing root using absolute path",
 			Path:     "/",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -521,7 +521,7 @@ func TestRemoveAll(t *testing.T) {
 				assert.Nil(t, info)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrOperationNotSupported)
+				assert.True(t, errors.Is(err, fserrors.ErrOperationNotSupported))
 
 				p, _ := fspath.NewFileSystemPath("/", nil)
 				files, _ := fs.FindFiles("target", p)
@@ -537,7 +537,7 @@ func TestRemoveAll(t *testing.T) {
 			CaseName: "Remove directory in root using relative path",
 			Path:     "../../../target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -568,7 +568,7 @@ func TestRemoveAll(t *testing.T) {
 			CaseName: "Remove directory in subdir using relative path",
 			Path:     "..",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -605,7 +605,7 @@ func TestRemoveAll(t *testing.T) {
 			CaseName: "Remove missing file using releative path",
 			Path:     "../target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -630,7 +630,7 @@ func TestRemoveAll(t *testing.T) {
 				assert.Nil(t, info)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 
 				p, _ := fspath.NewFileSystemPath("/", nil)
 				files, _ := fs.FindFiles("target", p)
@@ -646,7 +646,7 @@ func TestRemoveAll(t *testing.T) {
 			CaseName: "Removing root using relative file",
 			Path:     "../../../../..",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -671,7 +671,7 @@ func TestRemoveAll(t *testing.T) {
 				assert.Nil(t, info)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrOperationNotSupported)
+				assert.True(t, errors.Is(err, fserrors.ErrOperationNotSupported))
 
 				p, _ := fspath.NewFileSystemPath("/", nil)
 				files, _ := fs.FindFiles("target", p)
@@ -687,7 +687,7 @@ func TestRemoveAll(t *testing.T) {
 			CaseName: "Working directory previously deleted",
 			Path:     "../target",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2/target", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -716,7 +716,7 @@ func TestRemoveAll(t *testing.T) {
 				assert.Nil(t, info)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrInvalidWorkingDirectory)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalidWorkingDirectory))
 
 				p, _ := fspath.NewFileSystemPath("/", nil)
 				files, _ := fs.FindFiles("target", p)
@@ -738,3 +738,91 @@ func TestRemoveAll(t *testing.T) {
 		testCase.Assertions(t, fs, info, err)
 	}
 }
+
+func TestRmdir(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Path       string
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem, info file.FileInfo, err error)
+	}{
+		{
+			CaseName: "Remove empty directory",
+			Path:     "/dir1/empty",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, info file.FileInfo, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "/dir1/empty", info.AbsolutePath())
+
+				p, _ := fspath.NewFileSystemPath("/dir1", nil)
+				files, _ := fs.ListFiles(p)
+				assert.Equal(t, []string{"file1", "link"}, fileNames(files))
+			},
+		},
+		{
+			CaseName: "Remove directory not empty",
+			Path:     "/dir1",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, info file.FileInfo, err error) {
+				assert.Nil(t, info)
+				assert.True(t, errors.Is(err, fserrors.ErrNotEmpty))
+
+				p, _ := fspath.NewFileSystemPath("/dir1/empty", nil)
+				_, err = fs.Stat(p)
+				assert.Nil(t, err)
+			},
+		},
+		{
+			CaseName: "Remove regular file",
+			Path:     "/dir1/file1",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, info file.FileInfo, err error) {
+				assert.Nil(t, info)
+				assert.True(t, errors.Is(err, fserrors.ErrNotDir))
+			},
+		},
+		{
+			CaseName: "Remove symbolic link to a directory",
+			Path:     "/dir1/link",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, info file.FileInfo, err error) {
+				assert.Nil(t, info)
+				assert.True(t, errors.Is(err, fserrors.ErrNotDir))
+			},
+		},
+		{
+			CaseName: "Remove missing directory",
+			Path:     "/dir1/missing",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, info file.FileInfo, err error) {
+				assert.Nil(t, info)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+			},
+		},
+		{
+			CaseName: "Remove root",
+			Path:     "/",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, info file.FileInfo, err error) {
+				assert.Nil(t, info)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalid))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.CaseName, func(t *testing.T) {
+			fs := newFileSystem()
+			p, _ := fspath.NewFileSystemPath("/dir1/empty", nil)
+			if _, err := fs.MkdirAll(p); err != nil {
+				t.Fatal(err)
+			}
+			p, _ = fspath.NewFileSystemPath("/dir1/file1", nil)
+			if _, err := fs.CreateRegularFile(p); err != nil {
+				t.Fatal(err)
+			}
+			target, _ := fspath.NewFileSystemPath("/dir1/empty", nil)
+			p, _ = fspath.NewFileSystemPath("/dir1/link", nil)
+			if _, err := fs.CreateSymbolicLink(target, p); err != nil {
+				t.Fatal(err)
+			}
+
+			p, _ = fspath.NewFileSystemPath(c.Path, nil)
+			info, err := fs.Rmdir(p)
+			c.Assertions(t, fs, info, err)
+		})
+	}
+}
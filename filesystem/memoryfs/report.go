@@ -0,0 +1,41 @@
+package memoryfs
+
+import (
+	"material/filesystem/filesystem/fsreport"
+)
+
+// reporter collects the outcome of the files processed by a recursive operation
+type reporter struct {
+	report          *fsreport.Report
+	continueOnError bool
+	// err is the first failure
+	err error
+}
+
+func newReporter(continueOnError bool) *reporter {
+	return &reporter{report: &fsreport.Report{}, continueOnError: continueOnError}
+}
+
+// succeeded records the file at path as processed
+func (r *reporter) succeeded(path string) {
+	r.report.Succeeded = append(r.report.Succeeded, path)
+}
+
+// fail records the failure of the file at path, unless the operation has already
+// stopped because of a failure deeper in the tree.
+// Returns nil if the operation continues with the next file, err otherwise.
+func (r *reporter) fail(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if r.continueOnError || r.err == nil {
+		r.report.Failed = append(r.report.Failed, &fsreport.Failure{Path: path, Err: err})
+	}
+	if r.err == nil {
+		r.err = err
+	}
+	if r.continueOnError {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,173 @@
+package memoryfs_test
+
+import (
+	"errors"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fsname"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsreport"
+	"material/filesystem/filesystem/fsvirtual"
+	"material/filesystem/filesystem/memoryfs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// initializeReportTree creates a file system where names are at most 8 bytes
+// and paths at most 3 names deep, with the tree:
+// /src/abcdefgh, /src/sub/y, /src/x, /dst/src/abcdefgh,
+// /dir/a, /dir/mnt (virtual), /dir/sub/b
+func initializeReportTree() (*memoryfs.MemoryFileSystem, error) {
+	fs := newFileSystem(memoryfs.WithNamePolicy(fsname.Policy{MaxNameLength: 8, MaxDepth: 3}))
+	for _, path := range []string{"/src/abcdefgh", "/src/sub/y", "/src/x", "/dst/src/abcdefgh", "/dir/a", "/dir/sub/b"} {
+		p, _ := fspath.NewFileSystemPath(path, nil)
+		if err := fs.AppendAll(p, []byte(path)); err != nil {
+			return nil, err
+		}
+	}
+	p, _ := fspath.NewFileSystemPath("/dir/mnt", nil)
+	if err := fs.Mount(p, fsvirtual.Null); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+func failedPaths(report *fsreport.Report) []string {
+	paths := []string{}
+	for _, failure := range report.Failed {
+		paths = append(paths, failure.Path)
+	}
+	return paths
+}
+
+func assertExists(t *testing.T, fs *memoryfs.MemoryFileSystem, path string, exists bool) {
+	p, _ := fspath.NewFileSystemPath(path, nil)
+	_, err := fs.Stat(p)
+	assert.Equal(t, exists, err == nil, path)
+}
+
+func TestReport(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem)
+	}{
+		{
+			CaseName: "Copy continues after an error",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				src, _ := fspath.NewFileSystemPath("/src", nil)
+				dest, _ := fspath.NewFileSystemPath("/d/e", nil)
+				info, report, err := fs.CopyWithReport(src, dest, true)
+				assert.True(t, errors.Is(err, fserrors.ErrNameTooLong))
+				assert.Equal(t, "e", info.Name())
+				assert.Equal(t, []string{"/src/abcdefgh", "/src/sub", "/src/x", "/src"}, report.Succeeded)
+				assert.Equal(t, []string{"/src/sub/y"}, failedPaths(report))
+				assert.True(t, errors.Is(report.Failed[0].Err, fserrors.ErrNameTooLong))
+
+				assertExists(t, fs, "/d/e/x", true)
+				assertExists(t, fs, "/d/e/sub", true)
+				assertExists(t, fs, "/d/e/sub/y", false)
+			},
+		},
+		{
+			CaseName: "Copy stops at the first error",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				src, _ := fspath.NewFileSystemPath("/src", nil)
+				dest, _ := fspath.NewFileSystemPath("/d/e", nil)
+				info, report, err := fs.CopyWithReport(src, dest, false)
+				assert.True(t, errors.Is(err, fserrors.ErrNameTooLong))
+				assert.Nil(t, info)
+				assert.Equal(t, []string{"/src/abcdefgh"}, report.Succeeded)
+				assert.Equal(t, []string{"/src/sub/y"}, failedPaths(report))
+
+				_, err = fs.Copy(src, dest)
+				assert.True(t, errors.Is(err, fserrors.ErrNameTooLong))
+				assertExists(t, fs, "/d/e", false)
+			},
+		},
+		{
+			CaseName: "Move keeps the files that failed in the source directory",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				src, _ := fspath.NewFileSystemPath("/src", nil)
+				dest, _ := fspath.NewFileSystemPath("/dst", nil)
+				info, report, err := fs.MoveWithReport(src, dest, true)
+				assert.True(t, errors.Is(err, fserrors.ErrNameTooLong))
+				assert.Equal(t, "src", info.Name())
+				assert.Equal(t, []string{"/src/sub", "/src/x"}, report.Succeeded)
+				assert.Equal(t, []string{"/src/abcdefgh"}, failedPaths(report))
+
+				assertExists(t, fs, "/src/abcdefgh", true)
+				assertExists(t, fs, "/src/x", false)
+				assertExists(t, fs, "/dst/src/x", true)
+				assertExists(t, fs, "/dst/src/sub/y", true)
+			},
+		},
+		{
+			CaseName: "Move stops at the first error",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				src, _ := fspath.NewFileSystemPath("/src", nil)
+				dest, _ := fspath.NewFileSystemPath("/dst", nil)
+				info, report, err := fs.MoveWithReport(src, dest, false)
+				assert.True(t, errors.Is(err, fserrors.ErrNameTooLong))
+				assert.Nil(t, info)
+				assert.Empty(t, report.Succeeded)
+				assert.Equal(t, []string{"/src/abcdefgh"}, failedPaths(report))
+
+				assertExists(t, fs, "/src/x", true)
+				assertExists(t, fs, "/dst/src/x", false)
+			},
+		},
+		{
+			CaseName: "RemoveAll keeps the files that failed and their parents",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/dir", nil)
+				report, err := fs.RemoveAllWithReport(p, true)
+				assert.True(t, errors.Is(err, fserrors.ErrOperationNotSupported))
+				assert.Equal(t, []string{"/dir/a", "/dir/sub/b", "/dir/sub"}, report.Succeeded)
+				assert.Equal(t, []string{"/dir/mnt", "/dir"}, failedPaths(report))
+				assert.True(t, errors.Is(report.Failed[1].Err, fserrors.ErrNotEmpty))
+
+				assertExists(t, fs, "/dir/mnt", true)
+				assertExists(t, fs, "/dir/sub", false)
+			},
+		},
+		{
+			CaseName: "RemoveAll stops at the first error",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/dir", nil)
+				report, err := fs.RemoveAllWithReport(p, false)
+				assert.Equal(t, "remove /dir: operation not supported", err.Error())
+				assert.Equal(t, []string{"/dir/a"}, report.Succeeded)
+				assert.Equal(t, []string{"/dir/mnt"}, failedPaths(report))
+
+				_, err = fs.RemoveAll(p)
+				assert.True(t, errors.Is(err, fserrors.ErrOperationNotSupported))
+				assertExists(t, fs, "/dir/sub/b", true)
+			},
+		},
+		{
+			CaseName: "Transactions report the files",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				tx := fs.Begin()
+				p, _ := fspath.NewFileSystemPath("/dir", nil)
+				report, err := tx.RemoveAllWithReport(p, true)
+				assert.True(t, errors.Is(err, fserrors.ErrOperationNotSupported))
+				assert.Equal(t, []string{"/dir/mnt", "/dir"}, failedPaths(report))
+				assertExists(t, fs, "/dir/a", true)
+
+				assert.Nil(t, tx.Commit())
+				assertExists(t, fs, "/dir/a", false)
+				assertExists(t, fs, "/dir/mnt", true)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.CaseName, func(t *testing.T) {
+			fs, err := initializeReportTree()
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.Assertions(t, fs)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package memoryfs
+
+import (
+	"material/filesystem/filesystem/file"
+	"sync"
+	"sync/atomic"
+)
+
+// snapshotState is shared by the files of a transaction snapshot.
+// The snapshot is copied on access: a directory copies the entries of the
+// file system directory the first time it's used, so taking a snapshot
+// takes constant time and only the directories used by the transaction are copied.
+type snapshotState struct {
+	// file system the snapshot is taken from
+	origin *MemoryFileSystem
+	// copies of the inodes by original, to preserve the hard links
+	copies map[*inMemoryFileData]*inMemoryFileData
+	// inodes of the snapshot, released when it's discarded
+	inodes []*inMemoryFileData
+	sync.Mutex
+}
+
+// lazyDir is a snapshot directory whose entries are copied on first access
+type lazyDir struct {
+	state *snapshotState
+	// directory of the file system the entries are copied from
+	origin *inMemoryFile
+	// true once the entries are copied
+	copied atomic.Bool
+}
+
+// snapshot returns a copy of the file system sharing the content blocks.
+// Hard links are preserved. Open files, trash and versions are not copied.
+// The directories are copied on first access, so the snapshot sees the changes
+// made to the directories it hasn't accessed yet.
+// The caller must hold the lock.
+func (fs *MemoryFileSystem) snapshot() *MemoryFileSystem {
+	state := &snapshotState{
+		origin: fs,
+		copies: map[*inMemoryFileData]*inMemoryFileData{},
+	}
+	snap := &MemoryFileSystem{
+		openFiles:       newFileTable(),
+		blocks:          fs.blocks,
+		keys:            fs.keys,
+		trash:           map[string][]*trashEntry{},
+		trashRetention:  fs.trashRetention,
+		caseInsensitive: fs.caseInsensitive,
+		namePolicy:      fs.namePolicy,
+		clock:           fs.clock,
+		maxLinkDepth:    fs.maxLinkDepth,
+		pathCache:       newPathCache(fs.pathCache.capacity),
+		lastIno:         fs.lastIno,
+		snapshotState:   state,
+	}
+	snap.root = state.copyEntry(fs.root, nil)
+	snap.root.fileMap[".."] = snap.root
+	snap.root.fileMap["/"] = snap.root
+	return snap
+}
+
+// copyEntry returns a copy of the entry in the snapshot directory parent.
+// The entries of a directory are copied on first access.
+// The caller must hold the snapshot lock.
+func (s *snapshotState) copyEntry(f *inMemoryFile, parent *inMemoryFile) *inMemoryFile {
+	data, found := s.copies[f.data]
+	if !found {
+		data = f.data.snapshot()
+		s.copies[f.data] = data
+		s.inodes = append(s.inodes, data)
+	}
+
+	info := &inMemoryFileInfo{inode: data}
+	loc := *f.info.location.Load()
+	if parent != nil {
+		loc.parent = parent.info
+	}
+	info.location.Store(&loc)
+	snap := &inMemoryFile{
+		info:      info,
+		data:      data,
+		fileMap:   map[string]*inMemoryFile{},
+		link:      f.link,
+		isVirtual: f.isVirtual,
+	}
+	snap.fileMap["."] = snap
+	if parent != nil {
+		snap.fileMap[".."] = parent
+	}
+	if data.fileType == file.Directory {
+		snap.lazy = &lazyDir{state: s, origin: f}
+	}
+	return snap
+}
+
+// addInode registers an inode created in the snapshot.
+// It's a noop for the file systems that aren't snapshots.
+func (s *snapshotState) addInode(d *inMemoryFileData) {
+	if s == nil {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.inodes = append(s.inodes, d)
+}
+
+// materialize copies the entries of the file system directory
+// the first time a snapshot directory is accessed.
+// It's a noop for the other directories.
+// This implementation is thread safe.
+func (dir *inMemoryFile) materialize() {
+	lazy := dir.lazy
+	if lazy == nil || lazy.copied.Load() {
+		return
+	}
+
+	s := lazy.state
+	s.Lock()
+	defer s.Unlock()
+	if lazy.copied.Load() {
+		return
+	}
+
+	s.origin.RLock()
+	defer s.origin.RUnlock()
+	s.origin.visitDir(lazy.origin, func(name string, child *inMemoryFile) error {
+		dir.insertChild(s.origin.nameKey(name), s.copyEntry(child, dir))
+		return nil
+	})
+	lazy.copied.Store(true)
+}
+
+// release releases the content blocks of every inode of the snapshot,
+// including the removed and trashed ones.
+// It must be called only on snapshots.
+func (fs *MemoryFileSystem) release() {
+	s := fs.snapshotState
+	s.Lock()
+	defer s.Unlock()
+	for _, d := range s.inodes {
+		d.Lock()
+		d.content.release(fs.blocks)
+		d.releaseVersions()
+		d.Unlock()
+	}
+	s.inodes = nil
+}
+
+// snapshot returns a copy of the data sharing the content blocks.
+// This implementation is thread safe.
+func (d *inMemoryFileData) snapshot() *inMemoryFileData {
+	d.RLock()
+	defer d.RUnlock()
+	return &inMemoryFileData{
+		ino:           d.ino,
+		fileType:      d.fileType,
+		store:         d.store,
+		content:       d.content.clone(d.store),
+		encoding:      d.encoding,
+		encrypted:     d.encrypted,
+		links:         d.links,
+		modTime:       d.modTime,
+		changeTime:    d.changeTime,
+		nextVersionID: 1,
+		pipe:          snapshotPipe(d.pipe),
+		virtual:       d.virtual,
+		clock:         d.clock,
+	}
+}
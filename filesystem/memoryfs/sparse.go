@@ -0,0 +1,61 @@
+package memoryfs
+
+import (
+	"material/filesystem/filesystem/fserrors"
+)
+
+// SeekData moves the descriptor offset to the first position >= offset
+// containing data and returns it.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the file is not open
+// - the offset is negative
+// - there is no data after offset
+func (fs *MemoryFileSystem) SeekData(descriptor string, offset int) (int, error) {
+	if offset < 0 {
+		return 0, nameError("seek", descriptor, fserrors.ErrInvalid)
+	}
+
+	newOffset, err := fs.doWrite(descriptor, nil, func(fd *fileDescriptor) (int, error) {
+		return fd.SeekData(offset)
+	})
+	return newOffset, nameError("seek", descriptor, err)
+}
+
+// SeekHole moves the descriptor offset to the first position >= offset
+// in a hole and returns it. The end of the file is considered a hole.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the file is not open
+// - the offset is negative
+// - the offset is past the end of the file
+func (fs *MemoryFileSystem) SeekHole(descriptor string, offset int) (int, error) {
+	if offset < 0 {
+		return 0, nameError("seek", descriptor, fserrors.ErrInvalid)
+	}
+
+	newOffset, err := fs.doWrite(descriptor, nil, func(fd *fileDescriptor) (int, error) {
+		return fd.SeekHole(offset)
+	})
+	return newOffset, nameError("seek", descriptor, err)
+}
+
+// PunchHole deallocates length bytes starting at offset.
+// The range reads as zeros and the file size doesn't change.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the file is not open
+// - the offset or the length is negative
+func (fs *MemoryFileSystem) PunchHole(descriptor string, offset int, length int) error {
+	if offset < 0 || length < 0 {
+		return nameError("punchhole", descriptor, fserrors.ErrInvalid)
+	}
+
+	_, err := fs.doWrite(descriptor, nil, func(fd *fileDescriptor) (int, error) {
+		return 0, fd.PunchHole(offset, length)
+	})
+	return nameError("punchhole", descriptor, err)
+}
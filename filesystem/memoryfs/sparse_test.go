@@ -0,0 +1,170 @@
+package memoryfs_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/memoryfs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func initializeSparseFile(content []byte) (*memoryfs.MemoryFileSystem, string, error) {
+	fs := newFileSystem()
+	if err := createFileWithContent(fs, "/sparse", content); err != nil {
+		return nil, "", err
+	}
+	p, _ := fspath.NewFileSystemPath("/sparse", nil)
+	fd, err := fs.Open(p)
+	return fs, fd, err
+}
+
+func TestSparseFiles(t *testing.T) {
+	content := randomContent(1, 200<<10)
+	cases := []struct {
+		CaseName   string
+		Content    []byte
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem, fd string)
+	}{
+		{
+			CaseName: "Write at large offset does not allocate the gap",
+			Content:  []byte{},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, fd string) {
+				n, err := fs.WriteAt(fd, []byte("Hello world!"), 1<<30)
+				assert.Nil(t, err)
+				assert.Equal(t, 12, n)
+
+				p, _ := fspath.NewFileSystemPath("/sparse", nil)
+				stat, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.Equal(t, 1<<30-1+12, stat.Size())
+				assert.Equal(t, 12, stat.PhysicalSize())
+
+				buff := make([]byte, 20)
+				n, err = fs.ReadAt(fd, buff, 1<<30-9)
+				assert.Nil(t, err)
+				assert.Equal(t, 20, n)
+				assert.Equal(t, append(make([]byte, 8), []byte("Hello world!")...), buff)
+			},
+		},
+		{
+			CaseName: "Seek data and holes",
+			Content:  []byte("Hello"),
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, fd string) {
+				_, err := fs.WriteAt(fd, []byte("world"), 106)
+				assert.Nil(t, err)
+
+				pos, err := fs.SeekHole(fd, 0)
+				assert.Nil(t, err)
+				assert.Equal(t, 5, pos)
+				pos, err = fs.SeekData(fd, 5)
+				assert.Nil(t, err)
+				assert.Equal(t, 105, pos)
+				pos, err = fs.SeekData(fd, 107)
+				assert.Nil(t, err)
+				assert.Equal(t, 107, pos)
+				pos, err = fs.SeekHole(fd, 107)
+				assert.Nil(t, err)
+				assert.Equal(t, 110, pos)
+
+				// the offset is moved
+				buff := make([]byte, 5)
+				_, err = fs.SeekData(fd, 50)
+				assert.Nil(t, err)
+				_, err = fs.Read(fd, buff)
+				assert.Nil(t, err)
+				assert.Equal(t, []byte("world"), buff)
+
+				_, err = fs.SeekData(fd, 110)
+				assert.True(t, errors.Is(err, fserrors.ErrOffsetOutOfRange))
+				_, err = fs.SeekHole(fd, 110)
+				assert.True(t, errors.Is(err, fserrors.ErrOffsetOutOfRange))
+				_, err = fs.SeekHole(fd, -1)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalid))
+			},
+		},
+		{
+			CaseName: "Punch hole",
+			Content:  content,
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, fd string) {
+				assert.Nil(t, fs.PunchHole(fd, 50000, 100000))
+				expected := append([]byte{}, content...)
+				copy(expected[50000:150000], make([]byte, 100000))
+
+				p, _ := fspath.NewFileSystemPath("/sparse", nil)
+				data, err := fs.ReadAll(p)
+				assert.Nil(t, err)
+				assert.Equal(t, expected, data)
+
+				stat, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.Equal(t, len(content), stat.Size())
+				assert.Equal(t, len(content)-100000, stat.PhysicalSize())
+
+				digest, err := fs.Checksum(p, file.SHA256)
+				assert.Nil(t, err)
+				expectedDigest := sha256.Sum256(expected)
+				assert.Equal(t, expectedDigest[:], digest)
+
+				pos, err := fs.SeekHole(fd, 0)
+				assert.Nil(t, err)
+				assert.Equal(t, 50000, pos)
+				pos, err = fs.SeekData(fd, pos)
+				assert.Nil(t, err)
+				assert.Equal(t, 150000, pos)
+			},
+		},
+		{
+			CaseName: "Punch adjacent holes and write in a hole",
+			Content:  content,
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, fd string) {
+				assert.Nil(t, fs.PunchHole(fd, 0, 1000))
+				assert.Nil(t, fs.PunchHole(fd, 1000, 1000))
+				assert.Nil(t, fs.PunchHole(fd, 500, 1000))
+				pos, err := fs.SeekData(fd, 0)
+				assert.Nil(t, err)
+				assert.Equal(t, 2000, pos)
+
+				_, err = fs.WriteAt(fd, []byte("Hello world!"), 1000)
+				assert.Nil(t, err)
+				expected := append(make([]byte, 1000), []byte("Hello world!")...)
+				expected = append(expected, make([]byte, 1000)...)
+				expected = append(expected, content[2000:]...)
+
+				p, _ := fspath.NewFileSystemPath("/sparse", nil)
+				data, err := fs.ReadAll(p)
+				assert.Nil(t, err)
+				assert.Equal(t, expected, data)
+			},
+		},
+		{
+			CaseName: "Punch hole past the end of the file",
+			Content:  []byte("Hello world!"),
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, fd string) {
+				assert.Nil(t, fs.PunchHole(fd, 6, 100))
+				assert.Nil(t, fs.PunchHole(fd, 100, 100))
+				p, _ := fspath.NewFileSystemPath("/sparse", nil)
+				data, err := fs.ReadAll(p)
+				assert.Nil(t, err)
+				assert.Equal(t, append([]byte("Hello "), make([]byte, 6)...), data)
+
+				err = fs.PunchHole(fd, -1, 100)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalid))
+				err = fs.PunchHole("invalid", 0, 100)
+				assert.True(t, errors.Is(err, fserrors.ErrNotOpen))
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		fs, fd, err := initializeSparseFile(testCase.Content)
+		if err != nil {
+			t.Fatal("error initializing file system")
+		}
+		testCase.Assertions(t, fs, fd)
+		fs.Close(fd)
+	}
+}
@@ -0,0 +1,24 @@
+package memoryfs
+
+import (
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fspath"
+)
+
+// Stat returns the attributes of the file located at the specified path.
+// If the file is a symbolic link, the link is resolved.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the file does not exist
+func (fs *MemoryFileSystem) Stat(path *fspath.FileSystemPath) (file.FileStat, error) {
+	fs.RLock()
+	defer fs.RUnlock()
+
+	fileToStat, err := fs.traverseToBase(path)
+	if err != nil {
+		return nil, pathError("stat", path, err)
+	}
+
+	return newInMemoryFileStat(fileToStat), nil
+}
@@ -0,0 +1,182 @@
+package memoryfs_test
+
+import (
+	"errors"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/memoryfs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStat(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Path       string
+		Initialize func() (*memoryfs.MemoryFileSystem, file.File, error)
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem, stat file.FileStat, err error)
+	}{
+		{
+			CaseName: "Stat regular file using absolute path",
+			Path:     "/dir1/file",
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs := newFileSystem()
+				p, _ := fspath.NewFileSystemPath("/dir1/file", nil)
+				if err := fs.AppendAll(p, []byte("content")); err != nil {
+					return nil, nil, err
+				}
+				return fs, nil, nil
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, stat file.FileStat, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "/dir1/file", stat.AbsolutePath())
+				assert.Equal(t, "file", stat.Name())
+				assert.Equal(t, file.RegularFile, stat.FileType())
+				assert.Equal(t, 7, stat.Size())
+				assert.Equal(t, 1, stat.Links())
+				assert.False(t, stat.ModTime().IsZero())
+				assert.False(t, stat.ChangeTime().Before(stat.ModTime()))
+			},
+		},
+		{
+			CaseName: "Stat resolves symbolic links using relative path",
+			Path:     "link",
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs := newFileSystem()
+				p1, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
+				dir, err := fs.MkdirAll(p1)
+				if err != nil {
+					return nil, nil, err
+				}
+				p2, _ := fspath.NewFileSystemPath("/dir1/dir2/link", nil)
+				if _, err := fs.CreateSymbolicLink(p1, p2); err != nil {
+					return nil, nil, err
+				}
+				return fs, dir, nil
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, stat file.FileStat, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "/dir1/dir2", stat.AbsolutePath())
+				assert.Equal(t, file.Directory, stat.FileType())
+			},
+		},
+		{
+			CaseName: "Hard links count",
+			Path:     "/file",
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs := newFileSystem()
+				p1, _ := fspath.NewFileSystemPath("/file", nil)
+				if _, err := fs.CreateRegularFile(p1); err != nil {
+					return nil, nil, err
+				}
+				p2, _ := fspath.NewFileSystemPath("/link1", nil)
+				if _, err := fs.CreateHardLink(p1, p2); err != nil {
+					return nil, nil, err
+				}
+				p3, _ := fspath.NewFileSystemPath("/link2", nil)
+				if _, err := fs.CreateHardLink(p1, p3); err != nil {
+					return nil, nil, err
+				}
+				if _, err := fs.Remove(p3); err != nil {
+					return nil, nil, err
+				}
+				return fs, nil, nil
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, stat file.FileStat, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, 2, stat.Links())
+			},
+		},
+		{
+			CaseName: "Hard links share the inode and its metadata",
+			Path:     "/link",
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs := newFileSystem()
+				p1, _ := fspath.NewFileSystemPath("/dir/file", nil)
+				if err := fs.AppendAll(p1, []byte("content")); err != nil {
+					return nil, nil, err
+				}
+				p2, _ := fspath.NewFileSystemPath("/link", nil)
+				if _, err := fs.CreateHardLink(p1, p2); err != nil {
+					return nil, nil, err
+				}
+				if err := fs.AppendAll(p2, []byte(" appended")); err != nil {
+					return nil, nil, err
+				}
+				return fs, nil, nil
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, stat file.FileStat, err error) {
+				assert.Nil(t, err)
+				p, _ := fspath.NewFileSystemPath("/dir/file", nil)
+				fileStat, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.Equal(t, "/link", stat.AbsolutePath())
+				assert.Equal(t, "/dir/file", fileStat.AbsolutePath())
+				assert.NotZero(t, stat.Inode())
+				assert.Equal(t, fileStat.Inode(), stat.Inode())
+				assert.Equal(t, fileStat.FileType(), stat.FileType())
+				assert.Equal(t, 16, stat.Size())
+				assert.Equal(t, fileStat.Size(), stat.Size())
+				assert.Equal(t, 2, stat.Links())
+				assert.Equal(t, fileStat.Links(), stat.Links())
+				assert.Equal(t, fileStat.ModTime(), stat.ModTime())
+				assert.Equal(t, fileStat.ChangeTime(), stat.ChangeTime())
+
+				_, err = fs.Remove(p)
+				assert.Nil(t, err)
+				p, _ = fspath.NewFileSystemPath("/link", nil)
+				newStat, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.Equal(t, stat.Inode(), newStat.Inode())
+				assert.Equal(t, 1, newStat.Links())
+				assert.Equal(t, 16, newStat.Size())
+			},
+		},
+		{
+			CaseName: "Writing a file updates the modification time",
+			Path:     "/file",
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs := newFileSystem()
+				p, _ := fspath.NewFileSystemPath("/file", nil)
+				if _, err := fs.CreateRegularFile(p); err != nil {
+					return nil, nil, err
+				}
+				return fs, nil, nil
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, stat file.FileStat, err error) {
+				assert.Nil(t, err)
+				p, _ := fspath.NewFileSystemPath("/file", nil)
+				assert.Nil(t, fs.AppendAll(p, []byte("content")))
+				newStat, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.False(t, newStat.ModTime().Before(stat.ModTime()))
+				assert.Equal(t, 7, newStat.Size())
+				// stat is a snapshot
+				assert.Equal(t, 0, stat.Size())
+			},
+		},
+		{
+			CaseName: "File does not exist",
+			Path:     "/invalid",
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				return newFileSystem(), nil, nil
+			},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, stat file.FileStat, err error) {
+				assert.NotNil(t, err)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+				assert.Nil(t, stat)
+			},
+		},
+	}
+	for _, testCase := range cases {
+		fs, workingDir, err := testCase.Initialize()
+		if err != nil {
+			t.Fatal("error initializing file system")
+		}
+		p, _ := fspath.NewFileSystemPath(testCase.Path, workingDir)
+		stat, err := fs.Stat(p)
+		testCase.Assertions(t, fs, stat, err)
+	}
+}
@@ -0,0 +1,406 @@
+package memoryfs
+
+import (
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsreport"
+	"material/filesystem/filesystem/fstrash"
+	"material/filesystem/filesystem/fstx"
+	"sync"
+)
+
+// txOp is an operation recorded in a transaction
+type txOp struct {
+	// run applies the operation, it must be called while holding the lock of fs
+	run func(fs *MemoryFileSystem) error
+	// wrap records the operation and the paths in the errors of run
+	wrap func(err error) error
+}
+
+// Transaction implements the fstx.Transaction interface.
+// Operations are applied to a private snapshot of the file system,
+// sharing the content blocks, and recorded.
+// Commit applies them once to the file system.
+type Transaction struct {
+	// file system the transaction is committed to
+	fs *MemoryFileSystem
+	// private snapshot with the changes applied, nil once the transaction is closed
+	shadow *MemoryFileSystem
+	// operations applied to shadow
+	ops []txOp
+	sync.RWMutex
+}
+
+// Begin starts a new transaction.
+// The snapshot copies a directory the first time the transaction uses it,
+// so the transaction sees the changes committed by other sessions
+// to the directories it hasn't used yet.
+// This implementation is thread safe.
+func (fs *MemoryFileSystem) Begin() fstx.Transaction {
+	fs.RLock()
+	defer fs.RUnlock()
+	return &Transaction{
+		fs:     fs,
+		shadow: fs.snapshot(),
+	}
+}
+
+// Commit applies the operations to the file system while holding its lock,
+// so other sessions see either none or all of the changes.
+// Every operation runs once: the state of the files it changes is recorded
+// and, if any of them fails, e.g. because another session created a file the transaction
+// creates or removed a directory the transaction writes to, the changes are undone.
+// Names generated to fix conflicts in Move and Copy can differ from the ones
+// seen in the transaction.
+// The transaction is closed in any case.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the transaction is closed
+// - an operation can't be applied to the current file system
+func (tx *Transaction) Commit() error {
+	tx.Lock()
+	defer tx.Unlock()
+	if tx.shadow == nil {
+		return fserrors.ErrTransactionClosed
+	}
+	// the snapshot blocks are released first, so that they don't use the capacity
+	ops := tx.ops
+	tx.close()
+
+	fs := tx.fs
+	fs.Lock()
+	defer fs.Unlock()
+
+	fs.beginJournal()
+	for _, op := range ops {
+		if err := op.run(fs); err != nil {
+			fs.undoJournal()
+			return conflictError(op.wrap(err))
+		}
+	}
+	fs.endJournal()
+	return nil
+}
+
+// Rollback discards the changes and closes the transaction.
+// If the transaction is closed, this is a noop.
+// This implementation is thread safe.
+func (tx *Transaction) Rollback() {
+	tx.Lock()
+	defer tx.Unlock()
+	if tx.shadow != nil {
+		tx.close()
+	}
+}
+
+func (tx *Transaction) close() {
+	tx.shadow.release()
+	tx.shadow = nil
+	tx.ops = nil
+}
+
+// Mkdir creates a new directory in the transaction
+func (tx *Transaction) Mkdir(path *fspath.FileSystemPath) (file.File, error) {
+	return tx.create("mkdir", path, file.Directory, false)
+}
+
+// MkdirAll creates a directory along with any necessary parents in the transaction
+func (tx *Transaction) MkdirAll(path *fspath.FileSystemPath) (file.File, error) {
+	return tx.create("mkdir", path, file.Directory, true)
+}
+
+// CreateRegularFile creates a new file in the transaction
+func (tx *Transaction) CreateRegularFile(path *fspath.FileSystemPath) (file.File, error) {
+	return tx.create("create", path, file.RegularFile, false)
+}
+
+func (tx *Transaction) create(op string, path *fspath.FileSystemPath, fileType file.FileType, isRecursive bool) (file.File, error) {
+	abs, err := absolutePath(path)
+	if err != nil {
+		return nil, pathError(op, path, err)
+	}
+
+	var newFile *inMemoryFile
+	err = tx.apply(func(fs *MemoryFileSystem) error {
+		var err error
+		newFile, err = fs.createAt(abs, fileType, isRecursive)
+		return err
+	}, pathErrorFn(op, path))
+	if err != nil {
+		return nil, err
+	}
+	return newFile, nil
+}
+
+// Remove removes a file in the transaction
+func (tx *Transaction) Remove(path *fspath.FileSystemPath) (file.FileInfo, error) {
+	return tx.remove(path, false)
+}
+
+// RemoveAll removes a file or directory in the transaction
+func (tx *Transaction) RemoveAll(path *fspath.FileSystemPath) (file.FileInfo, error) {
+	return tx.remove(path, true)
+}
+
+// RemoveAllWithReport removes a file or directory in the transaction
+// and reports the removed files and the failures.
+// Commit replays the removal with the same continueOnError.
+func (tx *Transaction) RemoveAllWithReport(path *fspath.FileSystemPath, continueOnError bool) (*fsreport.Report, error) {
+	report := &fsreport.Report{}
+	abs, err := absolutePath(path)
+	if err != nil {
+		return report, pathError("remove", path, err)
+	}
+
+	var failure error
+	err = tx.apply(func(fs *MemoryFileSystem) error {
+		r := newReporter(continueOnError)
+		_, err := fs.removeFileLockFree(abs, true, r)
+		report, failure = r.report, r.err
+		return err
+	}, pathErrorFn("remove", path))
+	if err == nil {
+		err = pathError("remove", path, failure)
+	}
+	return report, err
+}
+
+func (tx *Transaction) remove(path *fspath.FileSystemPath, isRecursive bool) (file.FileInfo, error) {
+	abs, err := absolutePath(path)
+	if err != nil {
+		return nil, pathError("remove", path, err)
+	}
+
+	var info file.FileInfo
+	err = tx.apply(func(fs *MemoryFileSystem) error {
+		var err error
+		info, err = fs.removeFileLockFree(abs, isRecursive, newReporter(false))
+		return err
+	}, pathErrorFn("remove", path))
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Rmdir removes an empty directory in the transaction
+func (tx *Transaction) Rmdir(path *fspath.FileSystemPath) (file.FileInfo, error) {
+	abs, err := absolutePath(path)
+	if err != nil {
+		return nil, pathError("rmdir", path, err)
+	}
+
+	var info file.FileInfo
+	err = tx.apply(func(fs *MemoryFileSystem) error {
+		var err error
+		info, err = fs.rmdirLockFree(abs)
+		return err
+	}, pathErrorFn("rmdir", path))
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Trash moves a file to the user trash in the transaction.
+// The entry id changes on commit.
+func (tx *Transaction) Trash(path *fspath.FileSystemPath, user string, isRecursive bool) (*fstrash.Entry, error) {
+	abs, err := absolutePath(path)
+	if err != nil {
+		return nil, pathError("trash", path, err)
+	}
+
+	var entry *fstrash.Entry
+	err = tx.apply(func(fs *MemoryFileSystem) error {
+		var err error
+		entry, err = fs.trashLockFree(abs, user, isRecursive)
+		return err
+	}, pathErrorFn("trash", path))
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Move moves a file in the transaction
+func (tx *Transaction) Move(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath) (file.FileInfo, error) {
+	return tx.moveOrCopy(srcPath, destPath, false)
+}
+
+// Copy copies a file in the transaction
+func (tx *Transaction) Copy(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath) (file.FileInfo, error) {
+	return tx.moveOrCopy(srcPath, destPath, true)
+}
+
+// MoveWithReport moves a file in the transaction
+// and reports the moved files and the failures.
+// Commit replays the move with the same continueOnError.
+func (tx *Transaction) MoveWithReport(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath, continueOnError bool) (file.FileInfo, *fsreport.Report, error) {
+	return tx.moveOrCopyWithReport(srcPath, destPath, false, continueOnError, false)
+}
+
+// CopyWithReport copies a file in the transaction
+// and reports the copied files and the failures.
+// Commit replays the copy with the same continueOnError.
+func (tx *Transaction) CopyWithReport(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath, continueOnError bool) (file.FileInfo, *fsreport.Report, error) {
+	return tx.moveOrCopyWithReport(srcPath, destPath, true, continueOnError, false)
+}
+
+// CopyPreservingLinks copies a file in the transaction preserving the hard links
+// and reports the copied files and the failures.
+// Commit replays the copy with the same continueOnError.
+func (tx *Transaction) CopyPreservingLinks(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath, continueOnError bool) (file.FileInfo, *fsreport.Report, error) {
+	return tx.moveOrCopyWithReport(srcPath, destPath, true, continueOnError, true)
+}
+
+func (tx *Transaction) moveOrCopyWithReport(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath, isCopy bool, continueOnError bool, preserveLinks bool) (file.FileInfo, *fsreport.Report, error) {
+	report := &fsreport.Report{}
+	var failure error
+	info, err := tx.applyWithInfo(moveOrCopyOp(isCopy), srcPath, destPath, func(fs *MemoryFileSystem, src *fspath.FileSystemPath, dest *fspath.FileSystemPath) (file.FileInfo, error) {
+		r := newReporter(continueOnError)
+		info, err := fs.moveOrCopyPathLockFree(src, dest, isCopy, preserveLinks, r)
+		report, failure = r.report, r.err
+		return info, err
+	})
+	if err == nil && failure != nil {
+		err = linkError(moveOrCopyOp(isCopy), srcPath, destPath, failure)
+	}
+	return info, report, err
+}
+
+func (tx *Transaction) moveOrCopy(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath, isCopy bool) (file.FileInfo, error) {
+	return tx.applyWithInfo(moveOrCopyOp(isCopy), srcPath, destPath, func(fs *MemoryFileSystem, src *fspath.FileSystemPath, dest *fspath.FileSystemPath) (file.FileInfo, error) {
+		return fs.moveOrCopyPathLockFree(src, dest, isCopy, false, newReporter(false))
+	})
+}
+
+// CreateHardLink creates a hard link in the transaction
+func (tx *Transaction) CreateHardLink(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath) (file.FileInfo, error) {
+	return tx.applyWithInfo("link", srcPath, destPath, (*MemoryFileSystem).createHardLinkLockFree)
+}
+
+// CreateSymbolicLink creates a symbolic link in the transaction
+func (tx *Transaction) CreateSymbolicLink(srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath) (file.FileInfo, error) {
+	return tx.applyWithInfo("symlink", srcPath, destPath, (*MemoryFileSystem).createSymbolicLinkLockFree)
+}
+
+func (tx *Transaction) applyWithInfo(op string, srcPath *fspath.FileSystemPath, destPath *fspath.FileSystemPath, opFn func(fs *MemoryFileSystem, src *fspath.FileSystemPath, dest *fspath.FileSystemPath) (file.FileInfo, error)) (file.FileInfo, error) {
+	absSrc, err := absolutePath(srcPath)
+	if err != nil {
+		return nil, pathError(op, srcPath, err)
+	}
+	absDest, err := absolutePath(destPath)
+	if err != nil {
+		return nil, pathError(op, destPath, err)
+	}
+
+	var info file.FileInfo
+	err = tx.apply(func(fs *MemoryFileSystem) error {
+		var err error
+		info, err = opFn(fs, absSrc, absDest)
+		return err
+	}, func(err error) error {
+		return linkError(op, srcPath, destPath, err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// AppendAll writes data to a file in the transaction
+func (tx *Transaction) AppendAll(path *fspath.FileSystemPath, content []byte) error {
+	abs, err := absolutePath(path)
+	if err != nil {
+		return pathError("append", path, err)
+	}
+
+	content = append([]byte{}, content...)
+	return tx.apply(func(fs *MemoryFileSystem) error {
+		return fs.appendAllLockFree(abs, content)
+	}, pathErrorFn("append", path))
+}
+
+// Stat returns the attributes of a file as seen in the transaction
+func (tx *Transaction) Stat(path *fspath.FileSystemPath) (file.FileStat, error) {
+	shadow, abs, err := tx.read(path)
+	if err != nil {
+		return nil, pathError("stat", path, err)
+	}
+	return shadow.Stat(abs)
+}
+
+// ListFiles returns the files in a directory as seen in the transaction
+func (tx *Transaction) ListFiles(path *fspath.FileSystemPath) ([]file.FileInfo, error) {
+	shadow, abs, err := tx.read(path)
+	if err != nil {
+		return nil, pathError("list", path, err)
+	}
+	return shadow.ListFiles(abs)
+}
+
+// ListFilesPage returns a page of the files in a directory as seen in the transaction
+func (tx *Transaction) ListFilesPage(path *fspath.FileSystemPath, cursor string, limit int) ([]file.FileInfo, string, error) {
+	shadow, abs, err := tx.read(path)
+	if err != nil {
+		return nil, "", pathError("list", path, err)
+	}
+	return shadow.ListFilesPage(abs, cursor, limit)
+}
+
+// ReadAll returns the content of a file as seen in the transaction
+func (tx *Transaction) ReadAll(path *fspath.FileSystemPath) ([]byte, error) {
+	shadow, abs, err := tx.read(path)
+	if err != nil {
+		return nil, pathError("read", path, err)
+	}
+	return shadow.ReadAll(abs)
+}
+
+// apply applies run to the transaction snapshot and records it.
+// wrap records the operation and the paths in the errors of run,
+// also when it's applied on commit.
+// Failed operations are not recorded, but like outside of transactions
+// they can leave partial changes, visible only in the transaction.
+func (tx *Transaction) apply(run func(fs *MemoryFileSystem) error, wrap func(err error) error) error {
+	tx.Lock()
+	defer tx.Unlock()
+	if tx.shadow == nil {
+		return wrap(fserrors.ErrTransactionClosed)
+	}
+
+	tx.shadow.Lock()
+	defer tx.shadow.Unlock()
+	if err := run(tx.shadow); err != nil {
+		return wrap(err)
+	}
+	tx.ops = append(tx.ops, txOp{run: run, wrap: wrap})
+	return nil
+}
+
+// pathErrorFn returns a function recording the operation and the path in the errors
+func pathErrorFn(op string, path *fspath.FileSystemPath) func(err error) error {
+	return func(err error) error {
+		return pathError(op, path, err)
+	}
+}
+
+// read returns the snapshot and the absolute path to read from it
+func (tx *Transaction) read(path *fspath.FileSystemPath) (*MemoryFileSystem, *fspath.FileSystemPath, error) {
+	tx.RLock()
+	defer tx.RUnlock()
+	if tx.shadow == nil {
+		return nil, nil, fserrors.ErrTransactionClosed
+	}
+	abs, err := absolutePath(path)
+	return tx.shadow, abs, err
+}
+
+// absolutePath returns path without working directory.
+// Operations can't resolve relative paths because the working
+// directories belong to the file system and not to the snapshots.
+func absolutePath(path *fspath.FileSystemPath) (*fspath.FileSystemPath, error) {
+	return fspath.NewFileSystemPath(path.AbsolutePath(), nil)
+}
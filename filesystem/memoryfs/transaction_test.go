@@ -0,0 +1,240 @@
+package memoryfs_test
+
+import (
+	"errors"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fstx"
+	"material/filesystem/filesystem/memoryfs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func applyScript(tx fstx.Transaction) error {
+	p, _ := fspath.NewFileSystemPath("/app", nil)
+	if _, err := tx.Mkdir(p); err != nil {
+		return err
+	}
+	p, _ = fspath.NewFileSystemPath("/app/config.tmp", nil)
+	if _, err := tx.CreateRegularFile(p); err != nil {
+		return err
+	}
+	if err := tx.AppendAll(p, []byte("key=value")); err != nil {
+		return err
+	}
+	dest, _ := fspath.NewFileSystemPath("/app/config", nil)
+	_, err := tx.Move(p, dest)
+	return err
+}
+
+func TestTransaction(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem, tx fstx.Transaction)
+	}{
+		{
+			CaseName: "Changes are isolated until commit",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, tx fstx.Transaction) {
+				p, _ := fspath.NewFileSystemPath("/app/config", nil)
+				_, err := fs.Stat(p)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+
+				content, err := tx.ReadAll(p)
+				assert.Nil(t, err)
+				assert.Equal(t, []byte("key=value"), content)
+
+				assert.Nil(t, tx.Commit())
+				content, err = fs.ReadAll(p)
+				assert.Nil(t, err)
+				assert.Equal(t, []byte("key=value"), content)
+
+				p, _ = fspath.NewFileSystemPath("/app/config.tmp", nil)
+				_, err = fs.Stat(p)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+			},
+		},
+		{
+			CaseName: "Rmdir removes only empty directories",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, tx fstx.Transaction) {
+				p, _ := fspath.NewFileSystemPath("/app", nil)
+				_, err := tx.Rmdir(p)
+				assert.True(t, errors.Is(err, fserrors.ErrNotEmpty))
+
+				p, _ = fspath.NewFileSystemPath("/app/empty", nil)
+				_, err = tx.Mkdir(p)
+				assert.Nil(t, err)
+				_, err = tx.Rmdir(p)
+				assert.Nil(t, err)
+
+				assert.Nil(t, tx.Commit())
+				_, err = fs.Stat(p)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+			},
+		},
+		{
+			CaseName: "Rollback discards the changes",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, tx fstx.Transaction) {
+				tx.Rollback()
+
+				p, _ := fspath.NewFileSystemPath("/app", nil)
+				_, err := fs.Stat(p)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+				assert.Equal(t, 0, fs.StatFS().StoredBytes)
+
+				_, err = tx.Mkdir(p)
+				assert.True(t, errors.Is(err, fserrors.ErrTransactionClosed))
+				assert.True(t, errors.Is(tx.Commit(), fserrors.ErrTransactionClosed))
+			},
+		},
+		{
+			CaseName: "Commit fails on conflict and applies nothing",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, tx fstx.Transaction) {
+				p, _ := fspath.NewFileSystemPath("/app", nil)
+				_, err := fs.Mkdir(p)
+				assert.Nil(t, err)
+
+				err = tx.Commit()
+				assert.True(t, errors.Is(err, fserrors.ErrConflict))
+				var pathErr *fserrors.PathError
+				assert.True(t, errors.As(err, &pathErr))
+				assert.Equal(t, "mkdir", pathErr.Op)
+				assert.Equal(t, "/app", pathErr.Path)
+
+				files, err := fs.ListFiles(p)
+				assert.Nil(t, err)
+				assert.Len(t, files, 0)
+				assert.Equal(t, 0, fs.StatFS().StoredBytes)
+			},
+		},
+		{
+			CaseName: "Failed commit undoes the operations already applied",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, tx fstx.Transaction) {
+				tx.Rollback()
+				for _, name := range []string{"/data/log", "/data/old", "/data/tmp"} {
+					p, _ := fspath.NewFileSystemPath(name, nil)
+					assert.Nil(t, fs.AppendAll(p, []byte(name)))
+				}
+				stored := fs.StatFS().StoredBytes
+
+				tx = fs.Begin()
+				p, _ := fspath.NewFileSystemPath("/data/log", nil)
+				assert.Nil(t, tx.AppendAll(p, []byte(" appended")))
+				p, _ = fspath.NewFileSystemPath("/data/old", nil)
+				_, err := tx.Remove(p)
+				assert.Nil(t, err)
+				p, _ = fspath.NewFileSystemPath("/data/tmp", nil)
+				_, err = tx.Trash(p, "alice", false)
+				assert.Nil(t, err)
+				p, _ = fspath.NewFileSystemPath("/data/new", nil)
+				_, err = tx.Mkdir(p)
+				assert.Nil(t, err)
+
+				_, err = fs.Mkdir(p)
+				assert.Nil(t, err)
+				err = tx.Commit()
+				assert.True(t, errors.Is(err, fserrors.ErrConflict))
+
+				for _, name := range []string{"/data/log", "/data/old", "/data/tmp"} {
+					p, _ = fspath.NewFileSystemPath(name, nil)
+					content, err := fs.ReadAll(p)
+					assert.Nil(t, err)
+					assert.Equal(t, []byte(name), content)
+				}
+				p, _ = fspath.NewFileSystemPath("/data", nil)
+				files, err := fs.ListFiles(p)
+				assert.Nil(t, err)
+				assert.Len(t, files, 4)
+				assert.Len(t, fs.ListTrash("alice"), 0)
+				assert.Equal(t, stored, fs.StatFS().StoredBytes)
+			},
+		},
+		{
+			CaseName: "Directories are copied when the transaction uses them",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, tx fstx.Transaction) {
+				tx.Rollback()
+				p, _ := fspath.NewFileSystemPath("/other", nil)
+				_, err := fs.Mkdir(p)
+				assert.Nil(t, err)
+
+				tx = fs.Begin()
+				p, _ = fspath.NewFileSystemPath("/other/file", nil)
+				assert.Nil(t, fs.AppendAll(p, []byte("Hello")))
+
+				content, err := tx.ReadAll(p)
+				assert.Nil(t, err)
+				assert.Equal(t, []byte("Hello"), content)
+
+				assert.Nil(t, fs.AppendAll(p, []byte(" world")))
+				content, err = tx.ReadAll(p)
+				assert.Nil(t, err)
+				assert.Equal(t, []byte("Hello"), content)
+			},
+		},
+		{
+			CaseName: "Unrelated changes don't conflict",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, tx fstx.Transaction) {
+				p, _ := fspath.NewFileSystemPath("/other", nil)
+				_, err := fs.Mkdir(p)
+				assert.Nil(t, err)
+
+				assert.Nil(t, tx.Commit())
+				files, err := fs.ListFiles(p)
+				assert.Nil(t, err)
+				assert.Len(t, files, 0)
+
+				p, _ = fspath.NewFileSystemPath("/app", nil)
+				files, err = fs.ListFiles(p)
+				assert.Nil(t, err)
+				assert.Len(t, files, 1)
+			},
+		},
+		{
+			CaseName: "Relative paths are resolved from the working directory",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, tx fstx.Transaction) {
+				p, _ := fspath.NewFileSystemPath("/work", nil)
+				workDir, err := fs.Mkdir(p)
+				assert.Nil(t, err)
+
+				tx.Rollback()
+				tx = fs.Begin()
+				p, _ = fspath.NewFileSystemPath("notes", workDir)
+				assert.Nil(t, tx.AppendAll(p, []byte("Hello")))
+				assert.Nil(t, tx.Commit())
+
+				p, _ = fspath.NewFileSystemPath("/work/notes", nil)
+				content, err := fs.ReadAll(p)
+				assert.Nil(t, err)
+				assert.Equal(t, []byte("Hello"), content)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.CaseName, func(t *testing.T) {
+			fs := newFileSystem()
+			tx := fs.Begin()
+			if err := applyScript(tx); err != nil {
+				t.Fatal(err)
+			}
+			c.Assertions(t, fs, tx)
+		})
+	}
+}
+
+func TestTransactionCommitWritesOnce(t *testing.T) {
+	fs := newFileSystem(memoryfs.WithCapacity(len(secretContent) * 3 / 2))
+	fs.SetKeyProvider(newTestKeyProvider("key1", "key1"))
+	p, _ := fspath.NewFileSystemPath("/secrets", nil)
+	_, err := fs.Mkdir(p)
+	assert.Nil(t, err)
+	assert.Nil(t, fs.SetEncryption(p, true))
+
+	// every write of an encrypted file stores new blocks
+	tx := fs.Begin()
+	p, _ = fspath.NewFileSystemPath("/secrets/file", nil)
+	assert.Nil(t, tx.AppendAll(p, secretContent))
+	assert.Nil(t, tx.Commit())
+
+	assertContent(t, fs, "/secrets/file", secretContent)
+}
@@ -0,0 +1,244 @@
+package memoryfs
+
+import (
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fstrash"
+	"path/filepath"
+
+	"github.com/google/uuid"
+)
+
+// trashEntry is a file detached from the tree and kept in a user trash
+type trashEntry struct {
+	fstrash.Entry
+	file *inMemoryFile
+}
+
+// SetTrashRetention sets the limits applied to every trash when a file is trashed.
+// This implementation is thread safe.
+func (fs *MemoryFileSystem) SetTrashRetention(retention fstrash.Retention) {
+	fs.Lock()
+	defer fs.Unlock()
+	fs.trashRetention = retention
+}
+
+// Trash moves the file located at the specified path to the user trash.
+// The file keeps using memory until it's purged from the trash.
+// Trashed files can't be used as working directory.
+// The trash retention is applied after the file is trashed.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the file does not exist
+// - the file is a directory and isRecursive is false
+// - the file is the root
+func (fs *MemoryFileSystem) Trash(path *fspath.FileSystemPath, user string, isRecursive bool) (*fstrash.Entry, error) {
+	fs.Lock()
+	defer fs.Unlock()
+	entry, err := fs.trashLockFree(path, user, isRecursive)
+	return entry, pathError("trash", path, err)
+}
+
+// This method should be called only if the caller has already acquired a lock
+func (fs *MemoryFileSystem) trashLockFree(path *fspath.FileSystemPath, user string, isRecursive bool) (*fstrash.Entry, error) {
+	pathEnd, err := fs.traverseDirs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fileToTrash, found := fs.lookup(pathEnd, path.Base())
+	if !found {
+		return nil, fserrors.ErrNotExist
+	}
+	return fs.trashFile(fileToTrash, user, isRecursive)
+}
+
+// trashFile moves the file to the user trash
+func (fs *MemoryFileSystem) trashFile(fileToTrash *inMemoryFile, user string, isRecursive bool) (*fstrash.Entry, error) {
+	if fileToTrash == fs.root || fileToTrash.isVirtual {
+		return nil, fserrors.ErrOperationNotSupported
+	}
+	if fileToTrash.data.fileType == file.Directory && !isRecursive {
+		return nil, fserrors.ErrIsDir
+	}
+
+	fs.detachFromParent(fileToTrash)
+	fs.setTreeDeleted(fileToTrash, true)
+
+	entry := &trashEntry{
+		Entry: fstrash.Entry{
+			ID:           uuid.NewString(),
+			User:         user,
+			OriginalPath: fileToTrash.info.AbsolutePath(),
+			FileType:     fileToTrash.data.fileType,
+			DeletedAt:    fs.clock(),
+			Size:         fs.treeSize(fileToTrash),
+		},
+		file: fileToTrash,
+	}
+	fs.trash[user] = append(fs.trash[user], entry)
+	fs.purgeTrash(user, fs.trashRetention)
+
+	result := entry.Entry
+	return &result, nil
+}
+
+// ListTrash returns the entries in the user trash, oldest first.
+// This implementation is thread safe.
+func (fs *MemoryFileSystem) ListTrash(user string) []*fstrash.Entry {
+	fs.RLock()
+	defer fs.RUnlock()
+
+	entries := []*fstrash.Entry{}
+	for _, entry := range fs.trash[user] {
+		result := entry.Entry
+		entries = append(entries, &result)
+	}
+	return entries
+}
+
+// Restore moves the trash entry with the given id back to the tree.
+// If newPath is nil the entry is restored to its original path.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the entry does not exist
+// - the destination parent directory does not exist
+// - the destination already exists
+func (fs *MemoryFileSystem) Restore(user string, id string, newPath *fspath.FileSystemPath) (file.FileInfo, error) {
+	fs.Lock()
+	defer fs.Unlock()
+
+	idx := fs.findTrashEntry(user, id)
+	if idx < 0 {
+		return nil, nameError("restore", id, fserrors.ErrNotExist)
+	}
+	entry := fs.trash[user][idx]
+
+	if newPath == nil {
+		var err error
+		if newPath, err = fspath.NewFileSystemPath(entry.OriginalPath, nil); err != nil {
+			return nil, nameError("restore", entry.OriginalPath, err)
+		}
+	}
+
+	parent, err := fs.traverseDirs(newPath)
+	if err != nil {
+		return nil, pathError("restore", newPath, err)
+	}
+	name, err := fs.checkNewName(parent, newPath.Base())
+	if err != nil {
+		return nil, pathError("restore", newPath, err)
+	}
+	if _, found := fs.lookup(parent, name); found {
+		return nil, pathError("restore", newPath, fserrors.ErrExist)
+	}
+	if parent.isVirtual {
+		return nil, pathError("restore", newPath, fserrors.ErrOperationNotSupported)
+	}
+
+	entry.file.info.setDetached(filepath.Join(parent.info.AbsolutePath(), name))
+	fs.attachToParent(entry.file, parent)
+	fs.setTreeDeleted(entry.file, false)
+	entry.file.data.markChanged()
+
+	fs.trash[user] = append(fs.trash[user][:idx], fs.trash[user][idx+1:]...)
+	return entry.file.info, nil
+}
+
+// EmptyTrash purges the entries of the user trash exceeding the retention,
+// oldest first. If retention is nil every entry is purged.
+// Returns the purged entries.
+// This implementation is thread safe.
+func (fs *MemoryFileSystem) EmptyTrash(user string, retention *fstrash.Retention) []*fstrash.Entry {
+	fs.Lock()
+	defer fs.Unlock()
+
+	if retention == nil {
+		purged := fs.trash[user]
+		delete(fs.trash, user)
+		return fs.purgeTrashEntries(purged)
+	}
+	return fs.purgeTrash(user, *retention)
+}
+
+// purgeTrash purges the entries exceeding the retention and returns them
+func (fs *MemoryFileSystem) purgeTrash(user string, retention fstrash.Retention) []*fstrash.Entry {
+	entries := fs.trash[user]
+	kept := []*trashEntry{}
+	purged := []*trashEntry{}
+
+	now := fs.clock()
+	for _, entry := range entries {
+		if retention.MaxAge > 0 && now.Sub(entry.DeletedAt) > retention.MaxAge {
+			purged = append(purged, entry)
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+
+	if retention.MaxSize > 0 {
+		size := 0
+		for _, entry := range kept {
+			size += entry.Size
+		}
+		for len(kept) > 0 && size > retention.MaxSize {
+			size -= kept[0].Size
+			purged = append(purged, kept[0])
+			kept = kept[1:]
+		}
+	}
+
+	fs.trash[user] = kept
+	return fs.purgeTrashEntries(purged)
+}
+
+// purgeTrashEntries removes a link to every file in the entries and returns them
+func (fs *MemoryFileSystem) purgeTrashEntries(entries []*trashEntry) []*fstrash.Entry {
+	purged := []*fstrash.Entry{}
+	for _, entry := range entries {
+		fs.doWalkDir(entry.file, entry.OriginalPath, 0, map[*inMemoryFile]bool{}, func(_ string, f *inMemoryFile, _ int) error {
+			fs.markDeleted(f)
+			return nil
+		})
+		result := entry.Entry
+		purged = append(purged, &result)
+	}
+	return purged
+}
+
+func (fs *MemoryFileSystem) findTrashEntry(user string, id string) int {
+	for i, entry := range fs.trash[user] {
+		if entry.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// setTreeDeleted marks every file in the tree as deleted or not deleted
+func (fs *MemoryFileSystem) setTreeDeleted(root *inMemoryFile, isDeleted bool) {
+	fs.doWalkDir(root, root.info.AbsolutePath(), 0, map[*inMemoryFile]bool{}, func(_ string, f *inMemoryFile, _ int) error {
+		fs.journal.saveEntry(f)
+		f.isDeleted = isDeleted
+		return nil
+	})
+}
+
+// treeSize returns the sum of the file sizes in the tree.
+// Files with multiple hard links are counted once.
+func (fs *MemoryFileSystem) treeSize(root *inMemoryFile) int {
+	size := 0
+	seen := map[*inMemoryFileData]bool{}
+	fs.doWalkDir(root, root.info.AbsolutePath(), 0, map[*inMemoryFile]bool{}, func(_ string, f *inMemoryFile, _ int) error {
+		if !seen[f.data] {
+			seen[f.data] = true
+			fileSize, _ := f.data.usage()
+			size += fileSize
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,154 @@
+package memoryfs_test
+
+import (
+	"errors"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fstrash"
+	"material/filesystem/filesystem/memoryfs"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func initializeTrash() (*memoryfs.MemoryFileSystem, error) {
+	fs := newFileSystem()
+	p, _ := fspath.NewFileSystemPath("/dir/subdir", nil)
+	if _, err := fs.MkdirAll(p); err != nil {
+		return nil, err
+	}
+	if err := createFileWithContent(fs, "/dir/subdir/file", []byte("Hello")); err != nil {
+		return nil, err
+	}
+	return fs, createFileWithContent(fs, "/file", []byte("Hello world!"))
+}
+
+func TestTrash(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem)
+	}{
+		{
+			CaseName: "Trash and restore a file",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/file", nil)
+				entry, err := fs.Trash(p, "alice", false)
+				assert.Nil(t, err)
+				assert.Equal(t, "/file", entry.OriginalPath)
+				assert.Equal(t, file.RegularFile, entry.FileType)
+				assert.Equal(t, 12, entry.Size)
+
+				_, err = fs.Stat(p)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+				assert.Len(t, fs.ListTrash("alice"), 1)
+				assert.Len(t, fs.ListTrash("bob"), 0)
+
+				_, err = fs.Restore("bob", entry.ID, nil)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+
+				info, err := fs.Restore("alice", entry.ID, nil)
+				assert.Nil(t, err)
+				assert.Equal(t, "/file", info.AbsolutePath())
+				content, err := fs.ReadAll(p)
+				assert.Nil(t, err)
+				assert.Equal(t, []byte("Hello world!"), content)
+				assert.Len(t, fs.ListTrash("alice"), 0)
+			},
+		},
+		{
+			CaseName: "Trash and restore a directory to a new path",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/dir", nil)
+				_, err := fs.Trash(p, "alice", false)
+				assert.True(t, errors.Is(err, fserrors.ErrIsDir))
+
+				entry, err := fs.Trash(p, "alice", true)
+				assert.Nil(t, err)
+				assert.Equal(t, file.Directory, entry.FileType)
+				assert.Equal(t, 5, entry.Size)
+
+				newPath, _ := fspath.NewFileSystemPath("/restored", nil)
+				_, err = fs.Restore("alice", entry.ID, newPath)
+				assert.Nil(t, err)
+
+				p, _ = fspath.NewFileSystemPath("/restored/subdir/file", nil)
+				stat, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.Equal(t, "/restored/subdir/file", stat.AbsolutePath())
+			},
+		},
+		{
+			CaseName: "Restore fails if the destination exists",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/file", nil)
+				entry, err := fs.Trash(p, "alice", false)
+				assert.Nil(t, err)
+				_, err = fs.CreateRegularFile(p)
+				assert.Nil(t, err)
+
+				_, err = fs.Restore("alice", entry.ID, nil)
+				assert.True(t, errors.Is(err, fserrors.ErrExist))
+				assert.Len(t, fs.ListTrash("alice"), 1)
+			},
+		},
+		{
+			CaseName: "Trashing the root is not supported",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/", nil)
+				_, err := fs.Trash(p, "alice", true)
+				assert.NotNil(t, err)
+			},
+		},
+		{
+			CaseName: "Empty trash",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/file", nil)
+				_, err := fs.Trash(p, "alice", false)
+				assert.Nil(t, err)
+				p, _ = fspath.NewFileSystemPath("/dir", nil)
+				_, err = fs.Trash(p, "alice", true)
+				assert.Nil(t, err)
+
+				purged := fs.EmptyTrash("alice", &fstrash.Retention{MaxAge: time.Hour})
+				assert.Len(t, purged, 0)
+				purged = fs.EmptyTrash("alice", &fstrash.Retention{MaxSize: 10})
+				assert.Len(t, purged, 1)
+				assert.Equal(t, "/file", purged[0].OriginalPath)
+
+				purged = fs.EmptyTrash("alice", nil)
+				assert.Len(t, purged, 1)
+				assert.Len(t, fs.ListTrash("alice"), 0)
+				assert.Equal(t, 0, fs.StatFS().StoredBytes)
+			},
+		},
+		{
+			CaseName: "Retention is applied when trashing",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				fs.SetTrashRetention(fstrash.Retention{MaxSize: 12})
+
+				p, _ := fspath.NewFileSystemPath("/file", nil)
+				_, err := fs.Trash(p, "alice", false)
+				assert.Nil(t, err)
+				p, _ = fspath.NewFileSystemPath("/dir", nil)
+				_, err = fs.Trash(p, "alice", true)
+				assert.Nil(t, err)
+
+				entries := fs.ListTrash("alice")
+				assert.Len(t, entries, 1)
+				assert.Equal(t, "/dir", entries[0].OriginalPath)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.CaseName, func(t *testing.T) {
+			fs, err := initializeTrash()
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.Assertions(t, fs)
+		})
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"material/filesystem/filesystem/file"
 	"material/filesystem/filesystem/fserrors"
 	"material/filesystem/filesystem/fspath"
+	"path/filepath"
 )
 
+// MAX_LINK_DEPTH is the default maximum number of symbolic links followed to resolve a path
 const MAX_LINK_DEPTH = 40
 
 // traverseDirs traverses the path.Dir()
@@ -54,8 +56,8 @@ func (fs *MemoryFileSystem) traverseToDirWithCreateParentDirs(path *fspath.FileS
 		return nil, err
 	}
 
-	if dir.info.fileType != file.Directory {
-		return nil, fserrors.ErrInvalidFileType
+	if dir.data.fileType != file.Directory {
+		return nil, fserrors.ErrNotDir
 	}
 
 	return dir, nil
@@ -73,8 +75,7 @@ func (fs *MemoryFileSystem) traverse(path *fspath.FileSystemPath, createDirs boo
 	}
 
 	// Move through every dir
-	pathDirs := pathDirs(path)
-	dir, err := fs.traverseFromRootToLastDir(pathRoot, pathDirs, createDirs, linkDepth)
+	dir, err := fs.traverseDirsWithCache(path, pathRoot, createDirs, linkDepth)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -88,6 +89,31 @@ func (fs *MemoryFileSystem) traverse(path *fspath.FileSystemPath, createDirs boo
 	return dir, targetFile, nil
 }
 
+// traverseDirsWithCache moves through every path.Dir(), unless the directory is in the path cache.
+// The symbolic links followed while resolving a link target are not cached,
+// since the cached entries don't count the links they went through.
+func (fs *MemoryFileSystem) traverseDirsWithCache(path *fspath.FileSystemPath, pathRoot *inMemoryFile, createDirs bool, linkDepth int) (*inMemoryFile, error) {
+	dirPath := path.Dir()
+	if dirPath == "/" || dirPath == "." || linkDepth > 0 {
+		return fs.traverseFromRootToLastDir(pathRoot, pathDirs(path), createDirs, linkDepth)
+	}
+
+	// the base of a relative path can be "." or "..", so the key is the joined path.Dir()
+	if !path.IsAbs() {
+		dirPath = filepath.Join(pathRoot.info.AbsolutePath(), dirPath)
+	}
+	key := fs.pathKey(dirPath)
+	if dir, found := fs.pathCache.get(key); found {
+		return dir, nil
+	}
+	dir, err := fs.traverseFromRootToLastDir(pathRoot, pathDirs(path), createDirs, linkDepth)
+	if err != nil {
+		return nil, err
+	}
+	fs.pathCache.put(key, dir)
+	return dir, nil
+}
+
 // traverseFromRootToLastDir moves through every path.Dir()
 // If createParentDirs is true any missing parent directory is created.
 // Any symbolic link is resolved.
@@ -106,8 +132,8 @@ func (fs *MemoryFileSystem) traverseFromRootToLastDir(pathRoot *inMemoryFile, pa
 			return nil, linkErr
 		}
 
-		if next.info.fileType != file.Directory {
-			return nil, fserrors.ErrInvalidFileType
+		if next.data.fileType != file.Directory {
+			return nil, fserrors.ErrNotDir
 		}
 
 		curr = next
@@ -119,7 +145,7 @@ func (fs *MemoryFileSystem) traverseFromRootToLastDir(pathRoot *inMemoryFile, pa
 // moveToBase moves from the last dir in path.Dir() to path.Base()
 // If base is a symlink is resolved only if skipLink is false.
 func (fs *MemoryFileSystem) moveToBase(dir *inMemoryFile, fileName string, skipLink bool, linkDepth int) (*inMemoryFile, error) {
-	targetFile, found := dir.fileMap[fileName]
+	targetFile, found := fs.lookup(dir, fileName)
 	if !found {
 		return nil, nil
 	}
@@ -139,7 +165,7 @@ func (fs *MemoryFileSystem) moveToBase(dir *inMemoryFile, fileName string, skipL
 // moveToNext moves to the nextFileName.
 // if createDirs is true creates any missing parent directories.
 func (fs *MemoryFileSystem) moveToNext(curr *inMemoryFile, nextFileName string, createDirs bool) (*inMemoryFile, error) {
-	next, found := curr.fileMap[nextFileName]
+	next, found := fs.lookup(curr, nextFileName)
 	if found {
 		return next, nil
 	}
@@ -154,11 +180,11 @@ func (fs *MemoryFileSystem) moveToNext(curr *inMemoryFile, nextFileName string,
 // resolveSymlink tries to resolve a symlink and returns an error if the link points to a file
 // that does not exixt or too many symlink were followed.
 func (fs *MemoryFileSystem) resolveSymlink(currentFile *inMemoryFile, linkDepth int) (*inMemoryFile, error) {
-	if currentFile.info.fileType != file.SymbolicLink {
+	if currentFile.data.fileType != file.SymbolicLink {
 		return currentFile, nil
 	}
 
-	if linkDepth >= MAX_LINK_DEPTH {
+	if linkDepth >= fs.maxLinkDepth {
 		return nil, fserrors.ErrTooManyLinks
 	}
 
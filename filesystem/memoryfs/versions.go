@@ -0,0 +1,181 @@
+package memoryfs
+
+import (
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsversion"
+)
+
+// fileVersion is a snapshot of a file content.
+// The blocks are shared with the content and the other versions.
+type fileVersion struct {
+	fsversion.Version
+	content *blockList
+}
+
+// SetVersionPolicy sets the limits of the versions kept for every regular file.
+// This implementation is thread safe.
+func (fs *MemoryFileSystem) SetVersionPolicy(policy fsversion.Policy) {
+	fs.Lock()
+	defer fs.Unlock()
+	fs.versionPolicy = policy
+}
+
+// ListVersions returns the versions of the named file, oldest first.
+// If the file is a symbolic link, the link is resolved.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the file does not exist
+// - the file is not a regular file
+func (fs *MemoryFileSystem) ListVersions(path *fspath.FileSystemPath) ([]*fsversion.Version, error) {
+	fs.RLock()
+	defer fs.RUnlock()
+
+	f, err := fs.traverseToRegularFile(path)
+	if err != nil {
+		return nil, pathError("versions", path, err)
+	}
+
+	f.data.RLock()
+	defer f.data.RUnlock()
+	versions := []*fsversion.Version{}
+	for _, v := range f.data.versions {
+		result := v.Version
+		versions = append(versions, &result)
+	}
+	return versions, nil
+}
+
+// ReadVersion returns the content of a version of the named file.
+// If the file is a symbolic link, the link is resolved.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the file or the version does not exist
+// - the file is not a regular file
+func (fs *MemoryFileSystem) ReadVersion(path *fspath.FileSystemPath, id int) ([]byte, error) {
+	fs.RLock()
+	defer fs.RUnlock()
+
+	f, err := fs.traverseToRegularFile(path)
+	if err != nil {
+		return nil, pathError("readversion", path, err)
+	}
+
+	f.data.RLock()
+	defer f.data.RUnlock()
+	v := f.data.findVersion(id)
+	if v == nil {
+		return nil, pathError("readversion", path, fserrors.ErrNotExist)
+	}
+	content, err := v.content.bytes()
+	return content, pathError("readversion", path, err)
+}
+
+// RestoreVersion replaces the content of the named file with a version.
+// The restored content is captured as a new version.
+// If the file is a symbolic link, the link is resolved.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the file or the version does not exist
+// - the file is not a regular file
+func (fs *MemoryFileSystem) RestoreVersion(path *fspath.FileSystemPath, id int) error {
+	fs.RLock()
+	defer fs.RUnlock()
+
+	f, err := fs.traverseToRegularFile(path)
+	if err != nil {
+		return pathError("restoreversion", path, err)
+	}
+	return pathError("restoreversion", path, f.data.restoreVersion(id, fs.versionPolicy))
+}
+
+// traverseToRegularFile returns the regular file located at path, resolving symbolic links
+func (fs *MemoryFileSystem) traverseToRegularFile(path *fspath.FileSystemPath) (*inMemoryFile, error) {
+	f, err := fs.traverseToBase(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRegularFile(f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// captureVersion snapshots the content and removes the versions exceeding the policy.
+// This is a noop if the policy is disabled.
+func (d *inMemoryFileData) captureVersion(policy fsversion.Policy) {
+	d.modifiedSinceVersion = false
+	if !policy.Enabled() {
+		return
+	}
+
+	d.versions = append(d.versions, &fileVersion{
+		Version: fsversion.Version{
+			ID:        d.nextVersionID,
+			Size:      d.content.size,
+			ModTime:   d.modTime,
+			CreatedAt: d.clock(),
+		},
+		content: d.content.clone(d.store),
+	})
+	d.nextVersionID++
+	d.pruneVersions(policy)
+}
+
+// pruneVersions releases the versions exceeding the policy, keeping the most recent one
+func (d *inMemoryFileData) pruneVersions(policy fsversion.Policy) {
+	now := d.clock()
+	kept := []*fileVersion{}
+	for i, v := range d.versions {
+		isLast := i == len(d.versions)-1
+		tooMany := policy.MaxVersions > 0 && len(d.versions)-i > policy.MaxVersions
+		tooOld := policy.MaxAge > 0 && now.Sub(v.CreatedAt) > policy.MaxAge
+		if !isLast && (tooMany || tooOld) {
+			v.content.release(d.store)
+			continue
+		}
+		kept = append(kept, v)
+	}
+	d.versions = kept
+}
+
+// restoreVersion replaces the content with the version with the given id.
+// This implementation is thread safe.
+func (d *inMemoryFileData) restoreVersion(id int, policy fsversion.Policy) error {
+	d.Lock()
+	defer d.Unlock()
+
+	v := d.findVersion(id)
+	if v == nil {
+		return fserrors.ErrNotExist
+	}
+
+	d.content.release(d.store)
+	d.content = v.content.clone(d.store)
+	d.modTime = d.clock()
+	d.changeTime = d.modTime
+	d.checksums = nil
+	d.captureVersion(policy)
+	return nil
+}
+
+// findVersion returns the version with the given id, nil if not found
+func (d *inMemoryFileData) findVersion(id int) *fileVersion {
+	for _, v := range d.versions {
+		if v.ID == id {
+			return v
+		}
+	}
+	return nil
+}
+
+// releaseVersions releases every version
+func (d *inMemoryFileData) releaseVersions() {
+	for _, v := range d.versions {
+		v.content.release(d.store)
+	}
+	d.versions = nil
+}
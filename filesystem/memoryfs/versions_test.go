@@ -0,0 +1,162 @@
+package memoryfs_test
+
+import (
+	"errors"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsversion"
+	"material/filesystem/filesystem/memoryfs"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeVersions(fs *memoryfs.MemoryFileSystem, path string, contents ...string) error {
+	p, _ := fspath.NewFileSystemPath(path, nil)
+	for _, content := range contents {
+		if err := fs.AppendAll(p, []byte(content)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestVersions(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Policy     fsversion.Policy
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem)
+	}{
+		{
+			CaseName: "Versions are not captured without a policy",
+			Policy:   fsversion.Policy{},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				assert.Nil(t, writeVersions(fs, "/config", "a", "b"))
+
+				p, _ := fspath.NewFileSystemPath("/config", nil)
+				versions, err := fs.ListVersions(p)
+				assert.Nil(t, err)
+				assert.Len(t, versions, 0)
+			},
+		},
+		{
+			CaseName: "Keep the last N versions",
+			Policy:   fsversion.Policy{MaxVersions: 2},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				assert.Nil(t, writeVersions(fs, "/config", "a", "b", "c"))
+
+				p, _ := fspath.NewFileSystemPath("/config", nil)
+				versions, err := fs.ListVersions(p)
+				assert.Nil(t, err)
+				assert.Len(t, versions, 2)
+				assert.Equal(t, 2, versions[0].ID)
+				assert.Equal(t, 3, versions[1].ID)
+				assert.Equal(t, 3, versions[1].Size)
+
+				content, err := fs.ReadVersion(p, 2)
+				assert.Nil(t, err)
+				assert.Equal(t, []byte("ab"), content)
+
+				_, err = fs.ReadVersion(p, 1)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+			},
+		},
+		{
+			CaseName: "Versions are captured on close after write",
+			Policy:   fsversion.Policy{MaxVersions: 10},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				assert.Nil(t, writeVersions(fs, "/config", "Hello"))
+
+				p, _ := fspath.NewFileSystemPath("/config", nil)
+				fd, err := fs.Open(p)
+				assert.Nil(t, err)
+				_, err = fs.WriteAt(fd, []byte(" world"), 5)
+				assert.Nil(t, err)
+
+				versions, _ := fs.ListVersions(p)
+				assert.Len(t, versions, 1)
+				fs.Close(fd)
+				versions, _ = fs.ListVersions(p)
+				assert.Len(t, versions, 2)
+
+				// closing without writing does not capture a version
+				fd, err = fs.Open(p)
+				assert.Nil(t, err)
+				fs.Close(fd)
+				versions, _ = fs.ListVersions(p)
+				assert.Len(t, versions, 2)
+			},
+		},
+		{
+			CaseName: "Restore a version",
+			Policy:   fsversion.Policy{MaxVersions: 10},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				assert.Nil(t, writeVersions(fs, "/config", "a", "b"))
+
+				p, _ := fspath.NewFileSystemPath("/config", nil)
+				assert.Nil(t, fs.RestoreVersion(p, 1))
+				content, err := fs.ReadAll(p)
+				assert.Nil(t, err)
+				assert.Equal(t, []byte("a"), content)
+
+				versions, _ := fs.ListVersions(p)
+				assert.Len(t, versions, 3)
+				assert.Equal(t, 1, versions[2].Size)
+
+				err = fs.RestoreVersion(p, 42)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+			},
+		},
+		{
+			CaseName: "Old versions expire, the last one is kept",
+			Policy:   fsversion.Policy{MaxAge: time.Nanosecond},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				assert.Nil(t, writeVersions(fs, "/config", "a", "b", "c"))
+
+				p, _ := fspath.NewFileSystemPath("/config", nil)
+				versions, _ := fs.ListVersions(p)
+				assert.Len(t, versions, 1)
+				assert.Equal(t, 3, versions[0].ID)
+			},
+		},
+		{
+			CaseName: "Versions share blocks with the content",
+			Policy:   fsversion.Policy{MaxVersions: 10},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				content := randomContent(1, 100<<10)
+				assert.Nil(t, createFileWithContent(fs, "/data", content))
+				stored := fs.StatFS().StoredBytes
+
+				p, _ := fspath.NewFileSystemPath("/data", nil)
+				versions, _ := fs.ListVersions(p)
+				assert.Len(t, versions, 1)
+				assert.Equal(t, stored, fs.StatFS().StoredBytes)
+
+				_, err := fs.Remove(p)
+				assert.Nil(t, err)
+				assert.Equal(t, 0, fs.StatFS().StoredBytes)
+			},
+		},
+		{
+			CaseName: "Directories have no versions",
+			Policy:   fsversion.Policy{MaxVersions: 10},
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem) {
+				p, _ := fspath.NewFileSystemPath("/dir", nil)
+				_, err := fs.Mkdir(p)
+				assert.Nil(t, err)
+
+				_, err = fs.ListVersions(p)
+				assert.True(t, errors.Is(err, fserrors.ErrIsDir))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.CaseName, func(t *testing.T) {
+			fs := newFileSystem()
+			fs.SetVersionPolicy(c.Policy)
+			c.Assertions(t, fs)
+		})
+	}
+}
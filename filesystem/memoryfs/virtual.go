@@ -0,0 +1,101 @@
+package memoryfs
+
+import (
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsversion"
+	"material/filesystem/filesystem/fsvirtual"
+	"path/filepath"
+	"strings"
+)
+
+// Mount adds the virtual file or directory at the specified path.
+// Virtual files are read, listed and walked like the other files,
+// their content is generated by the provider when they are opened.
+// Virtual files can't be removed, moved, trashed or linked, and
+// files can't be created in virtual directories.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - the file name is invalid
+// - the file already exists
+// - any of the directory in the path does not exist
+// - the node or any of its entries is not a fsvirtual.File or a fsvirtual.Dir
+// - two entries of a directory have the same name ignoring case, in a case-insensitive file system
+func (fs *MemoryFileSystem) Mount(path *fspath.FileSystemPath, node fsvirtual.Node) error {
+	fs.Lock()
+	defer fs.Unlock()
+
+	if err := checkFilePath(path); err != nil {
+		return pathError("mount", path, err)
+	}
+
+	parent, err := fs.traverseDirs(path)
+	if err != nil {
+		return pathError("mount", path, err)
+	}
+	if _, found := fs.lookup(parent, path.Base()); found {
+		return pathError("mount", path, fserrors.ErrExist)
+	}
+	if parent.isVirtual {
+		return pathError("mount", path, fserrors.ErrOperationNotSupported)
+	}
+
+	mounted, err := fs.newVirtualFile(filepath.Join(parent.info.AbsolutePath(), path.Base()), node)
+	if err != nil {
+		return pathError("mount", path, err)
+	}
+	fs.attachToParent(mounted, parent)
+	return nil
+}
+
+// newVirtualFile creates the tree of the virtual node
+func (fs *MemoryFileSystem) newVirtualFile(absolutePath string, node fsvirtual.Node) (*inMemoryFile, error) {
+	var newFile *inMemoryFile
+	switch n := node.(type) {
+	case fsvirtual.File:
+		newFile = fs.newFile(absolutePath, file.RegularFile)
+		newFile.data.virtual = n
+	case fsvirtual.Dir:
+		newFile = fs.newFile(absolutePath, file.Directory)
+		for name, entry := range n {
+			if err := checkFileName(name); err != nil {
+				return nil, err
+			}
+			if name == "" || strings.Contains(name, "/") {
+				return nil, fserrors.ErrInvalid
+			}
+			// names may differ only in case
+			if _, found := fs.lookup(newFile, name); found {
+				return nil, fserrors.ErrExist
+			}
+			child, err := fs.newVirtualFile(filepath.Join(absolutePath, name), entry)
+			if err != nil {
+				return nil, err
+			}
+			newFile.addChild(fs.nameKey(name), child)
+			child.fileMap[".."] = newFile
+			child.info.setParent(newFile.info)
+		}
+	default:
+		return nil, fserrors.ErrInvalid
+	}
+
+	newFile.isVirtual = true
+	return newFile, nil
+}
+
+// openVirtual generates the content of the virtual file and returns the new descriptor.
+// The caller must have registered an open descriptor on data.
+// This method should be called without holding the fs lock, since the provider may use the file system.
+func (fs *MemoryFileSystem) openVirtual(data *inMemoryFileData, flags file.OpenFlag, owner string) (string, error) {
+	handle, err := data.virtual.Open()
+	if err != nil {
+		fs.openFiles.Lock()
+		defer fs.openFiles.Unlock()
+		fs.closeData(data, fsversion.Policy{})
+		return "", err
+	}
+	return fs.addDescriptor(&fileDescriptor{data: data, offset: 0, flags: flags, handle: handle, owner: owner}), nil
+}
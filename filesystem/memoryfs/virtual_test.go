@@ -0,0 +1,168 @@
+package memoryfs_test
+
+import (
+	"errors"
+	"fmt"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsvirtual"
+	"material/filesystem/filesystem/memoryfs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVirtualFiles(t *testing.T) {
+	cases := []struct {
+		CaseName   string
+		Assertions func(t *testing.T, fs *memoryfs.MemoryFileSystem, written *[]string)
+	}{
+		{
+			CaseName: "Content is generated when the file is opened",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, written *[]string) {
+				p, _ := fspath.NewFileSystemPath("/proc/descriptors", nil)
+				content, err := fs.ReadAll(p)
+				assert.Nil(t, err)
+				assert.Equal(t, []byte("0"), content)
+
+				devNull, _ := fspath.NewFileSystemPath("/dev/null", nil)
+				nullFd, err := fs.Open(devNull)
+				assert.Nil(t, err)
+				fd, err := fs.Open(p)
+				assert.Nil(t, err)
+				defer fs.Close(fd)
+
+				// the content doesn't change while the file is open
+				fs.Close(nullFd)
+				buff := make([]byte, 10)
+				n, err := fs.Read(fd, buff)
+				assert.Nil(t, err)
+				assert.Equal(t, []byte("1"), buff[:n])
+			},
+		},
+		{
+			CaseName: "Virtual files are listed and walked",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, written *[]string) {
+				p, _ := fspath.NewFileSystemPath("/dev", nil)
+				files, err := fs.ListFiles(p)
+				assert.Nil(t, err)
+				assert.Len(t, files, 3)
+				assert.Equal(t, "/dev/null", files[0].AbsolutePath())
+
+				stat, err := fs.Stat(p)
+				assert.Nil(t, err)
+				assert.Equal(t, file.Directory, stat.FileType())
+
+				root, _ := fspath.NewFileSystemPath("/", nil)
+				paths := []string{}
+				err = fs.WalkDir(root, func(path string, stat file.FileStat, depth int) error {
+					paths = append(paths, path)
+					return nil
+				}, false)
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/", "/dev", "/dev/null", "/dev/urandom", "/dev/zero", "/proc", "/proc/control", "/proc/descriptors"}, paths)
+			},
+		},
+		{
+			CaseName: "Writes are handled by the provider",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, written *[]string) {
+				p, _ := fspath.NewFileSystemPath("/proc/control", nil)
+				assert.Nil(t, fs.AppendAll(p, []byte("flush")))
+				assert.Equal(t, []string{"flush"}, *written)
+
+				p, _ = fspath.NewFileSystemPath("/proc/descriptors", nil)
+				err := fs.AppendAll(p, []byte("1"))
+				assert.True(t, errors.Is(err, fserrors.ErrOperationNotSupported))
+			},
+		},
+		{
+			CaseName: "Standard devices",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, written *[]string) {
+				p, _ := fspath.NewFileSystemPath("/dev/null", nil)
+				assert.Nil(t, fs.AppendAll(p, []byte("Hello")))
+				content, err := fs.ReadAll(p)
+				assert.Nil(t, err)
+				assert.Empty(t, content)
+
+				p, _ = fspath.NewFileSystemPath("/dev/zero", nil)
+				_, err = fs.ReadAll(p)
+				assert.True(t, errors.Is(err, fserrors.ErrOperationNotSupported))
+				fd, err := fs.OpenFile(p, file.OpenRead)
+				assert.Nil(t, err)
+				buff := []byte("Hello")
+				n, err := fs.Read(fd, buff)
+				assert.Nil(t, err)
+				assert.Equal(t, make([]byte, 5), buff[:n])
+				fs.Close(fd)
+
+				p, _ = fspath.NewFileSystemPath("/dev/urandom", nil)
+				fd, err = fs.OpenFile(p, file.OpenRead)
+				assert.Nil(t, err)
+				buff = make([]byte, 64)
+				n, err = fs.Read(fd, buff)
+				assert.Nil(t, err)
+				assert.Equal(t, 64, n)
+				assert.NotEqual(t, make([]byte, 64), buff)
+				fs.Close(fd)
+			},
+		},
+		{
+			CaseName: "Virtual files can't be changed",
+			Assertions: func(t *testing.T, fs *memoryfs.MemoryFileSystem, written *[]string) {
+				p, _ := fspath.NewFileSystemPath("/dev/null", nil)
+				_, err := fs.Remove(p)
+				assert.True(t, errors.Is(err, fserrors.ErrOperationNotSupported))
+
+				dest, _ := fspath.NewFileSystemPath("/null", nil)
+				_, err = fs.Move(p, dest)
+				assert.True(t, errors.Is(err, fserrors.ErrOperationNotSupported))
+
+				p, _ = fspath.NewFileSystemPath("/dev/tty", nil)
+				_, err = fs.CreateRegularFile(p)
+				assert.True(t, errors.Is(err, fserrors.ErrOperationNotSupported))
+
+				p, _ = fspath.NewFileSystemPath("/dev", nil)
+				err = fs.Mount(p, fsvirtual.Devices())
+				assert.True(t, errors.Is(err, fserrors.ErrExist))
+
+				tx := fs.Begin()
+				defer tx.Rollback()
+				p, _ = fspath.NewFileSystemPath("/proc/control", nil)
+				err = tx.AppendAll(p, []byte("flush"))
+				assert.True(t, errors.Is(err, fserrors.ErrOperationNotSupported))
+				assert.Empty(t, *written)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.CaseName, func(t *testing.T) {
+			fs := newFileSystem()
+			written := []string{}
+			proc := fsvirtual.Dir{
+				"descriptors": fsvirtual.FileFuncs{
+					ReadFn: func() ([]byte, error) {
+						return []byte(fmt.Sprint(fs.StatFS().OpenDescriptors)), nil
+					},
+				},
+				"control": fsvirtual.FileFuncs{
+					WriteFn: func(content []byte) error {
+						written = append(written, string(content))
+						return nil
+					},
+				},
+			}
+
+			p, _ := fspath.NewFileSystemPath("/proc", nil)
+			if err := fs.Mount(p, proc); err != nil {
+				t.Fatal(err)
+			}
+			p, _ = fspath.NewFileSystemPath("/dev", nil)
+			if err := fs.Mount(p, fsvirtual.Devices()); err != nil {
+				t.Fatal(err)
+			}
+			c.Assertions(t, fs, &written)
+		})
+	}
+}
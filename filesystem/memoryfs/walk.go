@@ -11,6 +11,7 @@ type visitFn func(string, *inMemoryFile) error
 // and calls walkFn for each file or directory matching the filter.
 // Optionally follow symbolic links.
 // If walkFn returns an error, the function stops immediately.
+// See WalkDir for an ordered and cycle safe walk.
 //
 // Returns an error when:
 // - too many links were followed
@@ -19,7 +20,7 @@ type visitFn func(string, *inMemoryFile) error
 func (fs *MemoryFileSystem) Walk(path *fspath.FileSystemPath, walkFn file.WalkFn, filterFn file.FilterFn, followLinks bool) error {
 	pathRoot, err := fs.traverseToBase(path)
 	if err != nil {
-		return err
+		return pathError("walk", path, err)
 	}
 
 	return fs.doWalk(pathRoot, walkFn, filterFn, followLinks, 0)
@@ -37,7 +38,7 @@ func (fs *MemoryFileSystem) doWalk(rootFile *inMemoryFile, walkFn file.WalkFn, f
 	}
 
 	// Optionally follow links
-	if followLinks && rootFile.info.fileType == file.SymbolicLink {
+	if followLinks && rootFile.data.fileType == file.SymbolicLink {
 		currLink, err := fs.resolveSymlink(rootFile, linkDepth)
 		if err != nil {
 			return err
@@ -55,13 +56,33 @@ func (fs *MemoryFileSystem) doWalk(rootFile *inMemoryFile, walkFn file.WalkFn, f
 }
 
 func (fs *MemoryFileSystem) visitDir(rootFile *inMemoryFile, visitFn visitFn) error {
-	for fileName, file := range rootFile.fileMap {
+	rootFile.materialize()
+	for key, file := range rootFile.fileMap {
 		// skip special keys to avoid infinite cycle
-		if fileName == ".." || fileName == "." || fileName == "/" {
+		if key == ".." || key == "." || key == "/" {
+			continue
+		}
+
+		if err := visitFn(file.info.Name(), file); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// visitDirInOrder visits the directory entries in lexical order.
+// visitFn can add and remove entries of the directory,
+// the entries added or removed by visitFn are not visited.
+func (fs *MemoryFileSystem) visitDirInOrder(rootFile *inMemoryFile, visitFn visitFn) error {
+	names := append([]string(nil), rootFile.childNames()...)
+	for _, name := range names {
+		file, found := fs.lookup(rootFile, name)
+		if !found {
 			continue
 		}
 
-		if err := visitFn(fileName, file); err != nil {
+		if err := visitFn(name, file); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,124 @@
+package memoryfs
+
+import (
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fspath"
+	"path/filepath"
+)
+
+type walkDirFn func(path string, f *inMemoryFile, depth int) error
+
+// WalkDir walks the file tree rooted at path, calling walkFn for each file or directory
+// in the tree, including path. The files in a directory are visited in lexical order.
+// If walkFn returns file.SkipDir for a directory, its content is skipped. If walkFn returns
+// file.SkipDir for any other file, the remaining files in the parent directory are skipped.
+// If walkFn returns file.SkipAll, the walk stops without errors.
+// If walkFn returns any other error, the walk stops and the error is returned.
+// Optionally follow symbolic links: every directory is visited at most once, so cycles
+// are not an error. Broken links are visited as they are. A followed link is visited
+// with its own name and path and the attributes of its target, and the files in a
+// linked directory are visited at paths under the link.
+// The file system is read locked during the walk, so walkFn must not modify it.
+// This implementation is thread safe.
+//
+// Returns an error when:
+// - path does not exist
+// - walkFn returns an error
+func (fs *MemoryFileSystem) WalkDir(path *fspath.FileSystemPath, walkFn file.WalkDirFn, followLinks bool) error {
+	fs.RLock()
+	defer fs.RUnlock()
+
+	pathRoot, err := fs.traverseToBase(path)
+	if err != nil {
+		return pathError("walk", path, err)
+	}
+
+	root := &walkedFile{path: path.AbsolutePath(), entry: pathRoot, target: pathRoot}
+	err = fs.walkTree(root, followLinks, map[*inMemoryFile]bool{}, func(w *walkedFile) error {
+		return walkFn(w.path, newWalkedFileStat(w), w.depth)
+	})
+	if err == file.SkipDir || err == file.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// walkedFile is a file visited by walkTree
+type walkedFile struct {
+	// path where the file has been reached
+	path  string
+	entry *inMemoryFile
+	// entry with the symbolic links resolved.
+	// It's the entry itself when links are not followed or the link is broken.
+	target *inMemoryFile
+	depth  int
+	// true if a symbolic link has been followed to reach the entry
+	throughLink bool
+}
+
+// newWalkedFileStat creates a snapshot of the target attributes.
+// The name and the path are the ones of the entry where it has been reached.
+func newWalkedFileStat(w *walkedFile) *inMemoryFileStat {
+	stat := newInMemoryFileStat(w.target)
+	if w.target != w.entry || w.throughLink {
+		stat.name = w.entry.info.Name()
+		stat.absolutePath = w.path
+	}
+	return stat
+}
+
+// doWalkDir visits curr and, if it's a directory, its children in lexical order,
+// without following symbolic links.
+// visited contains the directories already visited and prevents cycles.
+// walkFn must not add or remove directory entries.
+func (fs *MemoryFileSystem) doWalkDir(curr *inMemoryFile, currPath string, depth int, visited map[*inMemoryFile]bool, walkFn walkDirFn) error {
+	root := &walkedFile{path: currPath, entry: curr, target: curr, depth: depth}
+	return fs.walkTree(root, false, visited, func(w *walkedFile) error {
+		return walkFn(w.path, w.entry, w.depth)
+	})
+}
+
+// walkTree visits curr and, if its target is a directory, the children of the target
+// in lexical order. Optionally follow symbolic links.
+// visited contains the directories already visited and prevents cycles.
+// walkFn must not add or remove directory entries.
+func (fs *MemoryFileSystem) walkTree(curr *walkedFile, followLinks bool, visited map[*inMemoryFile]bool, walkFn func(w *walkedFile) error) error {
+	if followLinks {
+		if target, err := fs.resolveSymlink(curr.entry, 0); err == nil {
+			curr.target = target
+		}
+	}
+
+	isDir := curr.target.data.fileType == file.Directory
+	err := walkFn(curr)
+	if err == file.SkipDir && isDir {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !isDir || visited[curr.target] {
+		return nil
+	}
+	visited[curr.target] = true
+
+	throughLink := curr.throughLink || curr.target != curr.entry
+	for _, name := range curr.target.childNames() {
+		child := fs.entry(curr.target, name)
+		err := fs.walkTree(&walkedFile{
+			path:        filepath.Join(curr.path, name),
+			entry:       child,
+			target:      child,
+			depth:       curr.depth + 1,
+			throughLink: throughLink,
+		}, followLinks, visited, walkFn)
+		if err == file.SkipDir {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package memoryfs_test
+
+import (
+	"errors"
+	"fmt"
+	"material/filesystem/filesystem/file"
+	"material/filesystem/filesystem/fserrors"
+	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/memoryfs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func initializeWalkDirTree() (*memoryfs.MemoryFileSystem, file.File, error) {
+	fs := newFileSystem()
+	for _, dir := range []string{"/b/y", "/b/x", "/a", "/c"} {
+		p, _ := fspath.NewFileSystemPath(dir, nil)
+		if _, err := fs.MkdirAll(p); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, name := range []string{"/b/z", "/b/x/file2", "/b/x/file1", "/a/file"} {
+		p, _ := fspath.NewFileSystemPath(name, nil)
+		if _, err := fs.CreateRegularFile(p); err != nil {
+			return nil, nil, err
+		}
+	}
+	return fs, nil, nil
+}
+
+func TestWalkDir(t *testing.T) {
+	errWalk := errors.New("walk error")
+
+	cases := []struct {
+		CaseName   string
+		Path       string
+		FollowLink bool
+		Initialize func() (*memoryfs.MemoryFileSystem, file.File, error)
+		WalkFn     func(path string, stat file.FileStat, depth int) error
+		Assertions func(t *testing.T, res []string, err error)
+	}{
+		{
+			CaseName:   "Walk in lexical order",
+			Path:       "/",
+			Initialize: initializeWalkDirTree,
+			Assertions: func(t *testing.T, res []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/:0", "/a:1", "/a/file:2", "/b:1", "/b/x:2", "/b/x/file1:3", "/b/x/file2:3", "/b/y:2", "/b/z:2", "/c:1"}, res)
+			},
+		},
+		{
+			CaseName: "Walk using relative path",
+			Path:     "x",
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs, _, err := initializeWalkDirTree()
+				if err != nil {
+					return nil, nil, err
+				}
+				p, _ := fspath.NewFileSystemPath("/b", nil)
+				workingDir, err := fs.GetDirectory(p)
+				return fs, workingDir, err
+			},
+			Assertions: func(t *testing.T, res []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/b/x:0", "/b/x/file1:1", "/b/x/file2:1"}, res)
+			},
+		},
+		{
+			CaseName:   "Skip directory",
+			Path:       "/",
+			Initialize: initializeWalkDirTree,
+			WalkFn: func(path string, stat file.FileStat, depth int) error {
+				if path == "/b/x" {
+					return file.SkipDir
+				}
+				return nil
+			},
+			Assertions: func(t *testing.T, res []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/:0", "/a:1", "/a/file:2", "/b:1", "/b/x:2", "/b/y:2", "/b/z:2", "/c:1"}, res)
+			},
+		},
+		{
+			CaseName:   "Skip directory on a file skips the remaining files in the directory",
+			Path:       "/",
+			Initialize: initializeWalkDirTree,
+			WalkFn: func(path string, stat file.FileStat, depth int) error {
+				if path == "/b/x/file1" {
+					return file.SkipDir
+				}
+				return nil
+			},
+			Assertions: func(t *testing.T, res []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/:0", "/a:1", "/a/file:2", "/b:1", "/b/x:2", "/b/x/file1:3", "/b/y:2", "/b/z:2", "/c:1"}, res)
+			},
+		},
+		{
+			CaseName:   "Skip all",
+			Path:       "/",
+			Initialize: initializeWalkDirTree,
+			WalkFn: func(path string, stat file.FileStat, depth int) error {
+				if path == "/b/x" {
+					return file.SkipAll
+				}
+				return nil
+			},
+			Assertions: func(t *testing.T, res []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/:0", "/a:1", "/a/file:2", "/b:1", "/b/x:2"}, res)
+			},
+		},
+		{
+			CaseName:   "Walk function error",
+			Path:       "/",
+			Initialize: initializeWalkDirTree,
+			WalkFn: func(path string, stat file.FileStat, depth int) error {
+				if stat.FileType() == file.RegularFile {
+					return errWalk
+				}
+				return nil
+			},
+			Assertions: func(t *testing.T, res []string, err error) {
+				assert.Equal(t, errWalk, err)
+				assert.Equal(t, []string{"/:0", "/a:1", "/a/file:2"}, res)
+			},
+		},
+		{
+			CaseName:   "Deep tree following links",
+			Path:       "/",
+			FollowLink: true,
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs := newFileSystem()
+				p, _ := fspath.NewFileSystemPath("/"+strings.Repeat("dir/", 50), nil)
+				if _, err := fs.MkdirAll(p); err != nil {
+					return nil, nil, err
+				}
+				return fs, nil, nil
+			},
+			Assertions: func(t *testing.T, res []string, err error) {
+				assert.Nil(t, err)
+				assert.Len(t, res, 51)
+				assert.Equal(t, fmt.Sprintf("/%s:50", strings.Repeat("dir/", 49)+"dir"), res[50])
+			},
+		},
+		{
+			CaseName:   "Follow links with cycles",
+			Path:       "/",
+			FollowLink: true,
+			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
+				fs, _, err := initializeWalkDirTree()
+				if err != nil {
+					return nil, nil, err
+				}
+				p1, _ := fspath.NewFileSystemPath("/b", nil)
+				p2, _ := fspath.NewFileSystemPath("/b/x/loop", nil)
+				if _, err := fs.CreateSymbolicLink(p1, p2); err != nil {
+					return nil, nil, err
+				}
+				p3, _ := fspath.NewFileSystemPath("/a/file", nil)
+				p4, _ := fspath.NewFileSystemPath("/c/link", nil)
+				if _, err := fs.CreateSymbolicLink(p3, p4); err != nil {
+					return nil, nil, err
+				}
+				return fs, nil, nil
+			},
+			Assertions: func(t *testing.T, res []string, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"/:0", "/a:1", "/a/file:2", "/b:1", "/b/x:2", "/b/x/file1:3", "/b/x/file2:3", "/b/x/loop:3", "/b/y:2", "/b/z:2", "/c:1", "/c/link:2"}, res)
+			},
+		},
+		{
+			CaseName:   "Path does not exist",
+			Path:       "/invalid",
+			Initialize: initializeWalkDirTree,
+			Assertions: func(t *testing.T, res []string, err error) {
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
+				assert.Empty(t, res)
+			},
+		},
+	}
+	for _, testCase := range cases {
+		fs, workingDir, err := testCase.Initialize()
+		if err != nil {
+			t.Fatal("error initializing file system")
+		}
+		p, _ := fspath.NewFileSystemPath(testCase.Path, workingDir)
+		res := []string{}
+		err = fs.WalkDir(p, func(path string, stat file.FileStat, depth int) error {
+			res = append(res, fmt.Sprintf("%s:%d", path, depth))
+			if testCase.WalkFn != nil {
+				return testCase.WalkFn(path, stat, depth)
+			}
+			return nil
+		}, testCase.FollowLink)
+		testCase.Assertions(t, res, err)
+	}
+}
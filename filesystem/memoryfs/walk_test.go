@@ -1,6 +1,7 @@
 package memoryfs_test
 
 import (
+	"errors"
 	"material/filesystem/filesystem/file"
 	"material/filesystem/filesystem/fserrors"
 	"material/filesystem/filesystem/fspath"
@@ -26,7 +27,7 @@ func TestWalk(t *testing.T) {
 			Path:       "/",
 			FollowLink: true,
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir/to/walk/skip", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -63,7 +64,7 @@ func TestWalk(t *testing.T) {
 			Path:       "/dir1",
 			FollowLink: true,
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir/to/walk/skip", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -100,7 +101,7 @@ func TestWalk(t *testing.T) {
 			Path:       "/",
 			FollowLink: true,
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/dir/to/walk/", nil)
 				if _, err := fs.MkdirAll(p1); err != nil {
 					return nil, nil, err
@@ -116,7 +117,7 @@ func TestWalk(t *testing.T) {
 			},
 			Assertions: func(t *testing.T, res []string, err error) {
 				assert.NotNil(t, err)
-				assert.Equal(t, fserrors.ErrTooManyLinks, err)
+				assert.True(t, errors.Is(err, fserrors.ErrTooManyLinks))
 			},
 		},
 		{
@@ -124,7 +125,7 @@ func TestWalk(t *testing.T) {
 			Path:       "/",
 			FollowLink: true,
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/dir/to/walk/", nil)
 				if _, err := fs.MkdirAll(p1); err != nil {
 					return nil, nil, err
@@ -151,7 +152,7 @@ func TestWalk(t *testing.T) {
 			Path:       "/",
 			FollowLink: false,
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/dir/to/walk/", nil)
 				if _, err := fs.MkdirAll(p1); err != nil {
 					return nil, nil, err
@@ -21,14 +21,24 @@ func (fs *MemoryFileSystem) GetDirectory(path *fspath.FileSystemPath) (file.File
 	fs.RLock()
 	defer fs.RUnlock()
 
+	dir, err := fs.getDirectory(path)
+	if err != nil {
+		return nil, pathError("chdir", path, err)
+	}
+	return dir, nil
+}
+
+// getDirectory returns the directory located at the specified path.
+// This method should be called only if the caller has already acquired a lock
+func (fs *MemoryFileSystem) getDirectory(path *fspath.FileSystemPath) (*inMemoryFile, error) {
 	// Find path starting point
 	dir, err := fs.traverseToBase(path)
 	if err != nil {
 		return nil, err
 	}
 
-	if dir.info.fileType != file.Directory {
-		return nil, fserrors.ErrInvalidFileType
+	if dir.data.fileType != file.Directory {
+		return nil, fserrors.ErrNotDir
 	}
 
 	return dir, nil
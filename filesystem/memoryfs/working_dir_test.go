@@ -22,7 +22,7 @@ func TestGetDirectory(t *testing.T) {
 			CaseName: "Change working directory using absolute path - absolute path, work dir nil",
 			Path:     "/dir5/dir6",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -49,7 +49,7 @@ func TestGetDirectory(t *testing.T) {
 			CaseName: "Change working directory using absolute path - no such directory",
 			Path:     "/dir5/dir6/dir8",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -68,7 +68,7 @@ func TestGetDirectory(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 				assert.Nil(t, res)
 			},
 		},
@@ -76,7 +76,7 @@ func TestGetDirectory(t *testing.T) {
 			CaseName: "Change working directory using absolute path - regular file",
 			Path:     "/dir1/dir2/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -99,7 +99,7 @@ func TestGetDirectory(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrInvalidFileType)
+				assert.True(t, errors.Is(err, fserrors.ErrNotDir))
 				assert.Nil(t, res)
 			},
 		},
@@ -107,7 +107,7 @@ func TestGetDirectory(t *testing.T) {
 			CaseName: "Change working directory - relative path (../../), work dir not nil",
 			Path:     "../../",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -134,7 +134,7 @@ func TestGetDirectory(t *testing.T) {
 			CaseName: "Change working directory - relative path (../../././.././dir3/../dir1/dir2/), work dir not nil",
 			Path:     "../../././.././dir3/../dir1/dir2/",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -161,7 +161,7 @@ func TestGetDirectory(t *testing.T) {
 			CaseName: "Change working directory - relative path to before root, work dir not nil",
 			Path:     "../../../../../../../../..",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -188,7 +188,7 @@ func TestGetDirectory(t *testing.T) {
 			CaseName: "Change working directory using relative path - no such directory",
 			Path:     "../../dir3",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
 				if _, err := fs.MkdirAll(p); err != nil {
 					return nil, nil, err
@@ -208,7 +208,7 @@ func TestGetDirectory(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrNotExist)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 				assert.Nil(t, res)
 			},
 		},
@@ -216,7 +216,7 @@ func TestGetDirectory(t *testing.T) {
 			CaseName: "Change working directory using relative path - regular file",
 			Path:     "file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
 				workingDir, err := fs.MkdirAll(p)
 				if err != nil {
@@ -240,7 +240,7 @@ func TestGetDirectory(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrInvalidFileType)
+				assert.True(t, errors.Is(err, fserrors.ErrNotDir))
 				assert.Nil(t, res)
 			},
 		},
@@ -248,7 +248,7 @@ func TestGetDirectory(t *testing.T) {
 			CaseName: "Working directory previously deleted",
 			Path:     "file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
 				workingDir, err := fs.MkdirAll(p)
 				if err != nil {
@@ -276,7 +276,7 @@ func TestGetDirectory(t *testing.T) {
 				assert.NotNil(t, err)
 				target := &fserrors.FileSystemError{}
 				assert.True(t, errors.As(err, &target))
-				assert.Equal(t, err, fserrors.ErrInvalidWorkingDirectory)
+				assert.True(t, errors.Is(err, fserrors.ErrInvalidWorkingDirectory))
 				assert.Nil(t, res)
 			},
 		},
@@ -284,7 +284,7 @@ func TestGetDirectory(t *testing.T) {
 			CaseName: "Get directory should follow symlink - relative path (../../), work dir not nil",
 			Path:     "../../",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/dir1/dir2", nil)
 				if _, err := fs.MkdirAll(p1); err != nil {
 					return nil, nil, err
@@ -339,7 +339,7 @@ func TestDefaultWorkingDirectory(t *testing.T) {
 		},
 	}
 	for _, testCase := range cases {
-		fs := memoryfs.NewMemoryFileSystem()
+		fs := newFileSystem()
 		dir := fs.DefaultWorkingDirectory()
 		testCase.Assertions(t, dir)
 	}
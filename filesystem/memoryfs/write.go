@@ -1,9 +1,11 @@
 package memoryfs
 
 import (
+	"context"
 	"material/filesystem/filesystem/file"
 	"material/filesystem/filesystem/fserrors"
 	"material/filesystem/filesystem/fspath"
+	"material/filesystem/filesystem/fsvirtual"
 )
 
 // AppendAll writes data to the named file, creating it if necessary along
@@ -16,45 +18,88 @@ import (
 func (fs *MemoryFileSystem) AppendAll(path *fspath.FileSystemPath, content []byte) error {
 	fs.Lock()
 
-	parent, err := fs.traverseDirsAndCreateParentDirs(path)
+	fileToWrite, err := fs.fileToAppend(path)
 	if err != nil {
 		fs.Unlock()
-		return err
+		return pathError("append", path, err)
 	}
 
-	fileToWrite, err := fs.createFileToWriteIfMissing(parent, path.Base())
-	if err != nil {
+	if err := checkRegularFile(fileToWrite); err != nil {
 		fs.Unlock()
-		return err
+		return pathError("append", path, err)
 	}
 
-	descriptor, err := fs.doOpen(fileToWrite)
-	fs.Unlock()
+	descriptor, err := fs.openAndUnlock(context.Background(), fileToWrite, file.OpenWrite, "")
 	if err != nil {
-		return err
+		return pathError("append", path, err)
 	}
 	defer fs.Close(descriptor)
 
 	_, err = fs.doWrite(descriptor, content, func(fd *fileDescriptor) (int, error) {
-		return fd.WriteAt(content, fd.data.Size())
+		offset := fd.size()
+		if offset == fsvirtual.Unbounded {
+			offset = 0
+		}
+		return fd.WriteAt(content, offset)
 	})
 
 	if err != nil {
-		return err
+		return pathError("append", path, err)
 	}
 
 	return nil
 }
 
+// This method should be called only if the caller has already acquired a lock.
+// The content is written while holding the lock, so it's never visible half written.
+func (fs *MemoryFileSystem) appendAllLockFree(path *fspath.FileSystemPath, content []byte) error {
+	fileToWrite, err := fs.fileToAppend(path)
+	if err != nil {
+		return err
+	}
+	if err := checkRegularFile(fileToWrite); err != nil {
+		return err
+	}
+	// writes to virtual files can't be undone
+	if fileToWrite.isVirtual {
+		return fserrors.ErrOperationNotSupported
+	}
+
+	data := fileToWrite.data
+	fs.journal.saveInode(data)
+	data.open()
+	defer data.close(fs.versionPolicy)
+
+	data.Lock()
+	defer data.Unlock()
+	_, err = data.write(content, data.Size())
+	return err
+}
+
+// fileToAppend returns the file located at path, creating it
+// along with any missing parent directories
+func (fs *MemoryFileSystem) fileToAppend(path *fspath.FileSystemPath) (*inMemoryFile, error) {
+	parent, err := fs.traverseDirsAndCreateParentDirs(path)
+	if err != nil {
+		return nil, err
+	}
+	return fs.createFileToWriteIfMissing(parent, path.Base())
+}
+
 // Write writes content to the file and
 // returns the number of bytes written.
+// Writing a fifo waits while its buffer is full.
 //
 // Returns an error when:
 // - the file is not open
+// - the file is not open for writing
+// - the fifo has no readers
+// - the fifo buffer is full in non-blocking mode
 func (fs *MemoryFileSystem) Write(descriptor string, content []byte) (int, error) {
-	return fs.doWrite(descriptor, content, func(fd *fileDescriptor) (int, error) {
+	nWritten, err := fs.doWrite(descriptor, content, func(fd *fileDescriptor) (int, error) {
 		return fd.Write(content)
 	})
+	return nWritten, nameError("write", descriptor, err)
 }
 
 // WriteAt writes content to the file starting at offset
@@ -62,14 +107,17 @@ func (fs *MemoryFileSystem) Write(descriptor string, content []byte) (int, error
 //
 // Returns an error when:
 // - the file is not open
+// - the file is not open for writing
+// - the file is a fifo
 func (fs *MemoryFileSystem) WriteAt(descriptor string, content []byte, offset int) (int, error) {
 	if offset < 0 {
-		return 0, fserrors.ErrInvalid
+		return 0, nameError("write", descriptor, fserrors.ErrInvalid)
 	}
 
-	return fs.doWrite(descriptor, content, func(fd *fileDescriptor) (int, error) {
+	nWritten, err := fs.doWrite(descriptor, content, func(fd *fileDescriptor) (int, error) {
 		return fd.WriteAt(content, offset)
 	})
+	return nWritten, nameError("write", descriptor, err)
 }
 
 func (fs *MemoryFileSystem) doWrite(fileDescriptor string, content []byte, writeFn func(fd *fileDescriptor) (int, error)) (int, error) {
@@ -82,6 +130,12 @@ func (fs *MemoryFileSystem) doWrite(fileDescriptor string, content []byte, write
 		return 0, fserrors.ErrNotOpen
 	}
 
+	// Fifos and virtual files have their own locks, a fifo may wait for the readers
+	if fd.data.pipe != nil || fd.handle != nil {
+		fs.openFiles.RUnlock()
+		return writeFn(fd)
+	}
+
 	// Write lock file
 	fd.data.Lock()
 	defer fd.data.Unlock()
@@ -1,6 +1,7 @@
 package memoryfs_test
 
 import (
+	"errors"
 	"material/filesystem/filesystem/file"
 	"material/filesystem/filesystem/fserrors"
 	"material/filesystem/filesystem/fspath"
@@ -21,7 +22,7 @@ func TestAppendAll(t *testing.T) {
 			CaseName: "Append to existing empty file - absolute path",
 			Path:     "/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -37,7 +38,7 @@ func TestAppendAll(t *testing.T) {
 			CaseName: "Append to existing not empty empty file - absolute path",
 			Path:     "/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if err := fs.AppendAll(p, []byte("Ciao mondo!")); err != nil {
 					return nil, nil, err
@@ -53,7 +54,7 @@ func TestAppendAll(t *testing.T) {
 			CaseName: "Append to new file - absolute path",
 			Path:     "/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				return fs, nil, nil
 			},
 			Assertions: func(t *testing.T, data []byte, err error) {
@@ -65,7 +66,7 @@ func TestAppendAll(t *testing.T) {
 			CaseName: "Append to new file and create intermediate directories - absolute path",
 			Path:     "/dir1/dir2/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				return fs, nil, nil
 			},
 			Assertions: func(t *testing.T, data []byte, err error) {
@@ -77,7 +78,7 @@ func TestAppendAll(t *testing.T) {
 			CaseName: "Append to directory - absolute path",
 			Path:     "/dir1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -86,7 +87,7 @@ func TestAppendAll(t *testing.T) {
 			},
 			Assertions: func(t *testing.T, data []byte, err error) {
 				assert.NotNil(t, err)
-				assert.Equal(t, err, fserrors.ErrInvalidFileType)
+				assert.True(t, errors.Is(err, fserrors.ErrIsDir))
 			},
 		},
 
@@ -94,7 +95,7 @@ func TestAppendAll(t *testing.T) {
 			CaseName: "Append to existing file - relative path",
 			Path:     "file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -110,7 +111,7 @@ func TestAppendAll(t *testing.T) {
 			CaseName: "Append to new file - relative path",
 			Path:     "./file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				return fs, fs.DefaultWorkingDirectory(), nil
 			},
 			Assertions: func(t *testing.T, data []byte, err error) {
@@ -122,7 +123,7 @@ func TestAppendAll(t *testing.T) {
 			CaseName: "Append to new file and create intermediate directories - relative path",
 			Path:     "dir1/dir2/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				return fs, fs.DefaultWorkingDirectory(), nil
 			},
 			Assertions: func(t *testing.T, data []byte, err error) {
@@ -134,7 +135,7 @@ func TestAppendAll(t *testing.T) {
 			CaseName: "Append to directory - relative path",
 			Path:     "dir1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -143,14 +144,14 @@ func TestAppendAll(t *testing.T) {
 			},
 			Assertions: func(t *testing.T, data []byte, err error) {
 				assert.NotNil(t, err)
-				assert.Equal(t, err, fserrors.ErrInvalidFileType)
+				assert.True(t, errors.Is(err, fserrors.ErrIsDir))
 			},
 		},
 		{
 			CaseName: "Append to symlink - absolute path",
 			Path:     "/file1-link",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p1, _ := fspath.NewFileSystemPath("/file1", nil)
 
 				if _, err := fs.CreateRegularFile(p1); err != nil {
@@ -200,7 +201,7 @@ func TestWriteAt(t *testing.T) {
 			Position: 0,
 			Text:     []byte("Hello world!"),
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -221,7 +222,7 @@ func TestWriteAt(t *testing.T) {
 			Position: 0,
 			Text:     []byte("Hello universe! "),
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -245,7 +246,7 @@ func TestWriteAt(t *testing.T) {
 			Position: 6,
 			Text:     []byte("universe! "),
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -269,7 +270,7 @@ func TestWriteAt(t *testing.T) {
 			Position: 20,
 			Text:     []byte("Hello universe!"),
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -296,7 +297,7 @@ func TestWriteAt(t *testing.T) {
 			Position: 10,
 			Text:     []byte("Hello world!"),
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -341,7 +342,7 @@ func TestWriteAtClosedFile(t *testing.T) {
 			CaseName: "Write to closed file should fail - absolute path",
 			Path:     "/file1",
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -350,7 +351,7 @@ func TestWriteAtClosedFile(t *testing.T) {
 			},
 			Assertions: func(t *testing.T, b int, err error) {
 				assert.NotNil(t, err)
-				assert.Equal(t, fserrors.ErrNotOpen, err)
+				assert.True(t, errors.Is(err, fserrors.ErrNotOpen))
 				assert.Equal(t, 0, b)
 			},
 		},
@@ -390,7 +391,7 @@ func TestWriteAtMovedOrRemovedFile(t *testing.T) {
 				return err
 			},
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -416,7 +417,7 @@ func TestWriteAtMovedOrRemovedFile(t *testing.T) {
 				return err
 			},
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/dir1", nil)
 				if _, err := fs.Mkdir(p); err != nil {
 					return nil, nil, err
@@ -449,7 +450,7 @@ func TestWriteAtMovedOrRemovedFile(t *testing.T) {
 				return err
 			},
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -462,7 +463,7 @@ func TestWriteAtMovedOrRemovedFile(t *testing.T) {
 
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				_, err = fs.ReadAll(p)
-				assert.Equal(t, fserrors.ErrNotExist, err)
+				assert.True(t, errors.Is(err, fserrors.ErrNotExist))
 			},
 		},
 	}
@@ -498,7 +499,7 @@ func TestWrite(t *testing.T) {
 			Path:     "/file1",
 			Text:     []byte("Hello world!"),
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -518,7 +519,7 @@ func TestWrite(t *testing.T) {
 			Path:     "/file1",
 			Text:     []byte("Hello universe! "),
 			Initialize: func() (*memoryfs.MemoryFileSystem, file.File, error) {
-				fs := memoryfs.NewMemoryFileSystem()
+				fs := newFileSystem()
 				p, _ := fspath.NewFileSystemPath("/file1", nil)
 				if _, err := fs.CreateRegularFile(p); err != nil {
 					return nil, nil, err
@@ -554,7 +555,7 @@ func TestWrite(t *testing.T) {
 }
 
 func TestWriteInChuncks(t *testing.T) {
-	fs := memoryfs.NewMemoryFileSystem()
+	fs := newFileSystem()
 	p, _ := fspath.NewFileSystemPath("/file1", nil)
 	if _, err := fs.CreateRegularFile(p); err != nil {
 		t.Fatal("error initializing file system")